@@ -1,10 +1,93 @@
 package cfg
 
+import "time"
+
 type EnvConfiguration struct {
-	HTTP_PORT         string
-	LOG_LEVEL         string
-	DATABASE_HOST     string
-	DATABASE_NAME     string
-	DATABASE_PASSWORD string
-	DATABASE_USERNAME string
+	HTTP_PORT                string
+	HTTP_READ_TIMEOUT        time.Duration
+	HTTP_READ_HEADER_TIMEOUT time.Duration
+	HTTP_WRITE_TIMEOUT       time.Duration
+	HTTP_IDLE_TIMEOUT        time.Duration
+	// HTTP_SHUTDOWN_DELAY is how long the readiness probe fails before the server stops accepting connections
+	HTTP_SHUTDOWN_DELAY time.Duration
+	// HTTP_SHUTDOWN_TIMEOUT bounds the wait for the requests in flight
+	HTTP_SHUTDOWN_TIMEOUT time.Duration
+	// HTTP_READINESS_TIMEOUT bounds every check of the readiness probe
+	HTTP_READINESS_TIMEOUT time.Duration
+	// HTTP_VALIDATE_RESPONSES logs the responses not matching the OpenAPI specification, the requests are always validated
+	HTTP_VALIDATE_RESPONSES bool
+	LOG_LEVEL               string
+	// LOG_ACCESS_SAMPLE_PERCENT is the share of the successful requests written to the access log
+	LOG_ACCESS_SAMPLE_PERCENT int
+	DATABASE_DRIVER           string
+	DATABASE_HOST             string
+	DATABASE_NAME             string
+	DATABASE_PASSWORD         string
+	DATABASE_USERNAME         string
+	DATABASE_PORT             string
+	// DATABASE_SSLMODE and the certificate paths follow the libpq sslmode, sslrootcert, sslcert and sslkey parameters
+	DATABASE_SSLMODE           string
+	DATABASE_SSLROOTCERT       string
+	DATABASE_SSLCERT           string
+	DATABASE_SSLKEY            string
+	DATABASE_CONNECT_TIMEOUT   time.Duration
+	DATABASE_CONNECT_ATTEMPTS  int
+	DATABASE_MAX_OPEN_CONNS    int
+	DATABASE_MAX_IDLE_CONNS    int
+	DATABASE_CONN_MAX_LIFETIME time.Duration
+	DATABASE_STATEMENT_TIMEOUT time.Duration
+	// DATABASE_MIGRATE_ON_STARTUP applies the pending migrations before serving requests
+	DATABASE_MIGRATE_ON_STARTUP bool
+	// TRACING_EXPORTER is none, stdout or otlp, the OTLP exporter sends the spans over HTTP to TRACING_OTLP_ENDPOINT
+	TRACING_EXPORTER      string
+	TRACING_OTLP_ENDPOINT string
+	TRACING_OTLP_INSECURE bool
+	// SLA_CHECK_INTERVAL is how often the breaches of the SLA of the issues are flagged, 0 disables the checker
+	SLA_CHECK_INTERVAL time.Duration
+}
+
+// Setting documents one field of EnvConfiguration.
+// Key is both the environment variable and the key of the configuration file,
+// the command-line flag is Key in lower case with dashes.
+type Setting struct {
+	Key         string
+	Default     interface{}
+	Description string
+	Secret      bool
+}
+
+// Settings lists every field of EnvConfiguration, in the order they are printed
+var Settings = []Setting{
+	{Key: "HTTP_PORT", Default: "8080", Description: "port the HTTP server listens on"},
+	{Key: "HTTP_READ_TIMEOUT", Default: 30 * time.Second, Description: "maximum duration for reading a whole request, body included"},
+	{Key: "HTTP_READ_HEADER_TIMEOUT", Default: 10 * time.Second, Description: "maximum duration for reading the request headers"},
+	{Key: "HTTP_WRITE_TIMEOUT", Default: 30 * time.Second, Description: "maximum duration before timing out the write of the response"},
+	{Key: "HTTP_IDLE_TIMEOUT", Default: 120 * time.Second, Description: "maximum time a keep-alive connection waits for the next request"},
+	{Key: "HTTP_SHUTDOWN_DELAY", Default: 5 * time.Second, Description: "on SIGTERM or SIGINT, how long the readiness probe fails before the server stops accepting connections"},
+	{Key: "HTTP_READINESS_TIMEOUT", Default: 2 * time.Second, Description: "timeout of every dependency check of the readiness probe"},
+	{Key: "HTTP_SHUTDOWN_TIMEOUT", Default: 30 * time.Second, Description: "on SIGTERM or SIGINT, how long the requests in flight are waited for"},
+	{Key: "HTTP_VALIDATE_RESPONSES", Default: false, Description: "log a warning for every response not matching the OpenAPI specification"},
+	{Key: "LOG_LEVEL", Default: "info", Description: "one of trace, debug, info, warning, error, fatal, panic"},
+	{Key: "LOG_ACCESS_SAMPLE_PERCENT", Default: 100, Description: "percentage of the requests answered with a status below 400 written to the access log"},
+	{Key: "DATABASE_DRIVER", Default: "postgres", Description: "storage backend, postgres or sqlite"},
+	{Key: "DATABASE_HOST", Default: "localhost", Description: "postgres host"},
+	{Key: "DATABASE_NAME", Default: "issue", Description: "postgres database name, or the path of the sqlite database file"},
+	{Key: "DATABASE_USERNAME", Default: "postgres", Description: "postgres user"},
+	{Key: "DATABASE_PASSWORD", Default: "", Description: "postgres password", Secret: true},
+	{Key: "DATABASE_PORT", Default: "5432", Description: "postgres port"},
+	{Key: "DATABASE_SSLMODE", Default: "prefer", Description: "one of disable, allow, prefer, require, verify-ca, verify-full"},
+	{Key: "DATABASE_SSLROOTCERT", Default: "", Description: "path of the certificate authority used to verify the server"},
+	{Key: "DATABASE_SSLCERT", Default: "", Description: "path of the client certificate"},
+	{Key: "DATABASE_SSLKEY", Default: "", Description: "path of the client certificate key"},
+	{Key: "DATABASE_CONNECT_TIMEOUT", Default: 5 * time.Second, Description: "timeout of a single connection attempt to postgres"},
+	{Key: "DATABASE_CONNECT_ATTEMPTS", Default: 5, Description: "connection attempts on startup, with an exponential backoff between them"},
+	{Key: "DATABASE_MAX_OPEN_CONNS", Default: 10, Description: "maximum number of open connections, 0 means unlimited"},
+	{Key: "DATABASE_MAX_IDLE_CONNS", Default: 5, Description: "maximum number of idle connections, 0 keeps the database/sql default"},
+	{Key: "DATABASE_CONN_MAX_LIFETIME", Default: 30 * time.Minute, Description: "maximum time a connection is reused, 0 means forever"},
+	{Key: "DATABASE_STATEMENT_TIMEOUT", Default: time.Duration(0), Description: "postgres statement_timeout, 0 means no timeout"},
+	{Key: "DATABASE_MIGRATE_ON_STARTUP", Default: false, Description: "apply the pending migrations before serving requests"},
+	{Key: "TRACING_EXPORTER", Default: "none", Description: "where the spans are sent, one of none, stdout, otlp"},
+	{Key: "TRACING_OTLP_ENDPOINT", Default: "localhost:4318", Description: "host and port of the OTLP/HTTP collector"},
+	{Key: "TRACING_OTLP_INSECURE", Default: true, Description: "send the spans to the collector without TLS"},
+	{Key: "SLA_CHECK_INTERVAL", Default: time.Minute, Description: "how often the SLA breaches of the issues are flagged, 0 disables the checker"},
 }
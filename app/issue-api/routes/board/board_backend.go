@@ -0,0 +1,242 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"sort"
+	"strings"
+)
+
+// getProjectBoard returns the board of the project, an error response when the project or its board does not exist
+func getProjectBoard(ctx context.Context, stores models.Stores, projectId int) (models.Board, error) {
+	if _, err := internal.GetProjectById(ctx, stores, projectId); err != nil {
+		return models.Board{}, err
+	}
+	board, err := stores.Boards.Get(ctx, projectId)
+	if errors.Is(err, internal.ErrNotFound) {
+		return models.Board{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Project with id \"%d\" has no board", projectId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return models.Board{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return board, nil
+}
+
+// validateColumns checks that the names of the columns are unique and that a status is in a single column
+func validateColumns(columns []models.BoardColumn) error {
+	names := map[string]bool{}
+	statusColumns := map[string]string{}
+	for _, column := range columns {
+		if names[strings.ToLower(column.Name)] {
+			return &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The board has two columns named \"%s\"", column.Name),
+				ErrorCode:    400,
+			}
+		}
+		names[strings.ToLower(column.Name)] = true
+		for _, status := range column.Statuses {
+			if other, ok := statusColumns[strings.ToLower(status)]; ok {
+				return &models.ErrorResponse{
+					ErrorMessage: fmt.Sprintf("The status \"%s\" is in the columns \"%s\" and \"%s\"", status, other, column.Name),
+					ErrorCode:    400,
+				}
+			}
+			statusColumns[strings.ToLower(status)] = column.Name
+		}
+	}
+	return nil
+}
+
+func saveBoard(ctx context.Context, stores models.Stores, board models.Board) error {
+	if _, err := internal.GetProjectById(ctx, stores, board.ProjectID); err != nil {
+		return err
+	}
+	if err := validateColumns(board.Columns); err != nil {
+		return err
+	}
+
+	err := stores.Boards.Save(ctx, &board)
+	if internal.IsDuplicateKeyError(err) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The board of project \"%d\" is being created by another request", board.ProjectID),
+			ErrorCode:    409,
+		}
+	}
+	if err != nil {
+		return &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return nil
+}
+
+// getSwimlaneName returns the name of the swimlane of the issue, empty without swimlanes
+func getSwimlaneName(swimlane string, issue models.Issue) string {
+	switch swimlane {
+	case models.SWIMLANE_ASSIGNEE:
+		return issue.Assignee
+	case models.SWIMLANE_TYPE:
+		return issue.Type
+	case models.SWIMLANE_PRIORITY:
+		return issue.Priority
+	case models.SWIMLANE_EPIC:
+		return issue.Epic
+	}
+	return ""
+}
+
+func getBoard(ctx context.Context, stores models.Stores, projectId int) (models.BoardResponse, error) {
+	board, err := getProjectBoard(ctx, stores, projectId)
+	if err != nil {
+		return models.BoardResponse{}, err
+	}
+	issues, err := stores.Issues.ListByProject(ctx, projectId, models.IssueFilter{})
+	if err != nil {
+		return models.BoardResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	sprints, err := stores.Sprints.ListByProject(ctx, projectId, models.Page{})
+	if err != nil {
+		return models.BoardResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return buildBoard(board, selectBoardIssues(board, issues, sprints)), nil
+}
+
+// selectBoardIssues keeps the issues in the scope of the board, in their order
+func selectBoardIssues(board models.Board, issues []models.Issue, sprints []models.Sprint) []models.Issue {
+	sprintsById := map[int]*models.Sprint{}
+	for index := range sprints {
+		sprintsById[int(sprints[index].ID)] = &sprints[index]
+	}
+	selected := []models.Issue{}
+	for _, issue := range issues {
+		if board.InScope(sprintsById[issue.SprintID]) {
+			selected = append(selected, issue)
+		}
+	}
+	return selected
+}
+
+// buildBoard places the issues, ordered by rank, in the cells of their swimlane and of the column of their status.
+// The swimlanes are sorted by name, from P0 to P4 by priority, the swimlane of the issues without assignee, priority or epic comes last.
+func buildBoard(board models.Board, issues []models.Issue) models.BoardResponse {
+	response := models.BoardResponse{
+		ProjectID: board.ProjectID,
+		Swimlane:  board.Swimlane,
+		Scope:     board.Scope,
+		Columns:   []models.BoardColumnResponse{},
+		Swimlanes: []models.BoardSwimlaneResponse{},
+	}
+	for _, column := range board.Columns {
+		response.Columns = append(response.Columns, models.BoardColumnResponse{BoardColumn: column})
+	}
+
+	swimlaneIndexes := map[string]int{}
+	for _, issue := range issues {
+		columnIndex := -1
+		for index, column := range board.Columns {
+			if column.HasStatus(issue.Status) {
+				columnIndex = index
+				break
+			}
+		}
+		if columnIndex == -1 {
+			response.UnmappedIssues++
+			continue
+		}
+		response.Columns[columnIndex].Issues++
+
+		name := getSwimlaneName(board.Swimlane, issue)
+		swimlaneIndex, ok := swimlaneIndexes[name]
+		if !ok {
+			swimlane := models.BoardSwimlaneResponse{Name: name, Cells: []models.BoardCellResponse{}}
+			for _, column := range board.Columns {
+				swimlane.Cells = append(swimlane.Cells, models.BoardCellResponse{Column: column.Name, Issues: []models.GetIssueResponse{}})
+			}
+			swimlaneIndex = len(response.Swimlanes)
+			swimlaneIndexes[name] = swimlaneIndex
+			response.Swimlanes = append(response.Swimlanes, swimlane)
+		}
+		cell := &response.Swimlanes[swimlaneIndex].Cells[columnIndex]
+		cell.Issues = append(cell.Issues, issue.GetIssueResponseFromIssue())
+	}
+
+	for index, column := range response.Columns {
+		response.Columns[index].OverLimit = column.WipLimit != nil && column.Issues > *column.WipLimit
+	}
+	sort.SliceStable(response.Swimlanes, func(i, j int) bool {
+		first, second := response.Swimlanes[i].Name, response.Swimlanes[j].Name
+		if first == "" || second == "" {
+			return second == "" && first != ""
+		}
+		return first < second
+	})
+	return response
+}
+
+// moveBoardIssue sets the status of the issue to a status of the column, and ranks it next to another issue
+func moveBoardIssue(ctx context.Context, stores models.Stores, projectId int, issueId uint, request models.MoveBoardIssueRequest) error {
+	board, err := getProjectBoard(ctx, stores, projectId)
+	if err != nil {
+		return err
+	}
+	var column *models.BoardColumn
+	for index := range board.Columns {
+		if strings.EqualFold(board.Columns[index].Name, request.Column) {
+			column = &board.Columns[index]
+			break
+		}
+	}
+	if column == nil {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The board of project \"%d\" has no column \"%s\"", projectId, request.Column),
+			ErrorCode:    400,
+		}
+	}
+	if request.Status != "" && !column.HasStatus(request.Status) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The column \"%s\" has no status \"%s\"", column.Name, request.Status),
+			ErrorCode:    400,
+		}
+	}
+
+	rank, err := internal.GetMoveRank(ctx, stores, projectId, issueId, request.AfterIssueID, request.BeforeIssueID)
+	if err != nil {
+		return err
+	}
+	err = stores.Issues.MoveOnBoard(ctx, projectId, issueId, *column, request.Status, rank)
+	if errors.Is(err, internal.ErrWipLimit) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The column \"%s\" already holds its limit of %d issues", column.Name, *column.WipLimit),
+			ErrorCode:    409,
+		}
+	}
+	if errors.Is(err, internal.ErrNotFound) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" is not on the board of project \"%d\"", issueId, projectId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package board
+
+import (
+	"context"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBoard(testCase *testing.T) {
+	wipLimit := 1
+	board := models.Board{
+		ProjectID: 1,
+		Swimlane:  models.SWIMLANE_ASSIGNEE,
+		Columns: []models.BoardColumn{
+			{Name: "To Do", Statuses: []string{"", "To Do"}},
+			{Name: "Doing", Statuses: []string{"In Progress", "Review"}, WipLimit: &wipLimit},
+			{Name: "Done", Statuses: []string{"Done"}},
+		},
+	}
+	issues := []models.Issue{
+		{ID: 3, Status: "review", Assignee: "bob", Rank: "a"},
+		{ID: 1, Status: "In Progress", Assignee: "alice", Rank: "b"},
+		{ID: 2, Status: "", Rank: "c"},
+		{ID: 4, Status: "Blocked", Assignee: "alice", Rank: "d"},
+		{ID: 5, Status: "Done", Assignee: "bob", Rank: "e"},
+		{ID: 6, Status: "In Progress", Assignee: "bob", Rank: "f"},
+	}
+
+	testCase.Run("the issues in the cells of their swimlane and of their column", func(t *testing.T) {
+		response := buildBoard(board, issues)
+
+		require.Equal(t, 1, response.UnmappedIssues)
+		require.Equal(t, []int{1, 3, 1}, []int{response.Columns[0].Issues, response.Columns[1].Issues, response.Columns[2].Issues})
+		require.True(t, response.Columns[1].OverLimit)
+		require.False(t, response.Columns[0].OverLimit)
+
+		require.Equal(t, 3, len(response.Swimlanes))
+		require.Equal(t, "alice", response.Swimlanes[0].Name)
+		require.Equal(t, "bob", response.Swimlanes[1].Name)
+		require.Equal(t, "", response.Swimlanes[2].Name)
+		bobDoing := response.Swimlanes[1].Cells[1]
+		require.Equal(t, "Doing", bobDoing.Column)
+		require.Equal(t, []uint{3, 6}, []uint{bobDoing.Issues[0].ID, bobDoing.Issues[1].ID})
+		require.Equal(t, uint(2), response.Swimlanes[2].Cells[0].Issues[0].ID)
+		require.Equal(t, []models.GetIssueResponse{}, response.Swimlanes[2].Cells[1].Issues)
+	})
+
+	testCase.Run("a single swimlane without grouping", func(t *testing.T) {
+		board := board
+		board.Swimlane = models.SWIMLANE_NONE
+		response := buildBoard(board, issues)
+
+		require.Equal(t, 1, len(response.Swimlanes))
+		require.Equal(t, 3, len(response.Swimlanes[0].Cells[1].Issues))
+	})
+
+	testCase.Run("the swimlanes of the epics", func(t *testing.T) {
+		board := board
+		board.Swimlane = models.SWIMLANE_EPIC
+		epicIssues := []models.Issue{
+			{ID: 1, Status: "To Do", Epic: "Login", Rank: "a"},
+			{ID: 2, Status: "In Progress", Rank: "b"},
+			{ID: 3, Status: "Done", Epic: "Login", Rank: "c"},
+		}
+		response := buildBoard(board, epicIssues)
+
+		require.Equal(t, 2, len(response.Swimlanes))
+		require.Equal(t, "Login", response.Swimlanes[0].Name)
+		require.Equal(t, uint(1), response.Swimlanes[0].Cells[0].Issues[0].ID)
+		require.Equal(t, uint(3), response.Swimlanes[0].Cells[2].Issues[0].ID)
+		require.Equal(t, "", response.Swimlanes[1].Name)
+		require.Equal(t, uint(2), response.Swimlanes[1].Cells[1].Issues[0].ID)
+	})
+}
+
+func TestBoard(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+	wipLimit := 2
+	columns := []models.BoardColumn{
+		{Name: "To Do", Statuses: []string{"To Do"}},
+		{Name: "In Progress", Statuses: []string{"In Progress", "Review"}, WipLimit: &wipLimit},
+		{Name: "Done", Statuses: []string{"Done"}},
+	}
+
+	testCase.Run("save and replace the board", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, stores.Sprints.Start(ctx, int(projectId), int(sprintId), time.Now()))
+
+		_, err := getBoard(ctx, stores, int(projectId))
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Project with id \"1\" has no board", ErrorCode: 404}, err)
+
+		require.Equal(t, nil, saveBoard(ctx, stores, models.Board{ProjectID: int(projectId), Columns: columns}))
+		require.Equal(t, nil, saveBoard(ctx, stores, models.Board{ProjectID: int(projectId), Swimlane: models.SWIMLANE_TYPE, Columns: columns[:2]}))
+
+		board, err := getBoard(ctx, stores, int(projectId))
+		require.Equal(t, nil, err)
+		require.Equal(t, models.SWIMLANE_TYPE, board.Swimlane)
+		require.Equal(t, 2, len(board.Columns))
+		require.Equal(t, &wipLimit, board.Columns[1].WipLimit)
+		require.Equal(t, "Task", board.Swimlanes[0].Name)
+		require.Equal(t, 1, len(board.Swimlanes[0].Cells[0].Issues))
+	})
+
+	testCase.Run("saveBoard return error", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := int(internal.CreateTestProject(stores))
+
+		err := saveBoard(ctx, stores, models.Board{ProjectID: 100, Columns: columns})
+		require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode)
+
+		err = saveBoard(ctx, stores, models.Board{ProjectID: projectId, Columns: append(columns, models.BoardColumn{Name: "done", Statuses: []string{"Closed"}})})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The board has two columns named \"done\"", ErrorCode: 400}, err)
+
+		err = saveBoard(ctx, stores, models.Board{ProjectID: projectId, Columns: append(columns, models.BoardColumn{Name: "Closed", Statuses: []string{"done"}})})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The status \"done\" is in the columns \"Done\" and \"Closed\"", ErrorCode: 400}, err)
+	})
+
+	testCase.Run("move the issues within the WIP limits", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueIds := []uint{}
+		for index := 0; index < 4; index++ {
+			issueIds = append(issueIds, internal.CreateTestIssue(stores, int(projectId), int(sprintId)))
+		}
+		require.Equal(t, nil, stores.Sprints.Start(ctx, int(projectId), int(sprintId), time.Now()))
+		require.Equal(t, nil, saveBoard(ctx, stores, models.Board{ProjectID: int(projectId), Columns: columns}))
+
+		require.Equal(t, nil, moveBoardIssue(ctx, stores, int(projectId), issueIds[0], models.MoveBoardIssueRequest{Column: "In Progress"}))
+		require.Equal(t, nil, moveBoardIssue(ctx, stores, int(projectId), issueIds[1], models.MoveBoardIssueRequest{Column: "in progress", Status: "Review"}))
+		err := moveBoardIssue(ctx, stores, int(projectId), issueIds[2], models.MoveBoardIssueRequest{Column: "In Progress"})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The column \"In Progress\" already holds its limit of 2 issues", ErrorCode: 409}, err)
+
+		// the issues of a full column move within it
+		require.Equal(t, nil, moveBoardIssue(ctx, stores, int(projectId), issueIds[1], models.MoveBoardIssueRequest{Column: "In Progress", BeforeIssueID: issueIds[0]}))
+		require.Equal(t, nil, moveBoardIssue(ctx, stores, int(projectId), issueIds[3], models.MoveBoardIssueRequest{Column: "To Do", AfterIssueID: issueIds[1]}))
+
+		board, err := getBoard(ctx, stores, int(projectId))
+		require.Equal(t, nil, err)
+		cells := board.Swimlanes[0].Cells
+		require.Equal(t, []uint{issueIds[1], issueIds[0]}, []uint{cells[1].Issues[0].ID, cells[1].Issues[1].ID})
+		require.Equal(t, "Review", cells[1].Issues[0].Status)
+		require.Equal(t, []uint{issueIds[3], issueIds[2]}, []uint{cells[0].Issues[0].ID, cells[0].Issues[1].ID})
+
+		changes, err := stores.Issues.ListProjectChanges(ctx, int(projectId), models.IssueFilter{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 6, len(changes), "the creations and the changes of status are recorded")
+	})
+
+	testCase.Run("moveBoardIssue return error", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, stores.Sprints.Start(ctx, int(projectId), int(sprintId), time.Now()))
+
+		err := moveBoardIssue(ctx, stores, int(projectId), issueId, models.MoveBoardIssueRequest{Column: "Done"})
+		require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode)
+
+		require.Equal(t, nil, saveBoard(ctx, stores, models.Board{ProjectID: int(projectId), Columns: columns}))
+		err = moveBoardIssue(ctx, stores, int(projectId), issueId, models.MoveBoardIssueRequest{Column: "Blocked"})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The board of project \"1\" has no column \"Blocked\"", ErrorCode: 400}, err)
+		err = moveBoardIssue(ctx, stores, int(projectId), issueId, models.MoveBoardIssueRequest{Column: "Done", Status: "Review"})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The column \"Done\" has no status \"Review\"", ErrorCode: 400}, err)
+		err = moveBoardIssue(ctx, stores, int(projectId), issueId, models.MoveBoardIssueRequest{Column: "Done", AfterIssueID: issueId})
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+		err = moveBoardIssue(ctx, stores, int(projectId), issueId, models.MoveBoardIssueRequest{Column: "Done", AfterIssueID: 100})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Issue with id \"100\" does not exists", ErrorCode: 404}, err)
+		err = moveBoardIssue(ctx, stores, int(projectId), 100, models.MoveBoardIssueRequest{Column: "Done"})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Issue with id \"100\" is not on the board of project \"1\"", ErrorCode: 404}, err)
+	})
+
+	testCase.Run("the board holds the issues of its scope", func(t *testing.T) {
+		for name, stores := range map[string]models.Stores{
+			"gorm":   internal.NewGormStores(internal.NewTestDatabase(t, config)),
+			"memory": internal.NewMemoryStores(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				projectId, completedId := internal.CreateProjectAndSprint(stores)
+				activeId := internal.CreateTestSprint(stores, "active", int(projectId))
+				plannedId := internal.CreateTestSprint(stores, "planned", int(projectId))
+				createIssue := func(sprintId uint, status string) uint {
+					issue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Task", Title: "Issue title", Status: status}
+					require.Equal(t, nil, stores.Issues.Create(ctx, &issue))
+					return issue.ID
+				}
+				completedIssueId := createIssue(completedId, "Done")
+				require.Equal(t, nil, stores.Sprints.Start(ctx, int(projectId), int(completedId), time.Now()))
+				require.Equal(t, nil, stores.Sprints.Complete(ctx, int(projectId), int(completedId), 0, time.Now()))
+				activeIssueId := createIssue(activeId, "To Do")
+				createIssue(activeId, "In Progress")
+				backlogIssueId := createIssue(0, "In Progress")
+				plannedIssueId := createIssue(plannedId, "To Do")
+				require.Equal(t, nil, stores.Sprints.Start(ctx, int(projectId), int(activeId), time.Now()))
+
+				oneIssue := 1
+				limitedColumns := []models.BoardColumn{
+					{Name: "To Do", Statuses: []string{"To Do"}},
+					{Name: "In Progress", Statuses: []string{"In Progress"}, WipLimit: &oneIssue},
+				}
+				require.Equal(t, nil, saveBoard(ctx, stores, models.Board{ProjectID: int(projectId), Scope: models.BOARD_SCOPE_ACTIVE_SPRINT, Columns: limitedColumns}))
+				board, err := getBoard(ctx, stores, int(projectId))
+				require.Equal(t, nil, err)
+				require.Equal(t, []int{1, 1}, []int{board.Columns[0].Issues, board.Columns[1].Issues})
+				require.Equal(t, activeIssueId, board.Swimlanes[0].Cells[0].Issues[0].ID)
+				err = moveBoardIssue(ctx, stores, int(projectId), backlogIssueId, models.MoveBoardIssueRequest{Column: "To Do"})
+				require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode, "the backlog is not on the board of the active sprint")
+				err = moveBoardIssue(ctx, stores, int(projectId), activeIssueId, models.MoveBoardIssueRequest{Column: "In Progress"})
+				require.Equal(t, 409, err.(*models.ErrorResponse).ErrorCode)
+
+				require.Equal(t, nil, saveBoard(ctx, stores, models.Board{ProjectID: int(projectId), Scope: models.BOARD_SCOPE_PROJECT, Columns: columns}))
+				board, err = getBoard(ctx, stores, int(projectId))
+				require.Equal(t, nil, err)
+				require.Equal(t, []int{2, 2, 0}, []int{board.Columns[0].Issues, board.Columns[1].Issues, board.Columns[2].Issues}, "the issues of the completed sprint are not on the board")
+				require.Equal(t, nil, moveBoardIssue(ctx, stores, int(projectId), plannedIssueId, models.MoveBoardIssueRequest{Column: "Done"}))
+				err = moveBoardIssue(ctx, stores, int(projectId), completedIssueId, models.MoveBoardIssueRequest{Column: "Done"})
+				require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode)
+			})
+		}
+	})
+}
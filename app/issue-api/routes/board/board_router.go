@@ -0,0 +1,62 @@
+package board
+
+import (
+	"issue-service/app/issue-api/routes/models"
+	"net/http"
+	"strings"
+)
+
+type boardRouter struct {
+	routes models.Routes
+	stores models.Stores
+}
+
+func NewRouter(stores models.Stores) models.Router {
+	r := &boardRouter{stores: stores}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routers to the checkpoint controller
+func (r *boardRouter) Routes() models.Routes {
+	return r.routes
+}
+
+func (r *boardRouter) initRoutes() {
+	r.routes = models.Routes{
+		models.Route{
+			Name:        "SaveBoard",
+			Method:      strings.ToUpper("Put"),
+			Pattern:     "/v1/projects/{projectId}/board",
+			HandlerFunc: createSaveBoardHandler,
+			Summary:     "Create or replace the board of a project, its columns and its swimlanes",
+			RequestBody: models.SaveBoardRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The board is saved"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetBoard",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/board",
+			HandlerFunc: createGetBoardHandler,
+			Summary:     "Get the board of a project with its issues, grouped by swimlane and column and ordered by rank",
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The board", Body: models.BoardResponse{}},
+			}, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "MoveBoardIssue",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/board/issues/{issueId}/move",
+			HandlerFunc: createMoveBoardIssueHandler,
+			Summary:     "Move an issue to a column of the board and next to another issue, within the WIP limit of the column",
+			RequestBody: models.MoveBoardIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The issue is moved"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package board
+
+import (
+	"encoding/json"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func getProjectIdFromRequest(r *http.Request) (int, error) {
+	projectId, err := strconv.Atoi(mux.Vars(r)["projectId"])
+	if err != nil {
+		return 0, &models.ErrorResponse{
+			ErrorMessage: "Error parsing projectId to int",
+			ErrorCode:    500,
+		}
+	}
+	return projectId, nil
+}
+
+// decodeRequestBody reads the JSON request body into requestBody and validates it
+func decodeRequestBody(r *http.Request, requestBody interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(requestBody); err != nil {
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
+		return &models.ErrorResponse{
+			ErrorMessage: "Error reading request body",
+			ErrorCode:    400,
+		}
+	}
+	return internal.ValidateRequest(requestBody)
+}
+
+func createSaveBoardHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+		var requestBody models.SaveBoardRequest
+		if err := decodeRequestBody(r, &requestBody); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		board := models.Board{ProjectID: projectId, Swimlane: requestBody.Swimlane, Scope: requestBody.Scope, Columns: requestBody.Columns}
+		if board.Scope == "" {
+			board.Scope = models.BOARD_SCOPE_ACTIVE_SPRINT
+		}
+		if err := saveBoard(r.Context(), stores, board); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func createGetBoardHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		board, err := getBoard(r.Context(), stores, projectId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(board)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createMoveBoardIssueHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+		issueId, err := strconv.ParseUint(mux.Vars(r)["issueId"], 10, 32)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error parsing issueId to uint",
+				ErrorCode:    500,
+			}, w)
+			return
+		}
+		var requestBody models.MoveBoardIssueRequest
+		if err := decodeRequestBody(r, &requestBody); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		if err := moveBoardIssue(r.Context(), stores, projectId, uint(issueId), requestBody); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
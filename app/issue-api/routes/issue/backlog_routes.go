@@ -0,0 +1,144 @@
+package issue
+
+import (
+	"encoding/json"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func getProjectIdFromRequest(request *http.Request) (int, error) {
+	projectId, err := strconv.Atoi(mux.Vars(request)["projectId"])
+	if err != nil {
+		return 0, &models.ErrorResponse{
+			ErrorMessage: "Error parsing projectId to int",
+			ErrorCode:    500,
+		}
+	}
+	return projectId, nil
+}
+
+func createAddBacklogIssueHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		requestBody, err := getIssueFromRequestBody(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		validationErr := internal.ValidateRequest(requestBody)
+		if validationErr != nil {
+			internal.LogAndReturnErrorResponse(validationErr, w)
+			return
+		}
+
+		requestIssue := models.Issue{
+			ProjectID:                projectId,
+			Type:                     requestBody.Type,
+			Title:                    requestBody.Title,
+			Description:              requestBody.Description,
+			Status:                   requestBody.Status,
+			Assignee:                 requestBody.Assignee,
+			Priority:                 requestBody.Priority,
+			Epic:                     requestBody.Epic,
+			StoryPoints:              requestBody.StoryPoints,
+			Size:                     requestBody.Size,
+			OriginalEstimateMinutes:  requestBody.OriginalEstimateMinutes,
+			RemainingEstimateMinutes: requestBody.RemainingEstimateMinutes,
+			LoggedMinutes:            requestBody.LoggedMinutes,
+		}
+
+		issueId, err := createBacklogIssue(r.Context(), stores, requestIssue)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		response, err := internal.GetCreateResponseBody(issueId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(response)
+	}
+}
+
+func createGetBacklogIssuesHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		page, err := internal.GetPageFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		issues, err := getBacklogIssues(r.Context(), stores, projectId, page)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(issues)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createMoveBacklogIssueHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		issueUid, err := getIssueIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		requestBody, err := getMoveIssueFromRequestBody(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		validationErr := internal.ValidateRequest(requestBody)
+		if validationErr != nil {
+			internal.LogAndReturnErrorResponse(validationErr, w)
+			return
+		}
+
+		issue := models.Issue{ID: issueUid, ProjectID: projectId}
+		moveError := moveBacklogIssue(r.Context(), stores, issue, requestBody.ProjectID, requestBody.SprintID)
+		if moveError != nil {
+			internal.LogAndReturnErrorResponse(moveError, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -1,37 +1,114 @@
 package issue
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
+	"strconv"
 	"strings"
-
-	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
-func createIssue(database *gorm.DB, issue models.Issue) (uint, error) {
-	result := database.Create(&issue)
+func getForeignKeyErrorResponse(err error, projectId int, sprintId int) error {
+	entity := "Sprint"
+	value := sprintId
+	if strings.Contains(err.Error(), "project") {
+		entity = "Project"
+		value = projectId
+	}
 
-	if result.Error != nil {
-		log.WithField("error", result.Error.Error()).Error("Error creating new issue")
+	return &models.ErrorResponse{
+		ErrorMessage: fmt.Sprintf("%s with id \"%d\" does not exists", entity, value),
+		ErrorCode:    404,
+	}
+}
 
-		if internal.IsForeignKeyError(result.Error) {
-			entity := "Sprint"
-			value := issue.SprintID
-			if strings.Contains(result.Error.Error(), "project") {
-				entity = "Project"
-				value = issue.ProjectID
-			}
+// validateEstimate checks the story points, or the size, of issue against the estimation scale of the project.
+// In the projects estimated with t-shirt sizes the story points are set from the size.
+func validateEstimate(project models.Project, issue *models.Issue) error {
+	if issue.StoryPoints == nil && issue.Size == "" {
+		return nil
+	}
 
-			return 0, &models.ErrorResponse{
-				ErrorMessage: fmt.Sprintf("%s with id \"%d\" does not exists", entity, value),
-				ErrorCode:    404,
+	if project.EstimationScale == models.ESTIMATION_SCALE_TSHIRT {
+		for index, size := range models.TShirtSizes {
+			if issue.StoryPoints == nil && issue.Size == size {
+				// a copy, the issue must not point into the mapping of the sizes
+				points := models.TShirtSizePoints[index]
+				issue.StoryPoints = &points
+				return nil
 			}
 		}
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issues of project \"%d\" are estimated with a size, one of %s", project.ID, strings.Join(models.TShirtSizes, ", ")),
+			ErrorCode:    400,
+		}
+	}
+
+	if issue.Size != "" {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issues of project \"%d\" are estimated in story points, not with a size", project.ID),
+			ErrorCode:    400,
+		}
+	}
+	if project.EstimationScale == models.ESTIMATION_SCALE_HOURS {
+		return nil
+	}
+	// the fibonacci scale, also used by the projects created before the estimation scales
+	for _, points := range models.FibonacciPoints {
+		if *issue.StoryPoints == points {
+			return nil
+		}
+	}
+	allowedPoints := make([]string, 0, len(models.FibonacciPoints))
+	for _, points := range models.FibonacciPoints {
+		allowedPoints = append(allowedPoints, strconv.Itoa(points))
+	}
+	return &models.ErrorResponse{
+		ErrorMessage: fmt.Sprintf("The story points of the issues of project \"%d\" must be one of %s, got %d",
+			project.ID, strings.Join(allowedPoints, ", "), *issue.StoryPoints),
+		ErrorCode: 400,
+	}
+}
+
+func createIssue(ctx context.Context, stores models.Stores, issue models.Issue) (uint, error) {
+	project, err := internal.GetProjectById(ctx, stores, issue.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := internal.GetProjectSprint(ctx, stores, issue.ProjectID, issue.SprintID); err != nil {
+		return 0, err
+	}
+	return saveNewIssue(ctx, stores, project, issue)
+}
+
+// createBacklogIssue creates an issue in no sprint
+func createBacklogIssue(ctx context.Context, stores models.Stores, issue models.Issue) (uint, error) {
+	project, err := internal.GetProjectById(ctx, stores, issue.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	issue.SprintID = 0
+	return saveNewIssue(ctx, stores, project, issue)
+}
+
+func saveNewIssue(ctx context.Context, stores models.Stores, project models.Project, issue models.Issue) (uint, error) {
+	if err := validateEstimate(project, &issue); err != nil {
+		return 0, err
+	}
+
+	err := stores.Issues.Create(ctx, &issue)
+
+	if err != nil {
+		internal.RequestLogger(ctx).WithField("error", err.Error()).Error("Error creating new issue")
+
+		if internal.IsForeignKeyError(err) {
+			return 0, getForeignKeyErrorResponse(err, issue.ProjectID, issue.SprintID)
+		}
 
 		return 0, &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 
@@ -40,47 +117,177 @@ func createIssue(database *gorm.DB, issue models.Issue) (uint, error) {
 	return issue.ID, nil
 }
 
-func getIssues(database *gorm.DB, projectId int, sprintId int) ([]models.GetIssueResponse, error) {
+func getIssues(ctx context.Context, stores models.Stores, projectId int, sprintId int, page models.Page) ([]models.GetIssueResponse, error) {
 	issues := []models.GetIssueResponse{}
 
-	result := database.Model(&models.Issue{}).Where("project_id = ? and sprint_id = ?", projectId, sprintId).Find(&issues)
+	if _, err := internal.GetProjectSprint(ctx, stores, projectId, sprintId); err != nil {
+		return []models.GetIssueResponse{}, err
+	}
 
-	if result.Error != nil {
+	foundIssues, err := stores.Issues.ListBySprint(ctx, projectId, sprintId, page)
+
+	if err != nil {
 		return []models.GetIssueResponse{}, &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 	}
+
+	for _, issue := range foundIssues {
+		issues = append(issues, issue.GetIssueResponseFromIssue())
+	}
 	return issues, nil
 }
 
-func patchIssue(database *gorm.DB, issue models.Issue) error {
-	result := database.Model(&issue).Updates(issue)
+// getBacklogIssues lists the issues of the project in no sprint
+func getBacklogIssues(ctx context.Context, stores models.Stores, projectId int, page models.Page) ([]models.GetIssueResponse, error) {
+	issues := []models.GetIssueResponse{}
 
-	if result.Error != nil {
-		if internal.IsForeignKeyError(result.Error) {
-			entity := "Sprint"
-			value := issue.SprintID
-			if strings.Contains(result.Error.Error(), "project") {
-				entity = "Project"
-				value = issue.ProjectID
-			}
+	if _, err := internal.GetProjectById(ctx, stores, projectId); err != nil {
+		return []models.GetIssueResponse{}, err
+	}
 
-			return &models.ErrorResponse{
-				ErrorMessage: fmt.Sprintf("%s with id \"%d\" does not exists", entity, value),
-				ErrorCode:    404,
-			}
+	foundIssues, err := stores.Issues.ListBySprint(ctx, projectId, 0, page)
+
+	if err != nil {
+		return []models.GetIssueResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+
+	for _, issue := range foundIssues {
+		issues = append(issues, issue.GetIssueResponseFromIssue())
+	}
+	return issues, nil
+}
+
+func getIssue(ctx context.Context, stores models.Stores, projectId int, sprintId int, issueId uint) (models.GetIssueResponse, error) {
+	issue, err := internal.GetSprintIssue(ctx, stores, projectId, sprintId, issueId)
+	if err != nil {
+		return models.GetIssueResponse{}, err
+	}
+
+	return issue.GetIssueResponseFromIssue(), nil
+}
+
+func patchIssue(ctx context.Context, stores models.Stores, issue models.Issue) error {
+	if _, err := internal.GetSprintIssue(ctx, stores, issue.ProjectID, issue.SprintID, issue.ID); err != nil {
+		return err
+	}
+	if issue.StoryPoints != nil || issue.Size != "" {
+		project, err := internal.GetProjectById(ctx, stores, issue.ProjectID)
+		if err != nil {
+			return err
 		}
+		if err := validateEstimate(project, &issue); err != nil {
+			return err
+		}
+	}
+
+	// ProjectID and SprintID are never updated here, see moveIssue
+	err := stores.Issues.Update(ctx, issue)
+
+	if errors.Is(err, internal.ErrNotFound) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" does not exists", issue.ID),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
 		return &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 	}
-	if result.RowsAffected == 0 {
+	return nil
+}
+
+func moveIssue(ctx context.Context, stores models.Stores, issue models.Issue, targetProjectId int, targetSprintId int) error {
+	if _, err := internal.GetSprintIssue(ctx, stores, issue.ProjectID, issue.SprintID, issue.ID); err != nil {
+		return err
+	}
+	return saveIssueMove(ctx, stores, issue, targetProjectId, targetSprintId)
+}
+
+// moveBacklogIssue moves an issue of the backlog to a sprint, or to the backlog of another project
+func moveBacklogIssue(ctx context.Context, stores models.Stores, issue models.Issue, targetProjectId int, targetSprintId int) error {
+	if _, err := internal.GetBacklogIssue(ctx, stores, issue.ProjectID, issue.ID); err != nil {
+		return err
+	}
+	issue.SprintID = 0
+	return saveIssueMove(ctx, stores, issue, targetProjectId, targetSprintId)
+}
+
+// saveIssueMove moves the issue to the sprint targetSprintId, or to the backlog when it is 0, of the project targetProjectId
+func saveIssueMove(ctx context.Context, stores models.Stores, issue models.Issue, targetProjectId int, targetSprintId int) error {
+	if targetSprintId == 0 {
+		if _, err := internal.GetProjectById(ctx, stores, targetProjectId); err != nil {
+			return err
+		}
+	} else if _, err := internal.GetProjectSprint(ctx, stores, targetProjectId, targetSprintId); err != nil {
+		return err
+	}
+
+	err := stores.Issues.Move(ctx, issue, targetProjectId, targetSprintId)
+
+	if errors.Is(err, internal.ErrNotFound) {
 		return &models.ErrorResponse{
 			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" does not exists", issue.ID),
 			ErrorCode:    404,
 		}
 	}
+	if err != nil {
+		if internal.IsForeignKeyError(err) {
+			return getForeignKeyErrorResponse(err, targetProjectId, targetSprintId)
+		}
+		return &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return nil
+}
+
+// rankIssue places an issue of the sprint, or of the backlog when sprintId is 0, next to another issue of the same sprint
+func rankIssue(ctx context.Context, stores models.Stores, projectId int, sprintId int, issueId uint, request models.RankIssueRequest) error {
+	if request.AfterIssueID == 0 && request.BeforeIssueID == 0 {
+		return &models.ErrorResponse{
+			ErrorMessage: "One of afterIssueId and beforeIssueId must be set",
+			ErrorCode:    400,
+		}
+	}
+	getIssue := func(issueId uint) error {
+		if sprintId == 0 {
+			_, err := internal.GetBacklogIssue(ctx, stores, projectId, issueId)
+			return err
+		}
+		_, err := internal.GetSprintIssue(ctx, stores, projectId, sprintId, issueId)
+		return err
+	}
+	if err := getIssue(issueId); err != nil {
+		return err
+	}
+	rank, err := internal.GetMoveRank(ctx, stores, projectId, issueId, request.AfterIssueID, request.BeforeIssueID)
+	if err != nil {
+		return err
+	}
+	if err := getIssue(request.AfterIssueID + request.BeforeIssueID); err != nil {
+		return err
+	}
+
+	err = stores.Issues.SetRank(ctx, projectId, issueId, rank)
+	if errors.Is(err, internal.ErrNotFound) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" does not exists", issueId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
 	return nil
 }
@@ -1,7 +1,7 @@
 package issue
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
@@ -18,13 +18,6 @@ func TestCreateIssue(testCase *testing.T) {
 		return
 	}
 
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
-	expectedResponse := 1
-	expectedJsonReponse, _ := json.Marshal(expectedResponse)
 	expectedTitle := "Task title"
 	expectedDescription := "Task description"
 	inputIssue := models.Issue{
@@ -36,19 +29,20 @@ func TestCreateIssue(testCase *testing.T) {
 	}
 
 	testCase.Run("createIssue return the new id", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
 		inputIssue.ProjectID = int(projectId)
 		inputIssue.SprintID = int(sprintId)
 
-		response, err := createIssue(database, inputIssue)
+		response, err := createIssue(context.Background(), stores, inputIssue)
 
 		var foundIssue models.Issue
 
 		database.First(&foundIssue)
 
 		require.Equal(t, nil, err)
-		require.Equal(t, string(expectedJsonReponse), fmt.Sprint(response))
+		require.Equal(t, foundIssue.ID, response)
 		require.Equal(t, int(projectId), foundIssue.ProjectID)
 		require.Equal(t, int(sprintId), foundIssue.SprintID)
 		require.Equal(t, expectedTitle, foundIssue.Title)
@@ -56,14 +50,15 @@ func TestCreateIssue(testCase *testing.T) {
 	})
 
 	testCase.Run("successfully create two issue on same sprint", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
 		inputIssue.ProjectID = int(projectId)
 		inputIssue.SprintID = int(sprintId)
 
-		_, err1 := createIssue(database, inputIssue)
+		_, err1 := createIssue(context.Background(), stores, inputIssue)
 		require.Equal(t, nil, err1)
-		_, err2 := createIssue(database, inputIssue)
+		_, err2 := createIssue(context.Background(), stores, inputIssue)
 		require.Equal(t, nil, err2)
 
 		var foundIssue []models.Issue
@@ -79,10 +74,24 @@ func TestCreateIssue(testCase *testing.T) {
 
 		inputIssue.SprintID = wrongSprintId
 
-		internal.SetupAndResetDatabase(database)
-		internal.CreateProjectAndSprint(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		internal.CreateProjectAndSprint(stores)
 
-		_, err := createIssue(database, inputIssue)
+		_, err := createIssue(context.Background(), stores, inputIssue)
+
+		require.Equal(t, expectedError, err.Error())
+	})
+
+	testCase.Run("createIssue returns error if sprint belongs to another project", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		_, sprintId := internal.CreateProjectAndSprint(stores)
+		otherProjectId := internal.CreateTestProject(stores)
+		expectedError := fmt.Sprintf("Sprint with id \"%d\" does not exists", sprintId)
+
+		inputIssue.ProjectID = int(otherProjectId)
+		inputIssue.SprintID = int(sprintId)
+
+		_, err := createIssue(context.Background(), stores, inputIssue)
 
 		require.Equal(t, expectedError, err.Error())
 	})
@@ -93,9 +102,9 @@ func TestCreateIssue(testCase *testing.T) {
 
 		inputIssue.ProjectID = wrongProjectId
 
-		internal.SetupAndResetDatabase(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
 
-		_, err := createIssue(database, inputIssue)
+		_, err := createIssue(context.Background(), stores, inputIssue)
 
 		require.Equal(t, expectedError, err.Error())
 	})
@@ -108,30 +117,24 @@ func TestGetIssues(testCase *testing.T) {
 		return
 	}
 
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
-
 	testCase.Run("getIssues return one issue", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
-		issueId := internal.CreateTestIssue(database, int(projectId), int(sprintId))
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
 
-		foundIssues, err := getIssues(database, int(projectId), int(sprintId))
+		foundIssues, err := getIssues(context.Background(), stores, int(projectId), int(sprintId), models.Page{})
 
 		require.Equal(t, nil, err)
 		require.Equal(t, issueId, foundIssues[0].ID)
 	})
 
 	testCase.Run("getIssues return a list of issues", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
-		issueId1 := internal.CreateTestIssue(database, int(projectId), int(sprintId))
-		issueId2 := internal.CreateTestIssue(database, int(projectId), int(sprintId))
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId1 := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		issueId2 := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
 
-		foundIssues, err := getIssues(database, int(projectId), int(sprintId))
+		foundIssues, err := getIssues(context.Background(), stores, int(projectId), int(sprintId), models.Page{})
 
 		require.Equal(t, nil, err)
 		require.Equal(t, 2, len(foundIssues))
@@ -139,41 +142,82 @@ func TestGetIssues(testCase *testing.T) {
 		require.Equal(t, issueId2, foundIssues[1].ID)
 	})
 
-	testCase.Run("getIssues return empty list if project does not exist", func(t *testing.T) {
+	testCase.Run("getIssues return error if project does not exist", func(t *testing.T) {
 		nonExistingProjectId := 99999
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
-		internal.CreateTestIssue(database, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Project with id \"%d\" does not exists", nonExistingProjectId)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
 
-		foundIssues, err := getIssues(database, nonExistingProjectId, int(sprintId))
+		foundIssues, err := getIssues(context.Background(), stores, nonExistingProjectId, int(sprintId), models.Page{})
 
-		require.Equal(t, nil, err)
+		require.Equal(t, expectedError, err.Error())
 		require.Equal(t, []models.GetIssueResponse{}, foundIssues)
 	})
 
-	testCase.Run("getIssues return empty list if sprint does not exist", func(t *testing.T) {
+	testCase.Run("getIssues return error if sprint does not exist", func(t *testing.T) {
 		nonExistingSprinttId := 99999
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
-		internal.CreateTestIssue(database, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Sprint with id \"%d\" does not exists", nonExistingSprinttId)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
 
-		foundIssues, err := getIssues(database, int(projectId), nonExistingSprinttId)
+		foundIssues, err := getIssues(context.Background(), stores, int(projectId), nonExistingSprinttId, models.Page{})
 
-		require.Equal(t, nil, err)
+		require.Equal(t, expectedError, err.Error())
 		require.Equal(t, []models.GetIssueResponse{}, foundIssues)
 	})
+
+	testCase.Run("getIssues return error if sprint belongs to another project", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherProjectId := internal.CreateTestProject(stores)
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Sprint with id \"%d\" does not exists", sprintId)
+
+		_, err := getIssues(context.Background(), stores, int(otherProjectId), int(sprintId), models.Page{})
+
+		require.Equal(t, expectedError, err.Error())
+	})
 }
 
-func TestPatchIssue(testCase *testing.T) {
+func TestGetIssue(testCase *testing.T) {
 	config, err := internal.GetConfig("../../../../.env")
 	if err != nil {
 		log.Fatalf("Error reading env configuration: %s", err.Error())
 		return
 	}
 
-	database, err := internal.ConnectDatabase(config)
+	testCase.Run("getIssue return the issue", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		foundIssue, err := getIssue(context.Background(), stores, int(projectId), int(sprintId), issueId)
+
+		require.Equal(t, nil, err)
+		require.Equal(t, issueId, foundIssue.ID)
+		require.Equal(t, int(projectId), foundIssue.ProjectID)
+		require.Equal(t, int(sprintId), foundIssue.SprintID)
+	})
+
+	testCase.Run("getIssue return error if issue belongs to another sprint", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherSprintId := internal.CreateTestSprint(stores, "other", int(projectId))
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Issue with id \"%d\" does not exists", issueId)
+
+		_, err := getIssue(context.Background(), stores, int(projectId), int(otherSprintId), issueId)
+
+		require.Equal(t, expectedError, err.Error())
+	})
+}
+
+func TestPatchIssue(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
 	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
+		log.Fatalf("Error reading env configuration: %s", err.Error())
 		return
 	}
 
@@ -188,18 +232,21 @@ func TestPatchIssue(testCase *testing.T) {
 	}
 
 	testCase.Run("patchIssue update the status field only", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
 		inputIssue.ProjectID = int(projectId)
 		inputIssue.SprintID = int(sprintId)
-		issueId, _ := createIssue(database, inputIssue)
+		issueId, _ := createIssue(context.Background(), stores, inputIssue)
 		expectedStatus := "Completed"
 
 		patchIssueInput := models.Issue{
-			ID:     issueId,
-			Status: expectedStatus,
+			ID:        issueId,
+			ProjectID: int(projectId),
+			SprintID:  int(sprintId),
+			Status:    expectedStatus,
 		}
-		err := patchIssue(database, patchIssueInput)
+		err := patchIssue(context.Background(), stores, patchIssueInput)
 		require.Equal(t, nil, err)
 
 		var foundIssue models.Issue
@@ -211,18 +258,410 @@ func TestPatchIssue(testCase *testing.T) {
 	})
 
 	testCase.Run("patchIssue return error if issue does not exists", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		projectId, sprintId := internal.CreateProjectAndSprint(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
 		inputIssue.ProjectID = int(projectId)
 		inputIssue.SprintID = int(sprintId)
 		wrongIssueId := uint(99999)
 		expectedError := fmt.Sprintf("Issue with id \"%d\" does not exists", wrongIssueId)
 
 		patchIssueInput := models.Issue{
-			ID:     wrongIssueId,
-			Status: "Completed",
+			ID:        wrongIssueId,
+			ProjectID: int(projectId),
+			SprintID:  int(sprintId),
+			Status:    "Completed",
+		}
+		err := patchIssue(context.Background(), stores, patchIssueInput)
+		require.Equal(t, expectedError, err.Error())
+	})
+
+	testCase.Run("patchIssue return error if issue belongs to another project", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherProjectId, otherSprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Issue with id \"%d\" does not exists", issueId)
+
+		patchIssueInput := models.Issue{
+			ID:        issueId,
+			ProjectID: int(otherProjectId),
+			SprintID:  int(otherSprintId),
+			Status:    "Completed",
 		}
-		err := patchIssue(database, patchIssueInput)
+		err := patchIssue(context.Background(), stores, patchIssueInput)
+		require.Equal(t, expectedError, err.Error())
+
+		var foundIssue models.Issue
+		database.First(&foundIssue, issueId)
+		require.Equal(t, int(projectId), foundIssue.ProjectID)
+		require.Equal(t, int(sprintId), foundIssue.SprintID)
+		require.Equal(t, "To Do", foundIssue.Status)
+	})
+}
+
+func TestMoveIssue(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	testCase.Run("moveIssue moves the issue to another project and sprint", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		targetProjectId, targetSprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		issue := models.Issue{
+			ID:        issueId,
+			ProjectID: int(projectId),
+			SprintID:  int(sprintId),
+		}
+		err := moveIssue(context.Background(), stores, issue, int(targetProjectId), int(targetSprintId))
+		require.Equal(t, nil, err)
+
+		var foundIssue models.Issue
+		database.First(&foundIssue, issueId)
+		require.Equal(t, int(targetProjectId), foundIssue.ProjectID)
+		require.Equal(t, int(targetSprintId), foundIssue.SprintID)
+		require.Equal(t, "Issue title", foundIssue.Title)
+	})
+
+	testCase.Run("moveIssue moves the issue to the backlog when the target sprint is 0", func(t *testing.T) {
+		for name, stores := range map[string]models.Stores{
+			"gorm":   internal.NewGormStores(internal.NewTestDatabase(t, config)),
+			"memory": internal.NewMemoryStores(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				projectId, sprintId := internal.CreateProjectAndSprint(stores)
+				issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+				issue := models.Issue{ID: issueId, ProjectID: int(projectId), SprintID: int(sprintId)}
+				require.Equal(t, nil, moveIssue(ctx, stores, issue, int(projectId), 0))
+
+				backlog, err := stores.Issues.ListBySprint(ctx, int(projectId), 0, models.Page{})
+				require.Equal(t, nil, err)
+				require.Equal(t, 1, len(backlog))
+				require.Equal(t, issueId, backlog[0].ID)
+				sprintIssues, err := stores.Issues.ListBySprint(ctx, int(projectId), int(sprintId), models.Page{})
+				require.Equal(t, nil, err)
+				require.Equal(t, 0, len(sprintIssues))
+
+				otherIssue := models.Issue{ID: internal.CreateTestIssue(stores, int(projectId), int(sprintId)), ProjectID: int(projectId), SprintID: int(sprintId)}
+				err = moveIssue(ctx, stores, otherIssue, 100, 0)
+				require.Equal(t, &models.ErrorResponse{ErrorMessage: "Project with id \"100\" does not exists", ErrorCode: 404}, err)
+			})
+		}
+	})
+
+	testCase.Run("moveIssue return error if target sprint belongs to another project", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		_, otherSprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Sprint with id \"%d\" does not exists", otherSprintId)
+
+		issue := models.Issue{
+			ID:        issueId,
+			ProjectID: int(projectId),
+			SprintID:  int(sprintId),
+		}
+		err := moveIssue(context.Background(), stores, issue, int(projectId), int(otherSprintId))
+		require.Equal(t, expectedError, err.Error())
+
+		var foundIssue models.Issue
+		database.First(&foundIssue, issueId)
+		require.Equal(t, int(sprintId), foundIssue.SprintID)
+	})
+
+	testCase.Run("moveIssue return error if issue is not in the source sprint", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherSprintId := internal.CreateTestSprint(stores, "other", int(projectId))
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Issue with id \"%d\" does not exists", issueId)
+
+		issue := models.Issue{
+			ID:        issueId,
+			ProjectID: int(projectId),
+			SprintID:  int(otherSprintId),
+		}
+		err := moveIssue(context.Background(), stores, issue, int(projectId), int(sprintId))
+		require.Equal(t, expectedError, err.Error())
+	})
+}
+
+func TestBacklog(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	testCase.Run("createBacklogIssue creates an issue in no sprint", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		issueId, err := createBacklogIssue(context.Background(), stores, models.Issue{ProjectID: int(projectId), Type: "Task", Title: "Backlog task"})
+		require.Equal(t, nil, err)
+
+		backlogIssues, err := getBacklogIssues(context.Background(), stores, int(projectId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 1, len(backlogIssues))
+		require.Equal(t, issueId, backlogIssues[0].ID)
+		require.Equal(t, 0, backlogIssues[0].SprintID)
+		require.Equal(t, "Backlog task", backlogIssues[0].Title)
+	})
+
+	testCase.Run("createBacklogIssue returns error if project does not exists", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		_, err := createBacklogIssue(context.Background(), stores, models.Issue{ProjectID: 99999, Type: "Task", Title: "Backlog task"})
+
+		require.Equal(t, "Project with id \"99999\" does not exists", err.Error())
+	})
+
+	testCase.Run("moveBacklogIssue moves the issue to a sprint", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), 0)
+
+		err := moveBacklogIssue(context.Background(), stores, models.Issue{ID: issueId, ProjectID: int(projectId)}, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+
+		var foundIssue models.Issue
+		database.First(&foundIssue, issueId)
+		require.Equal(t, int(sprintId), foundIssue.SprintID)
+		backlogIssues, err := getBacklogIssues(context.Background(), stores, int(projectId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 0, len(backlogIssues))
+	})
+
+	testCase.Run("moveBacklogIssue returns error if the issue is in a sprint", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		expectedError := fmt.Sprintf("Issue with id \"%d\" is not in the backlog", issueId)
+
+		err := moveBacklogIssue(context.Background(), stores, models.Issue{ID: issueId, ProjectID: int(projectId)}, int(projectId), int(sprintId))
+
+		require.Equal(t, expectedError, err.Error())
+	})
+}
+
+func TestIssueEstimates(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	points := func(value int) *int {
+		return &value
+	}
+	createProjectAndSprint := func(stores models.Stores, estimationScale string) (int, int) {
+		project := models.Project{Name: internal.GetRandomStringName(10), Type: "project-type", EstimationScale: estimationScale}
+		stores.Projects.Create(context.Background(), &project)
+		sprintId := internal.CreateTestSprint(stores, "12345", int(project.ID))
+		return int(project.ID), int(sprintId)
+	}
+
+	testCase.Run("createIssue accepts the fibonacci story points and the time estimates", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := createProjectAndSprint(stores, models.ESTIMATION_SCALE_FIBONACCI)
+
+		issueId, err := createIssue(context.Background(), stores, models.Issue{
+			ProjectID:               projectId,
+			SprintID:                sprintId,
+			Type:                    "Task",
+			Title:                   "Estimated",
+			StoryPoints:             points(8),
+			OriginalEstimateMinutes: points(480),
+		})
+		require.Equal(t, nil, err)
+
+		issue, err := getIssue(context.Background(), stores, projectId, sprintId, issueId)
+		require.Equal(t, nil, err)
+		require.Equal(t, 8, *issue.StoryPoints)
+		require.Equal(t, 480, *issue.OriginalEstimateMinutes)
+		require.Nil(t, issue.RemainingEstimateMinutes)
+	})
+
+	testCase.Run("createIssue returns 400 if the story points are not on the fibonacci scale", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := createProjectAndSprint(stores, "")
+		expectedError := fmt.Sprintf("The story points of the issues of project \"%d\" must be one of 0, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, got 4", projectId)
+
+		_, err := createIssue(context.Background(), stores, models.Issue{ProjectID: projectId, SprintID: sprintId, Type: "Task", Title: "Title", StoryPoints: points(4)})
+
 		require.Equal(t, expectedError, err.Error())
 	})
+
+	testCase.Run("createIssue sets the story points from the t-shirt size", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := createProjectAndSprint(stores, models.ESTIMATION_SCALE_TSHIRT)
+
+		issueId, err := createIssue(context.Background(), stores, models.Issue{ProjectID: projectId, SprintID: sprintId, Type: "Task", Title: "Title", Size: "L"})
+		require.Equal(t, nil, err)
+		issue, _ := getIssue(context.Background(), stores, projectId, sprintId, issueId)
+		require.Equal(t, "L", issue.Size)
+		require.Equal(t, 5, *issue.StoryPoints)
+
+		_, err = createIssue(context.Background(), stores, models.Issue{ProjectID: projectId, SprintID: sprintId, Type: "Task", Title: "Title", StoryPoints: points(5)})
+		require.Equal(t, fmt.Sprintf("Issues of project \"%d\" are estimated with a size, one of XS, S, M, L, XL, XXL", projectId), err.Error())
+	})
+
+	testCase.Run("createIssue does not share the story points of the sizes", func(t *testing.T) {
+		stores := internal.NewMemoryStores()
+		projectId, sprintId := createProjectAndSprint(stores, models.ESTIMATION_SCALE_TSHIRT)
+		issueId, err := createIssue(context.Background(), stores, models.Issue{ProjectID: projectId, SprintID: sprintId, Type: "Task", Title: "Title", Size: "L"})
+		require.Equal(t, nil, err)
+		stored, _ := getIssue(context.Background(), stores, projectId, sprintId, issueId)
+		*stored.StoryPoints = 100
+		require.Equal(t, []int{1, 2, 3, 5, 8, 13}, models.TShirtSizePoints)
+	})
+
+	testCase.Run("patchIssue validates the story points against the hours scale", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := createProjectAndSprint(stores, models.ESTIMATION_SCALE_HOURS)
+		issueId := internal.CreateTestIssue(stores, projectId, sprintId)
+
+		err := patchIssue(context.Background(), stores, models.Issue{ID: issueId, ProjectID: projectId, SprintID: sprintId, StoryPoints: points(4), LoggedMinutes: points(0)})
+		require.Equal(t, nil, err)
+		issue, _ := getIssue(context.Background(), stores, projectId, sprintId, issueId)
+		require.Equal(t, 4, *issue.StoryPoints)
+		require.Equal(t, 0, *issue.LoggedMinutes)
+
+		err = patchIssue(context.Background(), stores, models.Issue{ID: issueId, ProjectID: projectId, SprintID: sprintId, Size: "M"})
+		require.Equal(t, fmt.Sprintf("Issues of project \"%d\" are estimated in story points, not with a size", projectId), err.Error())
+	})
+}
+
+func TestRankIssue(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+	getIssueIds := func(t *testing.T, stores models.Stores, projectId int, sprintId int) []uint {
+		issues, err := getBacklogIssues(context.Background(), stores, projectId, models.Page{})
+		if sprintId != 0 {
+			issues, err = getIssues(context.Background(), stores, projectId, sprintId, models.Page{})
+		}
+		require.Equal(t, nil, err)
+		issueIds := []uint{}
+		for _, issue := range issues {
+			issueIds = append(issueIds, issue.ID)
+		}
+		return issueIds
+	}
+
+	testCase.Run("rankIssue orders the issues of the sprint and of the backlog", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueIds := []uint{}
+		for index := 0; index < 3; index++ {
+			issueIds = append(issueIds, internal.CreateTestIssue(stores, int(projectId), int(sprintId)))
+		}
+		firstBacklogIssueId := internal.CreateTestIssue(stores, int(projectId), 0)
+		secondBacklogIssueId := internal.CreateTestIssue(stores, int(projectId), 0)
+
+		require.Equal(t, nil, rankIssue(ctx, stores, int(projectId), int(sprintId), issueIds[2], models.RankIssueRequest{BeforeIssueID: issueIds[0]}))
+		require.Equal(t, nil, rankIssue(ctx, stores, int(projectId), int(sprintId), issueIds[0], models.RankIssueRequest{AfterIssueID: issueIds[1]}))
+		require.Equal(t, []uint{issueIds[2], issueIds[1], issueIds[0]}, getIssueIds(t, stores, int(projectId), int(sprintId)))
+
+		require.Equal(t, nil, rankIssue(ctx, stores, int(projectId), 0, secondBacklogIssueId, models.RankIssueRequest{BeforeIssueID: firstBacklogIssueId}))
+		require.Equal(t, []uint{secondBacklogIssueId, firstBacklogIssueId}, getIssueIds(t, stores, int(projectId), 0))
+	})
+
+	testCase.Run("rankIssue reads only the neighbour and the next rank of the project", func(t *testing.T) {
+		for name, stores := range map[string]models.Stores{
+			"gorm":   internal.NewGormStores(internal.NewTestDatabase(t, config)),
+			"memory": internal.NewMemoryStores(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				projectId, _ := internal.CreateProjectAndSprint(stores)
+				issueIds := []uint{}
+				for index := 0; index < 4; index++ {
+					issueIds = append(issueIds, internal.CreateTestIssue(stores, int(projectId), 0))
+				}
+				otherProjectId, _ := internal.CreateProjectAndSprint(stores)
+				otherIssueId := internal.CreateTestIssue(stores, int(otherProjectId), 0)
+				// the issues created concurrently share a rank, the issue of the other project is ranked between them
+				require.Equal(t, nil, stores.Issues.SetRank(ctx, int(projectId), issueIds[1], "000000000001i"))
+				require.Equal(t, nil, stores.Issues.SetRank(ctx, int(otherProjectId), otherIssueId, "000000000001r"))
+
+				require.Equal(t, nil, rankIssue(ctx, stores, int(projectId), 0, issueIds[3], models.RankIssueRequest{AfterIssueID: issueIds[0]}))
+				require.Equal(t, []uint{issueIds[0], issueIds[1], issueIds[3], issueIds[2]}, getIssueIds(t, stores, int(projectId), 0))
+				issues, err := stores.Issues.ListByProject(ctx, int(projectId), models.IssueFilter{})
+				require.Equal(t, nil, err)
+				require.Equal(t, internal.RankBetween("000000000001i", issues[3].Rank), issues[2].Rank)
+				require.Equal(t, nil, rankIssue(ctx, stores, int(projectId), 0, issueIds[0], models.RankIssueRequest{BeforeIssueID: issueIds[2]}))
+				require.Equal(t, []uint{issueIds[1], issueIds[3], issueIds[0], issueIds[2]}, getIssueIds(t, stores, int(projectId), 0))
+			})
+		}
+	})
+
+	testCase.Run("rankIssue rebalances the ranks of the project when they grow too long", func(t *testing.T) {
+		for name, stores := range map[string]models.Stores{
+			"gorm":   internal.NewGormStores(internal.NewTestDatabase(t, config)),
+			"memory": internal.NewMemoryStores(),
+		} {
+			t.Run(name, func(t *testing.T) {
+				ctx := context.Background()
+				projectId, sprintId := internal.CreateProjectAndSprint(stores)
+				issueIds := []uint{}
+				for index := 0; index < 3; index++ {
+					issueIds = append(issueIds, internal.CreateTestIssue(stores, int(projectId), int(sprintId)))
+				}
+				getLongestRank := func() string {
+					issues, err := stores.Issues.ListByProject(ctx, int(projectId), models.IssueFilter{})
+					require.Equal(t, nil, err)
+					longest := ""
+					for _, issue := range issues {
+						if len(issue.Rank) > len(longest) {
+							longest = issue.Rank
+						}
+					}
+					return longest
+				}
+
+				// moving the issues to the top makes the first rank longer every few moves, past 64 characters without the rebalancing
+				ranked := issueIds
+				for move := 0; move < 400; move++ {
+					require.Equal(t, nil, rankIssue(ctx, stores, int(projectId), int(sprintId), ranked[2], models.RankIssueRequest{BeforeIssueID: ranked[0]}))
+					ranked = append([]uint{ranked[2]}, ranked[:2]...)
+					require.LessOrEqual(t, len(getLongestRank()), 64)
+				}
+				require.Equal(t, ranked, getIssueIds(t, stores, int(projectId), int(sprintId)), "the rebalancing keeps the order")
+			})
+		}
+	})
+
+	testCase.Run("rankIssue return error", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		otherIssueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		backlogIssueId := internal.CreateTestIssue(stores, int(projectId), 0)
+
+		err := rankIssue(ctx, stores, int(projectId), int(sprintId), issueId, models.RankIssueRequest{})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "One of afterIssueId and beforeIssueId must be set", ErrorCode: 400}, err)
+		err = rankIssue(ctx, stores, int(projectId), int(sprintId), issueId, models.RankIssueRequest{AfterIssueID: otherIssueId, BeforeIssueID: otherIssueId})
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+		err = rankIssue(ctx, stores, int(projectId), int(sprintId), issueId, models.RankIssueRequest{AfterIssueID: backlogIssueId})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: fmt.Sprintf("Issue with id \"%d\" does not exists", backlogIssueId), ErrorCode: 404}, err)
+		err = rankIssue(ctx, stores, int(projectId), 0, issueId, models.RankIssueRequest{AfterIssueID: backlogIssueId})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: fmt.Sprintf("Issue with id \"%d\" is not in the backlog", issueId), ErrorCode: 404}, err)
+	})
 }
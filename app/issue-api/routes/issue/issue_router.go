@@ -2,18 +2,17 @@ package issue
 
 import (
 	"issue-service/app/issue-api/routes/models"
+	"net/http"
 	"strings"
-
-	"gorm.io/gorm"
 )
 
 type issueRouter struct {
-	routes   models.Routes
-	database *gorm.DB
+	routes models.Routes
+	stores models.Stores
 }
 
-func NewRouter(db *gorm.DB) models.Router {
-	r := &issueRouter{database: db}
+func NewRouter(stores models.Stores) models.Router {
+	r := &issueRouter{stores: stores}
 	r.initRoutes()
 	return r
 }
@@ -30,13 +29,23 @@ func (r *issueRouter) initRoutes() {
 			Method:      strings.ToUpper("Post"),
 			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues",
 			HandlerFunc: createAddIssueHandler,
+			Summary:     "Create an issue in a sprint",
+			RequestBody: models.CreateIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The id of the new issue", Body: models.CreateResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
 		},
 
 		models.Route{
-			Name:        "GetIssues",
-			Method:      strings.ToUpper("Get"),
-			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues",
-			HandlerFunc: createGetIssuesHandler,
+			Name:            "GetIssues",
+			Method:          strings.ToUpper("Get"),
+			Pattern:         "/v1/projects/{projectId}/sprints/{sprintId}/issues",
+			HandlerFunc:     createGetIssuesHandler,
+			Summary:         "List the issues of a sprint in the order of their rank",
+			QueryParameters: models.PageParameters,
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The issues", Body: []models.GetIssueResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
 		},
 
 		models.Route{
@@ -44,6 +53,94 @@ func (r *issueRouter) initRoutes() {
 			Method:      strings.ToUpper("Patch"),
 			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues/{issueId}",
 			HandlerFunc: createPatchIssueHandler,
+			Summary:     "Update the fields of an issue",
+			RequestBody: models.PatchIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The issue is updated"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetIssueById",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues/{issueId}",
+			HandlerFunc: createGetIssueHandler,
+			Summary:     "Get an issue",
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The issue", Body: models.GetIssueResponse{}},
+			}, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "MoveIssue",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues/{issueId}/move",
+			HandlerFunc: createMoveIssueHandler,
+			Summary:     "Move an issue to another sprint, or to the backlog, possibly of another project",
+			RequestBody: models.MoveIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The issue is moved"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "RankIssue",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues/{issueId}/rank",
+			HandlerFunc: createRankIssueHandler,
+			Summary:     "Rank an issue of a sprint right after or right before another issue of the sprint",
+			RequestBody: models.RankIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The issue is ranked"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "AddBacklogIssue",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/backlog/issues",
+			HandlerFunc: createAddBacklogIssueHandler,
+			Summary:     "Create an issue in the backlog of a project",
+			RequestBody: models.CreateIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The id of the new issue", Body: models.CreateResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:            "GetBacklogIssues",
+			Method:          strings.ToUpper("Get"),
+			Pattern:         "/v1/projects/{projectId}/backlog/issues",
+			HandlerFunc:     createGetBacklogIssuesHandler,
+			Summary:         "List the issues of the backlog of a project, the issues in no sprint, in the order of their rank",
+			QueryParameters: models.PageParameters,
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The issues", Body: []models.GetIssueResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "MoveBacklogIssue",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/backlog/issues/{issueId}/move",
+			HandlerFunc: createMoveBacklogIssueHandler,
+			Summary:     "Move an issue of the backlog to a sprint, or to the backlog of another project",
+			RequestBody: models.MoveIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The issue is moved"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "RankBacklogIssue",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/backlog/issues/{issueId}/rank",
+			HandlerFunc: createRankIssueHandler,
+			Summary:     "Rank an issue of the backlog right after or right before another issue of the backlog",
+			RequestBody: models.RankIssueRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The issue is ranked"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
 		},
 	}
 }
@@ -8,8 +8,6 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
-	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 func getProjectIdAndSprintIdFromRequest(request *http.Request) (projectId string, sprintId string, err error) {
@@ -31,11 +29,31 @@ func getProjectIdAndSprintIdFromRequest(request *http.Request) (projectId string
 	return projectId, sprintId, nil
 }
 
+func getIssueIdFromRequest(request *http.Request) (uint, error) {
+	vars := mux.Vars(request)
+	issueId, issueOk := vars["issueId"]
+	if !issueOk {
+		return 0, &models.ErrorResponse{
+			ErrorMessage: "Error reading issueId path param from request",
+			ErrorCode:    500,
+		}
+	}
+
+	issueUid, err := strconv.ParseUint(issueId, 10, 32)
+	if err != nil {
+		return 0, &models.ErrorResponse{
+			ErrorMessage: "Error parsing issueId to uint",
+			ErrorCode:    500,
+		}
+	}
+	return uint(issueUid), nil
+}
+
 func getIssueFromRequestBody(request *http.Request) (models.CreateIssueRequest, error) {
 	var requestBody models.CreateIssueRequest
 	err := json.NewDecoder(request.Body).Decode(&requestBody)
 	if err != nil {
-		log.WithField("error", err.Error()).Error("Error reading request body")
+		internal.RequestLogger(request.Context()).WithField("error", err.Error()).Error("Error reading request body")
 		return models.CreateIssueRequest{}, &models.ErrorResponse{
 			ErrorMessage: "Error reading request body",
 			ErrorCode:    400,
@@ -49,7 +67,7 @@ func getPatchIssueFromRequestBody(r *http.Request) (models.PatchIssueRequest, er
 	var requestBody models.PatchIssueRequest
 	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-		log.WithField("error", err.Error()).Error("Error reading request body")
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
 		return models.PatchIssueRequest{}, &models.ErrorResponse{
 			ErrorMessage: "Error reading request body",
 			ErrorCode:    400,
@@ -58,7 +76,22 @@ func getPatchIssueFromRequestBody(r *http.Request) (models.PatchIssueRequest, er
 
 	return requestBody, nil
 }
-func createAddIssueHandler(database *gorm.DB) http.HandlerFunc {
+
+func getMoveIssueFromRequestBody(r *http.Request) (models.MoveIssueRequest, error) {
+	var requestBody models.MoveIssueRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	if err != nil {
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
+		return models.MoveIssueRequest{}, &models.ErrorResponse{
+			ErrorMessage: "Error reading request body",
+			ErrorCode:    400,
+		}
+	}
+
+	return requestBody, nil
+}
+
+func createAddIssueHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
@@ -80,11 +113,18 @@ func createAddIssueHandler(database *gorm.DB) http.HandlerFunc {
 		}
 
 		requestIssue := models.Issue{
-			Type:        requestBody.Type,
-			Title:       requestBody.Title,
-			Description: requestBody.Description,
-			Status:      requestBody.Status,
-			Assignee:    requestBody.Assignee,
+			Type:                     requestBody.Type,
+			Title:                    requestBody.Title,
+			Description:              requestBody.Description,
+			Status:                   requestBody.Status,
+			Assignee:                 requestBody.Assignee,
+			Priority:                 requestBody.Priority,
+			Epic:                     requestBody.Epic,
+			StoryPoints:              requestBody.StoryPoints,
+			Size:                     requestBody.Size,
+			OriginalEstimateMinutes:  requestBody.OriginalEstimateMinutes,
+			RemainingEstimateMinutes: requestBody.RemainingEstimateMinutes,
+			LoggedMinutes:            requestBody.LoggedMinutes,
 		}
 
 		requestIssue.ProjectID, err = strconv.Atoi(projectId)
@@ -108,7 +148,8 @@ func createAddIssueHandler(database *gorm.DB) http.HandlerFunc {
 		}
 
 		issueId, err := createIssue(
-			database,
+			r.Context(),
+			stores,
 			requestIssue,
 		)
 		if err != nil {
@@ -126,7 +167,7 @@ func createAddIssueHandler(database *gorm.DB) http.HandlerFunc {
 	}
 }
 
-func createPatchIssueHandler(database *gorm.DB) http.HandlerFunc {
+func createPatchIssueHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
@@ -153,13 +194,9 @@ func createPatchIssueHandler(database *gorm.DB) http.HandlerFunc {
 			return
 		}
 
-		vars := mux.Vars(r)
-		issueId, issueOk := vars["issueId"]
-		if !issueOk {
-			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
-				ErrorMessage: "Error parsing sprintId to int",
-				ErrorCode:    500,
-			}, w)
+		issueUid, err := getIssueIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
 			return
 		}
 
@@ -169,27 +206,34 @@ func createPatchIssueHandler(database *gorm.DB) http.HandlerFunc {
 			return
 		}
 
-		issueUid, err := strconv.ParseUint(issueId, 10, 32)
-		if err != nil {
+		if (requestBody.ProjectID != 0 && requestBody.ProjectID != projectIdInt) ||
+			(requestBody.SprintID != 0 && requestBody.SprintID != sprintIdInt) {
 			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
-				ErrorMessage: "Error parsing sprintId to uint",
-				ErrorCode:    500,
+				ErrorMessage: "Use the move endpoint to change the issue project or sprint",
+				ErrorCode:    400,
 			}, w)
 			return
 		}
 
 		requestIssue := models.Issue{
-			ProjectID:   projectIdInt,
-			SprintID:    sprintIdInt,
-			ID:          uint(issueUid),
-			Type:        requestBody.Type,
-			Title:       requestBody.Title,
-			Description: requestBody.Description,
-			Status:      requestBody.Status,
-			Assignee:    requestBody.Assignee,
+			ProjectID:                projectIdInt,
+			SprintID:                 sprintIdInt,
+			ID:                       issueUid,
+			Type:                     requestBody.Type,
+			Title:                    requestBody.Title,
+			Description:              requestBody.Description,
+			Status:                   requestBody.Status,
+			Assignee:                 requestBody.Assignee,
+			Priority:                 requestBody.Priority,
+			Epic:                     requestBody.Epic,
+			StoryPoints:              requestBody.StoryPoints,
+			Size:                     requestBody.Size,
+			OriginalEstimateMinutes:  requestBody.OriginalEstimateMinutes,
+			RemainingEstimateMinutes: requestBody.RemainingEstimateMinutes,
+			LoggedMinutes:            requestBody.LoggedMinutes,
 		}
 
-		patchError := patchIssue(database, requestIssue)
+		patchError := patchIssue(r.Context(), stores, requestIssue)
 		if patchError != nil {
 			internal.LogAndReturnErrorResponse(patchError, w)
 			return
@@ -199,7 +243,7 @@ func createPatchIssueHandler(database *gorm.DB) http.HandlerFunc {
 	}
 }
 
-func createGetIssuesHandler(database *gorm.DB) http.HandlerFunc {
+func createGetIssuesHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
@@ -228,7 +272,13 @@ func createGetIssuesHandler(database *gorm.DB) http.HandlerFunc {
 			return
 		}
 
-		issues, err := getIssues(database, projectIdInt, sprintIdInt)
+		page, err := internal.GetPageFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		issues, err := getIssues(r.Context(), stores, projectIdInt, sprintIdInt, page)
 		if err != nil {
 			internal.LogAndReturnErrorResponse(err, w)
 			return
@@ -243,3 +293,160 @@ func createGetIssuesHandler(database *gorm.DB) http.HandlerFunc {
 		w.Write(responseBody)
 	}
 }
+
+func createGetIssueHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		projectIdInt, err := strconv.Atoi(projectId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error parsing projectId to int",
+				ErrorCode:    500,
+			}, w)
+			return
+		}
+
+		sprintIdInt, err := strconv.Atoi(sprintId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error parsing sprintId to int",
+				ErrorCode:    500,
+			}, w)
+			return
+		}
+
+		issueUid, err := getIssueIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		issue, err := getIssue(r.Context(), stores, projectIdInt, sprintIdInt, issueUid)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(issue)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createMoveIssueHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		projectIdInt, err := strconv.Atoi(projectId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error parsing projectId to int",
+				ErrorCode:    500,
+			}, w)
+			return
+		}
+
+		sprintIdInt, err := strconv.Atoi(sprintId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error parsing sprintId to int",
+				ErrorCode:    500,
+			}, w)
+			return
+		}
+
+		issueUid, err := getIssueIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		requestBody, err := getMoveIssueFromRequestBody(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		validationErr := internal.ValidateRequest(requestBody)
+		if validationErr != nil {
+			internal.LogAndReturnErrorResponse(validationErr, w)
+			return
+		}
+
+		issue := models.Issue{
+			ID:        issueUid,
+			ProjectID: projectIdInt,
+			SprintID:  sprintIdInt,
+		}
+
+		moveError := moveIssue(r.Context(), stores, issue, requestBody.ProjectID, requestBody.SprintID)
+		if moveError != nil {
+			internal.LogAndReturnErrorResponse(moveError, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// createRankIssueHandler ranks the issues of a sprint, and of the backlog on the routes without sprintId
+func createRankIssueHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		sprintId := 0
+		if sprintIdValue, ok := mux.Vars(r)["sprintId"]; ok {
+			if sprintId, err = strconv.Atoi(sprintIdValue); err != nil {
+				internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+					ErrorMessage: "Error parsing sprintId to int",
+					ErrorCode:    500,
+				}, w)
+				return
+			}
+		}
+
+		issueUid, err := getIssueIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		var requestBody models.RankIssueRequest
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error reading request body",
+				ErrorCode:    400,
+			}, w)
+			return
+		}
+
+		rankError := rankIssue(r.Context(), stores, projectId, sprintId, issueUid, requestBody)
+		if rankError != nil {
+			internal.LogAndReturnErrorResponse(rankError, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
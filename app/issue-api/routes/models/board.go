@@ -0,0 +1,103 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// The swimlanes of a board group its issues by assignee, by type, by priority or by epic, the board has a single swimlane without them
+const (
+	SWIMLANE_NONE     = ""
+	SWIMLANE_ASSIGNEE = "assignee"
+	SWIMLANE_TYPE     = "type"
+	SWIMLANE_PRIORITY = "priority"
+	SWIMLANE_EPIC     = "epic"
+)
+
+// The scope of a board selects its issues: the issues of the active sprint, by default, or the issues of the backlog
+// and of the sprints not completed
+const (
+	BOARD_SCOPE_ACTIVE_SPRINT = "activeSprint"
+	BOARD_SCOPE_PROJECT       = "project"
+)
+
+// Board maps the statuses of the issues of a project to ordered columns, a project has at most one board
+type Board struct {
+	ID        uint `gorm:"primaryKey"`
+	ProjectID int  `gorm:"uniqueIndex:idx_boards_project"`
+	Swimlane  string
+	Scope     string
+	Columns   []BoardColumn `gorm:"serializer:json"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// InScope tells whether the issues of the sprint, nil for the backlog, are on the board
+func (board Board) InScope(sprint *Sprint) bool {
+	if board.Scope == BOARD_SCOPE_PROJECT {
+		return sprint == nil || sprint.CompletedAt == nil
+	}
+	return sprint != nil && sprint.IsActive()
+}
+
+// BoardColumn holds the issues in one of its statuses, compared case-insensitively, the empty status holds the issues without status.
+// WipLimit bounds the number of issues of the board in the column, nil for no limit.
+type BoardColumn struct {
+	Name     string   `json:"name" validate:"required,max=50"`
+	Statuses []string `json:"statuses" validate:"required,min=1,max=20,dive,max=50"`
+	WipLimit *int     `json:"wipLimit,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+// HasStatus tells whether the issues in status belong to the column
+func (column BoardColumn) HasStatus(status string) bool {
+	for _, columnStatus := range column.Statuses {
+		if strings.EqualFold(columnStatus, status) {
+			return true
+		}
+	}
+	return false
+}
+
+type SaveBoardRequest struct {
+	Swimlane string        `json:"swimlane,omitempty" validate:"omitempty,oneof=assignee type priority epic"`
+	Scope    string        `json:"scope,omitempty" validate:"omitempty,oneof=activeSprint project"`
+	Columns  []BoardColumn `json:"columns" validate:"required,min=1,max=20,dive"`
+}
+
+// BoardColumnResponse counts the issues of the board in the column
+type BoardColumnResponse struct {
+	BoardColumn
+	Issues    int  `json:"issues"`
+	OverLimit bool `json:"overLimit"`
+}
+
+// BoardCellResponse lists the issues of a swimlane in a column, ordered by rank
+type BoardCellResponse struct {
+	Column string             `json:"column"`
+	Issues []GetIssueResponse `json:"issues"`
+}
+
+// BoardSwimlaneResponse has a cell by column, Name is the assignee, the type, the priority or the epic of its issues
+type BoardSwimlaneResponse struct {
+	Name  string              `json:"name"`
+	Cells []BoardCellResponse `json:"cells"`
+}
+
+// BoardResponse is the board of a project with its issues, UnmappedIssues counts the issues in no column
+type BoardResponse struct {
+	ProjectID      int                     `json:"projectId"`
+	Swimlane       string                  `json:"swimlane,omitempty"`
+	Scope          string                  `json:"scope"`
+	Columns        []BoardColumnResponse   `json:"columns"`
+	Swimlanes      []BoardSwimlaneResponse `json:"swimlanes"`
+	UnmappedIssues int                     `json:"unmappedIssues"`
+}
+
+// MoveBoardIssueRequest moves an issue to a column, and between two issues of the project when one of them is set.
+// Status is the status of the issue in the column, the first status of the column when it is empty.
+type MoveBoardIssueRequest struct {
+	Column        string `json:"column" validate:"required,max=50"`
+	Status        string `json:"status,omitempty" validate:"max=50"`
+	AfterIssueID  uint   `json:"afterIssueId,omitempty"`
+	BeforeIssueID uint   `json:"beforeIssueId,omitempty"`
+}
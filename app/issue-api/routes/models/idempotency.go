@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// IdempotencyRecord keeps the response of a POST request sent with an Idempotency-Key header,
+// so that the retries of the request get the same response instead of applying it twice
+type IdempotencyRecord struct {
+	ID  uint   `gorm:"primaryKey"`
+	Key string `gorm:"uniqueIndex:idx_idempotency_records_key"`
+	// RequestHash tells apart a retry from another request sent with the same key
+	RequestHash string
+	// StatusCode is 0 while the first request is processed
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time `gorm:"index:idx_idempotency_records_created_at"`
+}
@@ -1,68 +1,163 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// ClosedIssueStatuses are the statuses of the finished issues, compared case-insensitively
+var ClosedIssueStatuses = []string{"done", "completed", "closed", "resolved"}
+
+func IsClosedIssueStatus(status string) bool {
+	for _, closedStatus := range ClosedIssueStatuses {
+		if strings.EqualFold(status, closedStatus) {
+			return true
+		}
+	}
+	return false
+}
+
+// TodoIssueStatuses are the statuses of the issues whose work has not started, compared case-insensitively.
+// The issues in the other statuses that are not closed are in progress.
+var TodoIssueStatuses = []string{"", "to do", "todo", "open", "new", "backlog"}
+
+func IsTodoIssueStatus(status string) bool {
+	for _, todoStatus := range TodoIssueStatuses {
+		if strings.EqualFold(status, todoStatus) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssuePriorities go from the most urgent P0 to the least urgent P4, the issues without priority have no SLA
+var IssuePriorities = []string{"P0", "P1", "P2", "P3", "P4"}
+
 type Issue struct {
 	gorm.Model
 	ID          uint `gorm:"primaryKey"`
-	ProjectID   int  `gorm:"uniqueIndex:idx_member"`
+	ProjectID   int  `gorm:"index:idx_issues_project_sprint"`
 	Project     Project
-	SprintID    int `gorm:"uniqueIndex:idx_member"`
-	Sprint      Project
+	SprintID    int `gorm:"index:idx_issues_project_sprint"`
+	Sprint      Sprint
 	Type        string
 	Title       string
 	Description string
 	Status      string
 	Assignee    string
+	Priority    string
+	// Epic groups the issues of a larger piece of work, it is free text as the assignee
+	Epic string
+	// StoryPoints is nil until the issue is estimated, Size is set in the projects estimated with t-shirt sizes
+	StoryPoints *int
+	Size        string
+	// the estimates and the logged time are in minutes
+	OriginalEstimateMinutes  *int
+	RemainingEstimateMinutes *int
+	LoggedMinutes            *int
+	// Rank orders the issues of the project, see internal.RankBetween
+	Rank string
+}
+
+// IssueChange records the state of an issue after a change of its status, its points, its project or its sprint.
+// SprintID is 0 while the issue is in the backlog.
+type IssueChange struct {
+	ID          uint `gorm:"primaryKey"`
+	IssueID     uint
+	ProjectID   int
+	SprintID    int
+	Status      string
+	StoryPoints *int
+	ChangedAt   time.Time
 }
 
 type CreateIssueRequest struct {
-	Type        string `json:"type,omitempty"`
-	Title       string `json:"title,omitempty"`
-	Description string `json:"description,omitempty"`
-	Status      string `json:"status,omitempty"`
-	Assignee    string `json:"assignee,omitempty"`
+	Type                     string `json:"type,omitempty" validate:"required,max=50"`
+	Title                    string `json:"title,omitempty" validate:"required,max=255"`
+	Description              string `json:"description,omitempty" validate:"max=10000"`
+	Status                   string `json:"status,omitempty" validate:"max=50"`
+	Assignee                 string `json:"assignee,omitempty" validate:"max=255"`
+	Priority                 string `json:"priority,omitempty" validate:"omitempty,oneof=P0 P1 P2 P3 P4"`
+	Epic                     string `json:"epic,omitempty" validate:"max=255"`
+	StoryPoints              *int   `json:"storyPoints,omitempty" validate:"omitempty,min=0,max=1000"`
+	Size                     string `json:"size,omitempty" validate:"omitempty,oneof=XS S M L XL XXL"`
+	OriginalEstimateMinutes  *int   `json:"originalEstimateMinutes,omitempty" validate:"omitempty,min=0,max=1000000"`
+	RemainingEstimateMinutes *int   `json:"remainingEstimateMinutes,omitempty" validate:"omitempty,min=0,max=1000000"`
+	LoggedMinutes            *int   `json:"loggedMinutes,omitempty" validate:"omitempty,min=0,max=1000000"`
 }
 
 type GetIssueResponse struct {
-	ID          uint      `json:"id,omitempty"`
-	ProjectID   int       `json:"projectId,omitempty"`
-	SprintID    int       `json:"sprintId,omitempty"`
-	Type        string    `json:"type,omitempty"`
-	Title       string    `json:"title,omitempty"`
-	Description string    `json:"description,omitempty"`
-	Status      string    `json:"status,omitempty"`
-	Assignee    string    `json:"assignee,omitempty"`
-	CreatedAt   time.Time `json:"createdAt,omitempty"`
-	UpdatedAt   time.Time `json:"updatedAt,omitempty"`
+	ID                       uint      `json:"id,omitempty"`
+	ProjectID                int       `json:"projectId,omitempty"`
+	SprintID                 int       `json:"sprintId,omitempty"`
+	Type                     string    `json:"type,omitempty"`
+	Title                    string    `json:"title,omitempty"`
+	Description              string    `json:"description,omitempty"`
+	Status                   string    `json:"status,omitempty"`
+	Assignee                 string    `json:"assignee,omitempty"`
+	Priority                 string    `json:"priority,omitempty"`
+	Epic                     string    `json:"epic,omitempty"`
+	CreatedAt                time.Time `json:"createdAt,omitempty"`
+	UpdatedAt                time.Time `json:"updatedAt,omitempty"`
+	StoryPoints              *int      `json:"storyPoints,omitempty"`
+	Size                     string    `json:"size,omitempty"`
+	OriginalEstimateMinutes  *int      `json:"originalEstimateMinutes,omitempty"`
+	RemainingEstimateMinutes *int      `json:"remainingEstimateMinutes,omitempty"`
+	LoggedMinutes            *int      `json:"loggedMinutes,omitempty"`
+	Rank                     string    `json:"rank,omitempty"`
 }
 
 type PatchIssueRequest struct {
-	ID          uint   `json:"id,omitempty"`
-	ProjectID   int    `json:"projectId,omitempty"`
-	SprintID    int    `json:"sprintId,omitempty"`
-	Type        string `json:"type,omitempty"`
-	Title       string `json:"title,omitempty"`
-	Description string `json:"description,omitempty"`
-	Status      string `json:"status,omitempty"`
-	Assignee    string `json:"assignee,omitempty"`
+	ID                       uint   `json:"id,omitempty"`
+	ProjectID                int    `json:"projectId,omitempty"`
+	SprintID                 int    `json:"sprintId,omitempty"`
+	Type                     string `json:"type,omitempty" validate:"max=50"`
+	Title                    string `json:"title,omitempty" validate:"max=255"`
+	Description              string `json:"description,omitempty" validate:"max=10000"`
+	Status                   string `json:"status,omitempty" validate:"max=50"`
+	Assignee                 string `json:"assignee,omitempty" validate:"max=255"`
+	Priority                 string `json:"priority,omitempty" validate:"omitempty,oneof=P0 P1 P2 P3 P4"`
+	Epic                     string `json:"epic,omitempty" validate:"max=255"`
+	StoryPoints              *int   `json:"storyPoints,omitempty" validate:"omitempty,min=0,max=1000"`
+	Size                     string `json:"size,omitempty" validate:"omitempty,oneof=XS S M L XL XXL"`
+	OriginalEstimateMinutes  *int   `json:"originalEstimateMinutes,omitempty" validate:"omitempty,min=0,max=1000000"`
+	RemainingEstimateMinutes *int   `json:"remainingEstimateMinutes,omitempty" validate:"omitempty,min=0,max=1000000"`
+	LoggedMinutes            *int   `json:"loggedMinutes,omitempty" validate:"omitempty,min=0,max=1000000"`
+}
+
+// MoveIssueRequest moves an issue to a sprint of a project, or to its backlog when SprintID is 0
+type MoveIssueRequest struct {
+	ProjectID int `json:"projectId" validate:"required"`
+	SprintID  int `json:"sprintId" validate:"min=0"`
+}
+
+// RankIssueRequest places an issue right after, or right before, another issue of its sprint or of the backlog
+type RankIssueRequest struct {
+	AfterIssueID  uint `json:"afterIssueId,omitempty"`
+	BeforeIssueID uint `json:"beforeIssueId,omitempty"`
 }
 
 func (issue Issue) GetIssueResponseFromIssue() GetIssueResponse {
 	return GetIssueResponse{
-		ID:          issue.ID,
-		ProjectID:   issue.ProjectID,
-		SprintID:    issue.SprintID,
-		Type:        issue.Type,
-		Title:       issue.Title,
-		Description: issue.Description,
-		Status:      issue.Status,
-		Assignee:    issue.Assignee,
-		CreatedAt:   issue.CreatedAt,
-		UpdatedAt:   issue.UpdatedAt,
+		ID:                       issue.ID,
+		ProjectID:                issue.ProjectID,
+		SprintID:                 issue.SprintID,
+		Type:                     issue.Type,
+		Title:                    issue.Title,
+		Description:              issue.Description,
+		Status:                   issue.Status,
+		Assignee:                 issue.Assignee,
+		Priority:                 issue.Priority,
+		Epic:                     issue.Epic,
+		CreatedAt:                issue.CreatedAt,
+		UpdatedAt:                issue.UpdatedAt,
+		StoryPoints:              issue.StoryPoints,
+		Size:                     issue.Size,
+		OriginalEstimateMinutes:  issue.OriginalEstimateMinutes,
+		RemainingEstimateMinutes: issue.RemainingEstimateMinutes,
+		LoggedMinutes:            issue.LoggedMinutes,
+		Rank:                     issue.Rank,
 	}
 }
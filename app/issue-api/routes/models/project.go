@@ -1,19 +1,60 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// DEFAULT_PROJECT_TIMEZONE is the timezone of the projects created without one
+const DEFAULT_PROJECT_TIMEZONE = "UTC"
+
+// The estimation scales of the projects: the story points of the issues are a Fibonacci number,
+// derived from a t-shirt size, or a number of hours
+const (
+	ESTIMATION_SCALE_FIBONACCI = "fibonacci"
+	ESTIMATION_SCALE_TSHIRT    = "tshirt"
+	ESTIMATION_SCALE_HOURS     = "hours"
+)
+
+// FibonacciPoints are the story points allowed by the fibonacci scale
+var FibonacciPoints = []int{0, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+// TShirtSizes are the sizes allowed by the tshirt scale, TShirtSizePoints their story points
+var TShirtSizes = []string{"XS", "S", "M", "L", "XL", "XXL"}
+var TShirtSizePoints = []int{1, 2, 3, 5, 8, 13}
+
 type Project struct {
 	gorm.Model
 	ID     uint `gorm:"primaryKey"`
 	Client string
 	Name   string `gorm:"unique"`
 	Type   string
+	// Timezone is the IANA name of the timezone the days of the sprints are counted in
+	Timezone string
+	// SprintMinDays and SprintMaxDays bound the length of the sprints in days, 0 means no bound
+	SprintMinDays int
+	SprintMaxDays int
+	// EstimationScale is one of the ESTIMATION_SCALE constants
+	EstimationScale string
+}
+
+// GetLocation returns the timezone of the project, UTC when it is unset or unknown
+func (project Project) GetLocation() *time.Location {
+	location, err := time.LoadLocation(project.Timezone)
+	if err != nil || project.Timezone == "" {
+		return time.UTC
+	}
+	return location
 }
 
 type CreateProjectRequest struct {
-	Client string `json:"client,omitempty"`
-	Name   string `json:"name,omitempty" validate:"required"`
-	Type   string `json:"type,omitempty" validate:"required"`
+	Client        string `json:"client,omitempty" validate:"max=255"`
+	Name          string `json:"name,omitempty" validate:"required,max=255"`
+	Type          string `json:"type,omitempty" validate:"required,max=50"`
+	Timezone      string `json:"timezone,omitempty" validate:"max=64"`
+	SprintMinDays int    `json:"sprintMinDays,omitempty" validate:"min=0,max=366"`
+	SprintMaxDays int    `json:"sprintMaxDays,omitempty" validate:"min=0,max=366"`
+	// EstimationScale is fibonacci by default
+	EstimationScale string `json:"estimationScale,omitempty" validate:"omitempty,oneof=fibonacci tshirt hours"`
 }
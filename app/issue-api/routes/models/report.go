@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// VelocitySprint is the work committed and completed in a completed sprint.
+// RollingAverage averages the completed work of the sprint and of the previous sprints of the window.
+type VelocitySprint struct {
+	SprintID       uint      `json:"sprintId"`
+	Number         string    `json:"number"`
+	StartDate      time.Time `json:"startDate"`
+	EndDate        time.Time `json:"endDate"`
+	CompletedAt    time.Time `json:"completedAt"`
+	Committed      int       `json:"committed"`
+	Completed      int       `json:"completed"`
+	RollingAverage float64   `json:"rollingAverage"`
+}
+
+// ForecastPercentile is the number of sprints finishing the remaining work in Percentile percent of the simulations
+type ForecastPercentile struct {
+	Percentile int `json:"percentile"`
+	Sprints    int `json:"sprints"`
+}
+
+// VelocityForecast simulates the sprints needed by the open issues of the project.
+// Percentiles is empty when no sprint completed any work.
+type VelocityForecast struct {
+	RemainingWork     int                  `json:"remainingWork"`
+	UnestimatedIssues int                  `json:"unestimatedIssues"`
+	Simulations       int                  `json:"simulations"`
+	Percentiles       []ForecastPercentile `json:"percentiles"`
+}
+
+// VelocityReportResponse is the velocity of the latest completed sprints of a project, in the order of their completion
+type VelocityReportResponse struct {
+	ProjectID         int              `json:"projectId"`
+	Unit              string           `json:"unit"`
+	Window            int              `json:"window"`
+	Sprints           []VelocitySprint `json:"sprints"`
+	AverageVelocity   float64          `json:"averageVelocity"`
+	StandardDeviation float64          `json:"standardDeviation"`
+	Forecast          VelocityForecast `json:"forecast"`
+}
+
+// OpenWork sums the open issues of a project, those whose status is not one of ClosedIssueStatuses
+type OpenWork struct {
+	Issues            int
+	Points            int
+	UnestimatedIssues int
+}
+
+// FlowIssue is an issue completed in the range of the flow report.
+// StartedAt is the first change to a status in progress, it is nil when the issue went from to do to closed.
+type FlowIssue struct {
+	IssueID       uint       `json:"issueId"`
+	Title         string     `json:"title"`
+	Type          string     `json:"type"`
+	Assignee      string     `json:"assignee,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	StartedAt     *time.Time `json:"startedAt,omitempty"`
+	CompletedAt   time.Time  `json:"completedAt"`
+	LeadTimeDays  float64    `json:"leadTimeDays"`
+	CycleTimeDays *float64   `json:"cycleTimeDays,omitempty"`
+}
+
+// FlowTimePercentile is the time within which Percentile percent of the issues were completed
+type FlowTimePercentile struct {
+	Percentile int     `json:"percentile"`
+	Days       float64 `json:"days"`
+}
+
+type FlowTimeSummary struct {
+	Issues      int                  `json:"issues"`
+	AverageDays float64              `json:"averageDays"`
+	Percentiles []FlowTimePercentile `json:"percentiles"`
+}
+
+// CumulativeFlowDay counts the issues of the project by status at the end of a day
+type CumulativeFlowDay struct {
+	Date     string         `json:"date"`
+	Statuses map[string]int `json:"statuses"`
+}
+
+// FlowReportResponse is the lead and cycle times of the issues completed from From to To, and the cumulative flow of these days.
+// Statuses lists the statuses of the cumulative flow, the statuses to do first and the closed statuses last.
+type FlowReportResponse struct {
+	ProjectID      int                 `json:"projectId"`
+	From           string              `json:"from"`
+	To             string              `json:"to"`
+	Type           string              `json:"type,omitempty"`
+	Assignee       string              `json:"assignee,omitempty"`
+	LeadTime       FlowTimeSummary     `json:"leadTime"`
+	CycleTime      FlowTimeSummary     `json:"cycleTime"`
+	Issues         []FlowIssue         `json:"issues"`
+	Statuses       []string            `json:"statuses"`
+	CumulativeFlow []CumulativeFlowDay `json:"cumulativeFlow"`
+}
@@ -2,8 +2,6 @@ package models
 
 import (
 	"net/http"
-
-	"gorm.io/gorm"
 )
 
 type ErrorResponse struct {
@@ -19,7 +17,32 @@ type Route struct {
 	Name        string
 	Method      string
 	Pattern     string
-	HandlerFunc func(*gorm.DB) http.HandlerFunc
+	HandlerFunc func(Stores) http.HandlerFunc
+	// Summary, QueryParameters, RequestBody and Responses document the route in the OpenAPI specification.
+	// RequestBody is a value of the type decoded from the request body, nil when the route reads no body.
+	// RequestBodyOptional accepts the requests without body, the fields of RequestBody keep their zero values.
+	Summary             string
+	QueryParameters     []QueryParameter
+	RequestBody         interface{}
+	RequestBodyOptional bool
+	Responses           map[int]Response
+}
+
+// QueryParameter documents an optional query parameter, Type is integer or string
+type QueryParameter struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Response documents a response of a route.
+// Body is a value of the type written in the response, nil for an empty body and a string for plain text.
+// OtherContentTypes are the text formats the route can write instead, selected by a query parameter.
+type Response struct {
+	Description       string
+	Body              interface{}
+	ContentType       string
+	OtherContentTypes []string
 }
 
 type Routes []Route
@@ -29,6 +52,30 @@ type Router interface {
 	Routes() Routes
 }
 
+// MAX_PAGE_LIMIT bounds the limit query parameter of the lists
+const MAX_PAGE_LIMIT = 1000
+
+// PageParameters are the query parameters of the paginated lists
+var PageParameters = []QueryParameter{
+	{Name: "limit", Type: "integer", Description: "maximum number of items returned, every item when missing"},
+	{Name: "offset", Type: "integer", Description: "number of items skipped, in the order of the list"},
+}
+
 type CreateResponse struct {
 	Id string `json:"id,omitempty"`
 }
+
+var errorDescriptions = map[int]string{
+	http.StatusBadRequest:          "The request is invalid",
+	http.StatusNotFound:            "The resource or one of its parents does not exist",
+	http.StatusConflict:            "The request conflicts with an existing resource",
+	http.StatusInternalServerError: "Unexpected error",
+}
+
+// WithErrors adds to responses an ErrorResponse body for every status code
+func WithErrors(responses map[int]Response, statusCodes ...int) map[int]Response {
+	for _, statusCode := range statusCodes {
+		responses[statusCode] = Response{Description: errorDescriptions[statusCode], Body: ErrorResponse{}}
+	}
+	return responses
+}
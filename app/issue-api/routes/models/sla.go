@@ -0,0 +1,124 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// The response clock of an issue runs until it leaves the statuses to do, its resolution clock while it is not closed
+const (
+	SLA_KIND_RESPONSE   = "response"
+	SLA_KIND_RESOLUTION = "resolution"
+)
+
+// The states of an SLA clock: a running clock counts the time, a paused clock waits in a paused status,
+// a met clock stopped within its target and a breached clock exceeded it
+const (
+	SLA_STATE_RUNNING  = "running"
+	SLA_STATE_PAUSED   = "paused"
+	SLA_STATE_MET      = "met"
+	SLA_STATE_BREACHED = "breached"
+)
+
+// SlaPolicy holds the SLA targets of the issues of a project, a project has at most one policy.
+// The clocks of the issues do not run while they are in one of PausedStatuses, compared case-insensitively.
+type SlaPolicy struct {
+	ID             uint        `gorm:"primaryKey"`
+	ProjectID      int         `gorm:"uniqueIndex:idx_sla_policies_project"`
+	PausedStatuses []string    `gorm:"serializer:json"`
+	Targets        []SlaTarget `gorm:"serializer:json"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// SlaTarget gives the minutes to respond to and to resolve the issues of a priority, and of a type when it is set.
+// A nil target sets no SLA.
+type SlaTarget struct {
+	Priority          string `json:"priority" validate:"required,oneof=P0 P1 P2 P3 P4"`
+	Type              string `json:"type,omitempty" validate:"max=50"`
+	ResponseMinutes   *int   `json:"responseMinutes,omitempty" validate:"omitempty,min=1,max=525600"`
+	ResolutionMinutes *int   `json:"resolutionMinutes,omitempty" validate:"omitempty,min=1,max=525600"`
+}
+
+// IsPaused tells whether the clocks of the issues in status are paused
+func (policy SlaPolicy) IsPaused(status string) bool {
+	for _, pausedStatus := range policy.PausedStatuses {
+		if strings.EqualFold(pausedStatus, status) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTarget returns the target of the priority and of the type of the issue,
+// or else the target of its priority for every type, false when the issue has no target
+func (policy SlaPolicy) GetTarget(issue Issue) (SlaTarget, bool) {
+	found := false
+	var target SlaTarget
+	for _, policyTarget := range policy.Targets {
+		if policyTarget.Priority != issue.Priority {
+			continue
+		}
+		if strings.EqualFold(policyTarget.Type, issue.Type) {
+			return policyTarget, true
+		}
+		if policyTarget.Type == "" {
+			target, found = policyTarget, true
+		}
+	}
+	return target, found
+}
+
+// SlaBreach flags the issue whose clock of Kind exceeded its target, the checker flags a breach once
+type SlaBreach struct {
+	ID         uint   `gorm:"primaryKey"`
+	ProjectID  int    `gorm:"index:idx_sla_breaches_project"`
+	IssueID    uint   `gorm:"uniqueIndex:idx_sla_breaches_issue_kind"`
+	Kind       string `gorm:"uniqueIndex:idx_sla_breaches_issue_kind"`
+	BreachedAt time.Time
+	CreatedAt  time.Time
+}
+
+type SaveSlaPolicyRequest struct {
+	PausedStatuses []string    `json:"pausedStatuses,omitempty" validate:"max=20,dive,max=50"`
+	Targets        []SlaTarget `json:"targets" validate:"required,min=1,max=100,dive"`
+}
+
+type SlaPolicyResponse struct {
+	ProjectID      int         `json:"projectId"`
+	PausedStatuses []string    `json:"pausedStatuses"`
+	Targets        []SlaTarget `json:"targets"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+}
+
+// SlaClock is the time counted against a target, RemainingMinutes is negative once the target is exceeded.
+// DueAt is set while the clock is running, FlaggedAt once the checker flagged its breach.
+type SlaClock struct {
+	State            string     `json:"state"`
+	TargetMinutes    int        `json:"targetMinutes"`
+	ElapsedMinutes   int        `json:"elapsedMinutes"`
+	RemainingMinutes int        `json:"remainingMinutes"`
+	DueAt            *time.Time `json:"dueAt,omitempty"`
+	BreachedAt       *time.Time `json:"breachedAt,omitempty"`
+	FlaggedAt        *time.Time `json:"flaggedAt,omitempty"`
+}
+
+// SlaIssue is an issue with its clocks, a clock is nil when the target of the issue sets no time for it
+type SlaIssue struct {
+	IssueID    uint      `json:"issueId"`
+	SprintID   int       `json:"sprintId,omitempty"`
+	Type       string    `json:"type"`
+	Title      string    `json:"title"`
+	Status     string    `json:"status,omitempty"`
+	Assignee   string    `json:"assignee,omitempty"`
+	Priority   string    `json:"priority"`
+	Response   *SlaClock `json:"response,omitempty"`
+	Resolution *SlaClock `json:"resolution,omitempty"`
+}
+
+// SlaIssuesResponse lists the issues not closed whose clocks are breached, or due within WithinMinutes
+type SlaIssuesResponse struct {
+	ProjectID     int        `json:"projectId"`
+	WithinMinutes int        `json:"withinMinutes"`
+	Issues        []SlaIssue `json:"issues"`
+}
@@ -9,43 +9,173 @@ import (
 type Sprint struct {
 	gorm.Model
 	ID                uint   `gorm:"primaryKey"`
-	Number            string `gorm:"uniqueIndex:idx_member"`
-	ProjectID         int    `gorm:"uniqueIndex:idx_member"`
+	Number            string `gorm:"uniqueIndex:idx_sprints_project_number"`
+	ProjectID         int    `gorm:"uniqueIndex:idx_sprints_project_number"`
 	Project           Project
 	StartDate         time.Time
 	EndDate           time.Time
 	Completed         bool
 	MaxIssuePerSprint int
+	StartedAt         *time.Time
+	CompletedAt       *time.Time
+	// NextSprintID received the unfinished issues of the completed sprint, nil when they went to the backlog
+	NextSprintID *int
+}
+
+// IsActive is true between the start and the completion of the sprint
+func (sprint Sprint) IsActive() bool {
+	return sprint.StartedAt != nil && sprint.CompletedAt == nil
+}
+
+// Overlaps tells whether the two sprints share an instant, a sprint lasts from its start included to its end excluded,
+// so that a sprint can start when the previous one ends. The sprints without dates overlap no sprint.
+func (sprint Sprint) Overlaps(other Sprint) bool {
+	if sprint.StartDate.IsZero() || sprint.EndDate.IsZero() || other.StartDate.IsZero() || other.EndDate.IsZero() {
+		return false
+	}
+	return sprint.StartDate.Before(other.EndDate) && other.StartDate.Before(sprint.EndDate)
+}
+
+// The outcomes of the issues of a completed sprint
+const (
+	SPRINT_OUTCOME_COMPLETED    = "completed"
+	SPRINT_OUTCOME_CARRIED_OVER = "carriedOver"
+	SPRINT_OUTCOME_REMOVED      = "removed"
+)
+
+// SprintSnapshotIssue records an issue of a sprint, Committed when it was in the sprint at its start.
+// The Outcome and the Status are set when the sprint is completed.
+type SprintSnapshotIssue struct {
+	SprintID  uint `gorm:"primaryKey;autoIncrement:false"`
+	IssueID   uint `gorm:"primaryKey;autoIncrement:false"`
+	Committed bool
+	Outcome   string
+	Type      string
+	Title     string
+	Status    string
+	// StoryPoints are the points of the issue at the start, then at the completion of the sprint
+	StoryPoints *int
+	// CommittedStoryPoints are the points of a committed issue at the start of the sprint, the re-estimates do not change them
+	CommittedStoryPoints *int
+}
+
+// SprintPoints sums the story points of a sprint
+type SprintPoints struct {
+	SprintID uint
+	// IssuePoints and ClosedIssuePoints sum the issues in the sprint, and those with a closed status
+	IssuePoints       int
+	ClosedIssuePoints int
+	// CommittedPoints and CompletedPoints sum the snapshot, the committed issues and the completed ones,
+	// CommittedIssues and CompletedIssues count them
+	CommittedPoints int
+	CompletedPoints int
+	CommittedIssues int
+	CompletedIssues int
 }
 
 type CreateSprintRequest struct {
-	Number            string    `json:"number,omitempty" validate:"required"`
-	StartDate         time.Time `json:"startDate,omitempty"`
-	EndDate           time.Time `json:"endDate,omitempty"`
-	Completed         bool      `json:"completed,omitempty"`
-	MaxIssuePerSprint int       `json:"maxIssuePerSprint,omitempty"`
+	Number            string    `json:"number,omitempty" validate:"required,max=50"`
+	StartDate         time.Time `json:"startDate,omitempty" validate:"required"`
+	EndDate           time.Time `json:"endDate,omitempty" validate:"required"`
+	MaxIssuePerSprint int       `json:"maxIssuePerSprint,omitempty" validate:"min=0"`
 }
 
 type PatchSprintRequest struct {
 	ID                uint      `json:"id" validate:"required"`
 	ProjectID         int       `json:"projectId" validate:"required"`
-	Number            string    `json:"number,omitempty"`
+	Number            string    `json:"number,omitempty" validate:"max=50"`
 	StartDate         time.Time `json:"startDate,omitempty"`
 	EndDate           time.Time `json:"endDate,omitempty"`
-	Completed         bool      `json:"completed,omitempty"`
-	MaxIssuePerSprint int       `json:"maxIssuePerSprint,omitempty"`
+	MaxIssuePerSprint int       `json:"maxIssuePerSprint,omitempty" validate:"min=0"`
+}
+
+type CompleteSprintRequest struct {
+	// NextSprintID receives the unfinished issues, they go to the backlog of the project when it is 0
+	NextSprintID int `json:"nextSprintId,omitempty" validate:"min=0"`
 }
 
 type GetSprintResponse struct {
-	ID                uint      `json:"id"`
-	ProjectID         int       `json:"projectId"`
-	Number            string    `json:"number,omitempty"`
-	StartDate         time.Time `json:"startDate,omitempty"`
-	EndDate           time.Time `json:"endDate,omitempty"`
-	Completed         bool      `json:"completed"`
-	MaxIssuePerSprint int       `json:"maxIssuePerSprint,omitempty"`
-	CreatedAt         time.Time `json:"createdAt,omitempty"`
-	UpdatedAt         time.Time `json:"updatedAt,omitempty"`
+	ID                uint       `json:"id"`
+	ProjectID         int        `json:"projectId"`
+	Number            string     `json:"number,omitempty"`
+	StartDate         time.Time  `json:"startDate,omitempty"`
+	EndDate           time.Time  `json:"endDate,omitempty"`
+	Completed         bool       `json:"completed"`
+	MaxIssuePerSprint int        `json:"maxIssuePerSprint,omitempty"`
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	CompletedAt       *time.Time `json:"completedAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt,omitempty"`
+	UpdatedAt         time.Time  `json:"updatedAt,omitempty"`
+	// CommittedPoints are planned until the start, CompletedPoints are closed until the completion
+	CommittedPoints int `json:"committedPoints"`
+	CompletedPoints int `json:"completedPoints"`
+}
+
+type SprintSnapshotIssueResponse struct {
+	ID          uint   `json:"id"`
+	Type        string `json:"type,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Status      string `json:"status,omitempty"`
+	StoryPoints *int   `json:"storyPoints,omitempty"`
+}
+
+// SprintCompletionResponse lists the issues committed at the start of a sprint,
+// and what happened to them and to the issues added during the sprint
+type SprintCompletionResponse struct {
+	SprintID     uint                          `json:"sprintId"`
+	ProjectID    int                           `json:"projectId"`
+	NextSprintID int                           `json:"nextSprintId,omitempty"`
+	StartedAt    *time.Time                    `json:"startedAt,omitempty"`
+	CompletedAt  time.Time                     `json:"completedAt"`
+	Committed    []SprintSnapshotIssueResponse `json:"committed"`
+	Added        []SprintSnapshotIssueResponse `json:"added"`
+	Completed    []SprintSnapshotIssueResponse `json:"completed"`
+	CarriedOver  []SprintSnapshotIssueResponse `json:"carriedOver"`
+	Removed      []SprintSnapshotIssueResponse `json:"removed"`
+}
+
+// The units of the work of a burndown, the story points or the number of issues
+const (
+	BURNDOWN_UNIT_POINTS = "points"
+	BURNDOWN_UNIT_ISSUES = "issues"
+)
+
+// The kinds of the changes of the scope of a started sprint
+const (
+	SCOPE_CHANGE_ADDED     = "added"
+	SCOPE_CHANGE_REMOVED   = "removed"
+	SCOPE_CHANGE_ESTIMATED = "estimated"
+)
+
+// BurndownDay is the work of a sprint at the end of a day, the actual series are nil for the days to come.
+// Remaining and Completed are the work of the open and of the closed issues, and Scope their sum.
+type BurndownDay struct {
+	Date      string  `json:"date"`
+	Ideal     float64 `json:"ideal"`
+	Remaining *int    `json:"remaining,omitempty"`
+	Completed *int    `json:"completed,omitempty"`
+	Scope     *int    `json:"scope,omitempty"`
+}
+
+// BurndownScopeChange is an issue added to the sprint, removed from it or estimated again after its start,
+// Change is the work it added to the scope, negative when the scope decreased
+type BurndownScopeChange struct {
+	ChangedAt time.Time `json:"changedAt"`
+	IssueID   uint      `json:"issueId"`
+	Kind      string    `json:"kind"`
+	Change    int       `json:"change"`
+}
+
+// SprintBurndownResponse is the work of a sprint day by day, CommittedWork is the scope at its start
+type SprintBurndownResponse struct {
+	SprintID      uint                  `json:"sprintId"`
+	ProjectID     int                   `json:"projectId"`
+	Unit          string                `json:"unit"`
+	StartDate     string                `json:"startDate"`
+	EndDate       string                `json:"endDate"`
+	CommittedWork int                   `json:"committedWork"`
+	Days          []BurndownDay         `json:"days"`
+	ScopeChanges  []BurndownScopeChange `json:"scopeChanges"`
 }
 
 func (sprint Sprint) GetSprintResponseFromSprint() GetSprintResponse {
@@ -57,7 +187,69 @@ func (sprint Sprint) GetSprintResponseFromSprint() GetSprintResponse {
 		EndDate:           sprint.EndDate,
 		Completed:         sprint.Completed,
 		MaxIssuePerSprint: sprint.MaxIssuePerSprint,
+		StartedAt:         sprint.StartedAt,
+		CompletedAt:       sprint.CompletedAt,
 		CreatedAt:         sprint.CreatedAt,
 		UpdatedAt:         sprint.UpdatedAt,
 	}
 }
+
+// GetSprintResponseWithPoints adds the committed and the completed points to the response.
+// Before the start the committed points are those of the issues in the sprint,
+// and before the completion the completed points are those of its closed issues.
+func (sprint Sprint) GetSprintResponseWithPoints(points SprintPoints) GetSprintResponse {
+	response := sprint.GetSprintResponseFromSprint()
+	response.CommittedPoints = points.CommittedPoints
+	if sprint.StartedAt == nil {
+		response.CommittedPoints = points.IssuePoints
+	}
+	response.CompletedPoints = points.CompletedPoints
+	if sprint.CompletedAt == nil {
+		response.CompletedPoints = points.ClosedIssuePoints
+	}
+	return response
+}
+
+// GetSprintCompletionResponse groups the snapshot of the completed sprint
+func (sprint Sprint) GetSprintCompletionResponse(snapshot []SprintSnapshotIssue) SprintCompletionResponse {
+	response := SprintCompletionResponse{
+		SprintID:    sprint.ID,
+		ProjectID:   sprint.ProjectID,
+		StartedAt:   sprint.StartedAt,
+		Committed:   []SprintSnapshotIssueResponse{},
+		Added:       []SprintSnapshotIssueResponse{},
+		Completed:   []SprintSnapshotIssueResponse{},
+		CarriedOver: []SprintSnapshotIssueResponse{},
+		Removed:     []SprintSnapshotIssueResponse{},
+	}
+	if sprint.NextSprintID != nil {
+		response.NextSprintID = *sprint.NextSprintID
+	}
+	if sprint.CompletedAt != nil {
+		response.CompletedAt = *sprint.CompletedAt
+	}
+
+	for _, issue := range snapshot {
+		issueResponse := SprintSnapshotIssueResponse{
+			ID:          issue.IssueID,
+			Type:        issue.Type,
+			Title:       issue.Title,
+			Status:      issue.Status,
+			StoryPoints: issue.StoryPoints,
+		}
+		if issue.Committed {
+			response.Committed = append(response.Committed, issueResponse)
+		} else {
+			response.Added = append(response.Added, issueResponse)
+		}
+		switch issue.Outcome {
+		case SPRINT_OUTCOME_COMPLETED:
+			response.Completed = append(response.Completed, issueResponse)
+		case SPRINT_OUTCOME_CARRIED_OVER:
+			response.CarriedOver = append(response.CarriedOver, issueResponse)
+		case SPRINT_OUTCOME_REMOVED:
+			response.Removed = append(response.Removed, issueResponse)
+		}
+	}
+	return response
+}
@@ -0,0 +1,32 @@
+package models
+
+import "context"
+
+// StatusCheck is a dependency verified by the readiness probe.
+// Check returns a short description of the state of the dependency, or an error when it is not usable.
+type StatusCheck struct {
+	Name  string
+	Check func(ctx context.Context) (string, error)
+}
+
+const (
+	STATUS_OK       = "OK"
+	STATUS_FAILED   = "Failed"
+	STATUS_DRAINING = "Draining"
+)
+
+type StatusResponse struct {
+	Status    string                `json:"status"`
+	Name      string                `json:"name"`
+	Version   string                `json:"version"`
+	CommitSha string                `json:"commitSha"`
+	BuildTime string                `json:"buildTime"`
+	Checks    []StatusCheckResponse `json:"checks,omitempty"`
+}
+
+type StatusCheckResponse struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Message   string  `json:"message,omitempty"`
+	LatencyMs float64 `json:"latencyMs"`
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// Page selects a part of a list, a zero Limit selects every item after Offset
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// ProjectStore persists projects.
+// Implementations return internal.ErrNotFound when a project does not exist
+// and internal.ErrDuplicateKey when the project name is already taken.
+type ProjectStore interface {
+	List(ctx context.Context, page Page) ([]Project, error)
+	Get(ctx context.Context, projectId int) (Project, error)
+	Create(ctx context.Context, project *Project) error
+}
+
+// SprintStore persists sprints. Every lookup is scoped to the owning project.
+type SprintStore interface {
+	ListByProject(ctx context.Context, projectId int, page Page) ([]Sprint, error)
+	Get(ctx context.Context, projectId int, sprintId int) (Sprint, error)
+	// Create and Update return internal.ErrOverlap when the dates of the sprint overlap another sprint of the project,
+	// the check and the write are atomic
+	Create(ctx context.Context, sprint *Sprint) error
+	// Update writes the non-zero fields of sprint, it never changes the ProjectID nor the lifecycle fields
+	Update(ctx context.Context, sprint Sprint) error
+	// GetActive returns the started and not completed sprint of the project
+	GetActive(ctx context.Context, projectId int) (Sprint, error)
+	// Start records the issues of the sprint as committed, it returns internal.ErrNotFound when the sprint
+	// was already started and internal.ErrDuplicateKey when another sprint of the project is active
+	Start(ctx context.Context, projectId int, sprintId int, startedAt time.Time) error
+	// Complete completes the active sprint in one transaction: its unfinished issues are moved to
+	// nextSprintId, or to the backlog when it is 0, and the snapshot of its issues is recorded.
+	// It returns internal.ErrNotFound when the sprint is not active or the next sprint is completed.
+	Complete(ctx context.Context, projectId int, sprintId int, nextSprintId int, completedAt time.Time) error
+	// ListSnapshot returns the snapshot of the issues of a sprint, ordered by issue id
+	ListSnapshot(ctx context.Context, projectId int, sprintId int) ([]SprintSnapshotIssue, error)
+	// SumPoints sums in the database the story points of the sprints of the project, by sprint id
+	SumPoints(ctx context.Context, projectId int, sprintIds []uint) (map[uint]SprintPoints, error)
+	// CountActiveByProject counts, by project id, the sprints started and not completed
+	CountActiveByProject(ctx context.Context) (map[int]int, error)
+}
+
+// BoardStore persists the boards of the projects.
+// Get returns internal.ErrNotFound when the project has no board.
+type BoardStore interface {
+	Get(ctx context.Context, projectId int) (Board, error)
+	// Save creates the board of the project, or replaces the swimlane, the scope and the columns of its board
+	Save(ctx context.Context, board *Board) error
+}
+
+// SlaStore persists the SLA policies of the projects and the breaches flagged by the SLA checker.
+// GetPolicy returns internal.ErrNotFound when the project has no policy.
+type SlaStore interface {
+	GetPolicy(ctx context.Context, projectId int) (SlaPolicy, error)
+	// SavePolicy creates the policy of the project, or replaces the paused statuses and the targets of its policy
+	SavePolicy(ctx context.Context, policy *SlaPolicy) error
+	// ListPolicies returns the policies of every project, ordered by project id
+	ListPolicies(ctx context.Context) ([]SlaPolicy, error)
+	// ListBreaches returns the breaches flagged in the project, ordered by id
+	ListBreaches(ctx context.Context, projectId int) ([]SlaBreach, error)
+	// FlagBreach records breach, it returns false when the breach of the issue and of the kind was already flagged
+	FlagBreach(ctx context.Context, breach *SlaBreach) (bool, error)
+}
+
+// IdempotencyStore keeps the responses of the requests sent with an Idempotency-Key header.
+// Get returns internal.ErrNotFound when the key is not recorded.
+type IdempotencyStore interface {
+	// Create forgets the records created before expiredBefore, then records the key of a request being processed.
+	// It returns internal.ErrDuplicateKey when the key is already recorded.
+	Create(ctx context.Context, record *IdempotencyRecord, expiredBefore time.Time) error
+	Get(ctx context.Context, key string) (IdempotencyRecord, error)
+	// Complete stores the status code, the content type and the body of the response of the request of record.Key
+	Complete(ctx context.Context, record IdempotencyRecord) error
+	// Delete forgets the key, so that its request can be processed again
+	Delete(ctx context.Context, key string) error
+}
+
+// IssueStore persists issues. Every lookup is scoped to the owning project and sprint,
+// a sprint id of 0 stands for the backlog of the project, the issues in no sprint.
+// The creations, and the changes of the status, the points, the project or the sprint of an issue, are recorded as IssueChange.
+// The issues are ranked last in their project when they are created or moved to another project.
+type IssueStore interface {
+	// ListBySprint returns the issues of the sprint ordered by rank, the issues sharing a rank by id
+	ListBySprint(ctx context.Context, projectId int, sprintId int, page Page) ([]Issue, error)
+	Get(ctx context.Context, projectId int, sprintId int, issueId uint) (Issue, error)
+	Create(ctx context.Context, issue *Issue) error
+	// Update writes the non-zero fields of issue, it never changes the ProjectID or SprintID
+	Update(ctx context.Context, issue Issue) error
+	Move(ctx context.Context, issue Issue, targetProjectId int, targetSprintId int) error
+	// ListSprintChanges returns the changes of the issues that were once in the sprint, ordered by time,
+	// including the changes before they entered it and after they left it
+	ListSprintChanges(ctx context.Context, projectId int, sprintId int) ([]IssueChange, error)
+	// ListByProject returns the issues of the project matching filter, in the backlog and in the sprints, ordered by rank
+	ListByProject(ctx context.Context, projectId int, filter IssueFilter) ([]Issue, error)
+	// ListProjectChanges returns the changes of the issues of the project matching filter, ordered by time
+	ListProjectChanges(ctx context.Context, projectId int, filter IssueFilter) ([]IssueChange, error)
+	// GetAdjacentRanks returns the rank of the issue of the project and the closest greater rank of its other issues,
+	// or the closest lower rank when after is false, skipping excludedIssueId. The adjacent rank is empty when there is none.
+	GetAdjacentRanks(ctx context.Context, projectId int, issueId uint, after bool, excludedIssueId uint) (string, string, error)
+	// SetRank stores the rank ordering the issue among the issues of its project,
+	// the ranks of the project are rebalanced when rank grew too long
+	SetRank(ctx context.Context, projectId int, issueId uint, rank string) error
+	// MoveOnBoard sets the status of the issue and, when it is not empty, its rank in one transaction, rebalancing the ranks as SetRank.
+	// The empty status keeps the status of the issues already in column, the others take its first status.
+	// It returns internal.ErrNotFound when the issue is not in the scope of the board of the project,
+	// and internal.ErrWipLimit when column would hold more issues of the board than its WipLimit.
+	MoveOnBoard(ctx context.Context, projectId int, issueId uint, column BoardColumn, status string, rank string) error
+	// CountOpenByProject counts, by project id, the issues whose status is not one of ClosedIssueStatuses
+	CountOpenByProject(ctx context.Context) (map[int]int, error)
+	// SumOpenWork sums in the database the open issues of the project, in the backlog and in the sprints
+	SumOpenWork(ctx context.Context, projectId int) (OpenWork, error)
+}
+
+// IssueFilter selects the issues of a type or of an assignee, the empty fields select every issue
+type IssueFilter struct {
+	Type     string
+	Assignee string
+	// Open keeps the issues whose status is not one of ClosedIssueStatuses
+	Open bool
+}
+
+type Stores struct {
+	Projects    ProjectStore
+	Sprints     SprintStore
+	Issues      IssueStore
+	Boards      BoardStore
+	Sla         SlaStore
+	Idempotency IdempotencyStore
+}
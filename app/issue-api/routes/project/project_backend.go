@@ -1,19 +1,17 @@
 package project
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	models "issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
-
-	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
-func getProjects(database *gorm.DB) ([]models.Project, error) {
-	var projects []models.Project
-	result := database.Find(&projects)
-	if result.Error != nil {
+func getProjects(ctx context.Context, stores models.Stores, page models.Page) ([]models.Project, error) {
+	projects, err := stores.Projects.List(ctx, page)
+	if err != nil {
 		return []models.Project{}, &models.ErrorResponse{
 			ErrorMessage: "Error retrieving projects",
 			ErrorCode:    500,
@@ -22,19 +20,45 @@ func getProjects(database *gorm.DB) ([]models.Project, error) {
 	return projects, nil
 }
 
-func createProject(database *gorm.DB, project models.Project) (uint, error) {
-	result := database.Create(&project)
+func validateProjectSettings(project *models.Project) error {
+	if project.Timezone == "" {
+		project.Timezone = models.DEFAULT_PROJECT_TIMEZONE
+	}
+	if project.EstimationScale == "" {
+		project.EstimationScale = models.ESTIMATION_SCALE_FIBONACCI
+	}
+	if _, err := time.LoadLocation(project.Timezone); err != nil {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Timezone \"%s\" is unknown", project.Timezone),
+			ErrorCode:    400,
+		}
+	}
+	if project.SprintMaxDays > 0 && project.SprintMinDays > project.SprintMaxDays {
+		return &models.ErrorResponse{
+			ErrorMessage: "The minimum length of the sprints must not be greater than their maximum length",
+			ErrorCode:    400,
+		}
+	}
+	return nil
+}
+
+func createProject(ctx context.Context, stores models.Stores, project models.Project) (uint, error) {
+	if err := validateProjectSettings(&project); err != nil {
+		return 0, err
+	}
+
+	err := stores.Projects.Create(ctx, &project)
 
-	if result.Error != nil {
-		log.WithField("error", result.Error.Error()).Error("Error creating new project")
-		if internal.IsDuplicateKeyError(result.Error) {
+	if err != nil {
+		internal.RequestLogger(ctx).WithField("error", err.Error()).Error("Error creating new project")
+		if internal.IsDuplicateKeyError(err) {
 			return 0, &models.ErrorResponse{
 				ErrorMessage: fmt.Sprintf("Project with name \"%s\" already exists", project.Name),
 				ErrorCode:    409,
 			}
 		}
 		return 0, &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 
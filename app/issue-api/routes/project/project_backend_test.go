@@ -1,7 +1,7 @@
 package project
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	models "issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
@@ -17,34 +17,28 @@ func TestCreateProject(testCase *testing.T) {
 		log.Fatalf("Error reading env configuration: %s", err.Error())
 		return
 	}
-	database, err := internal.ConnectDatabase(config)
-
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
-	expectedResponse := 1
-	expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
 	testCase.Run("createProject return the new id", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
 		inputProject := models.Project{
 			Name:   "",
 			Type:   "",
 			Client: "",
 		}
-		response, err := createProject(database, inputProject)
+		response, err := createProject(context.Background(), stores, inputProject)
 
 		var foundProject models.Project
 
 		database.First(&foundProject)
 
 		require.Equal(t, nil, err)
-		require.Equal(t, string(expectedJsonReponse), fmt.Sprint(response))
+		require.Equal(t, foundProject.ID, response)
 	})
 
 	testCase.Run("createProject with specific name and type", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
 		expectedProjectName := internal.GetRandomStringName(10)
 		expectedType := "project-type"
 		expectedClient := "project-client"
@@ -53,7 +47,7 @@ func TestCreateProject(testCase *testing.T) {
 			Type:   expectedType,
 			Client: expectedClient,
 		}
-		createProject(database, inputProject)
+		createProject(context.Background(), stores, inputProject)
 
 		var foundProject models.Project
 
@@ -66,7 +60,8 @@ func TestCreateProject(testCase *testing.T) {
 	})
 
 	testCase.Run("create two projects", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
 
 		inputProject1 := models.Project{
 			Name:   internal.GetRandomStringName(10),
@@ -76,8 +71,8 @@ func TestCreateProject(testCase *testing.T) {
 		inputProject2 := inputProject1
 		inputProject2.Name = internal.GetRandomStringName(10)
 
-		createProject(database, inputProject1)
-		createProject(database, inputProject2)
+		createProject(context.Background(), stores, inputProject1)
+		createProject(context.Background(), stores, inputProject2)
 
 		var foundProjects []models.Project
 
@@ -88,7 +83,7 @@ func TestCreateProject(testCase *testing.T) {
 	})
 
 	testCase.Run("createProject returns error if project with same name already exits", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
 
 		expectedProjectName := internal.GetRandomStringName(10)
 		expectedError := fmt.Sprintf("Project with name \"%s\" already exists", expectedProjectName)
@@ -101,11 +96,11 @@ func TestCreateProject(testCase *testing.T) {
 			Client: expectedClient,
 		}
 
-		_, err1 := createProject(database, inputProject)
+		_, err1 := createProject(context.Background(), stores, inputProject)
 
 		require.Equal(t, nil, err1)
 
-		_, err2 := createProject(database, inputProject)
+		_, err2 := createProject(context.Background(), stores, inputProject)
 
 		require.Equal(t, expectedError, err2.Error())
 	})
@@ -117,15 +112,9 @@ func TestGetProjects(testCase *testing.T) {
 		log.Fatalf("Error reading env configuration: %s", err.Error())
 		return
 	}
-	database, err := internal.ConnectDatabase(config)
-
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
 
 	testCase.Run("getProjects return a list of projects", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
 		expectedProjectName := internal.GetRandomStringName(10)
 		expectedType := "project-type"
 		expectedClient := "project-client"
@@ -134,7 +123,7 @@ func TestGetProjects(testCase *testing.T) {
 			Type:   expectedType,
 			Client: expectedClient,
 		}
-		createProject(database, inputProject)
+		projectId, _ := createProject(context.Background(), stores, inputProject)
 
 		expectedResponse := []models.Project{
 			{
@@ -144,12 +133,75 @@ func TestGetProjects(testCase *testing.T) {
 			},
 		}
 
-		foundProjects, err := getProjects(database)
+		foundProjects, err := getProjects(context.Background(), stores, models.Page{})
 
 		require.Equal(t, nil, err)
 		require.Equal(t, expectedResponse[0].Name, foundProjects[0].Name)
 		require.Equal(t, expectedResponse[0].Type, foundProjects[0].Type)
 		require.Equal(t, expectedResponse[0].Client, foundProjects[0].Client)
-		require.Equal(t, uint(1), foundProjects[0].ID)
+		require.Equal(t, projectId, foundProjects[0].ID)
+	})
+
+	testCase.Run("getProjects return a page of projects", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectIds := []uint{}
+		for index := 0; index < 5; index++ {
+			projectId, err := createProject(context.Background(), stores, models.Project{Name: internal.GetRandomStringName(10), Type: "project-type"})
+			require.NoError(t, err)
+			projectIds = append(projectIds, projectId)
+		}
+
+		for _, testPage := range []struct {
+			page     models.Page
+			expected []uint
+		}{
+			{models.Page{Limit: 2}, projectIds[:2]},
+			{models.Page{Limit: 2, Offset: 2}, projectIds[2:4]},
+			{models.Page{Limit: 2, Offset: 4}, projectIds[4:]},
+			{models.Page{Offset: 3}, projectIds[3:]},
+			{models.Page{Limit: 2, Offset: 5}, []uint{}},
+		} {
+			foundProjects, err := getProjects(context.Background(), stores, testPage.page)
+			require.NoError(t, err)
+			foundIds := []uint{}
+			for _, project := range foundProjects {
+				foundIds = append(foundIds, project.ID)
+			}
+			require.Equal(t, testPage.expected, foundIds, "page %+v", testPage.page)
+		}
+	})
+}
+
+func TestProjectSettings(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	testCase.Run("createProject defaults the timezone to UTC", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		projectId, err := createProject(context.Background(), stores, models.Project{Name: "project-name", Type: "project-type"})
+
+		require.Equal(t, nil, err)
+		project, _ := stores.Projects.Get(context.Background(), int(projectId))
+		require.Equal(t, models.DEFAULT_PROJECT_TIMEZONE, project.Timezone)
+	})
+
+	testCase.Run("createProject returns 400 if the timezone is unknown", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		_, err := createProject(context.Background(), stores, models.Project{Name: "project-name", Type: "project-type", Timezone: "Europe/Atlantis"})
+
+		require.Equal(t, "Timezone \"Europe/Atlantis\" is unknown", err.Error())
+	})
+
+	testCase.Run("createProject returns 400 if the minimum sprint length is greater than the maximum", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		_, err := createProject(context.Background(), stores, models.Project{Name: "project-name", Type: "project-type", SprintMinDays: 15, SprintMaxDays: 14})
+
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
 	})
 }
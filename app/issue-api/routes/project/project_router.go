@@ -2,18 +2,17 @@ package project
 
 import (
 	"issue-service/app/issue-api/routes/models"
+	"net/http"
 	"strings"
-
-	"gorm.io/gorm"
 )
 
 type projectRouter struct {
-	routes   models.Routes
-	database *gorm.DB
+	routes models.Routes
+	stores models.Stores
 }
 
-func NewRouter(db *gorm.DB) models.Router {
-	r := &projectRouter{database: db}
+func NewRouter(stores models.Stores) models.Router {
+	r := &projectRouter{stores: stores}
 	r.initRoutes()
 	return r
 }
@@ -30,13 +29,23 @@ func (r *projectRouter) initRoutes() {
 			Method:      strings.ToUpper("Post"),
 			Pattern:     "/v1/projects",
 			HandlerFunc: createAddProjectHandler,
+			Summary:     "Create a project",
+			RequestBody: models.CreateProjectRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The id of the new project", Body: models.CreateResponse{}},
+			}, http.StatusBadRequest, http.StatusConflict, http.StatusInternalServerError),
 		},
 
 		models.Route{
-			Name:        "GetProjects",
-			Method:      strings.ToUpper("Get"),
-			Pattern:     "/v1/projects",
-			HandlerFunc: createGetProjectsHandler,
+			Name:            "GetProjects",
+			Method:          strings.ToUpper("Get"),
+			Pattern:         "/v1/projects",
+			HandlerFunc:     createGetProjectsHandler,
+			Summary:         "List the projects",
+			QueryParameters: models.PageParameters,
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The projects", Body: []models.Project{}},
+			}, http.StatusBadRequest, http.StatusInternalServerError),
 		},
 	}
 }
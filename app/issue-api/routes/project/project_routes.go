@@ -5,16 +5,13 @@ import (
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
 	"net/http"
-
-	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 func getProjectFromRequestBody(r *http.Request) (models.CreateProjectRequest, error) {
 	var requestProject models.CreateProjectRequest
 	err := json.NewDecoder(r.Body).Decode(&requestProject)
 	if err != nil {
-		log.WithField("error", err.Error()).Error("Error reading request body")
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
 		return models.CreateProjectRequest{}, &models.ErrorResponse{
 			ErrorMessage: "Error reading request body",
 			ErrorCode:    400,
@@ -23,7 +20,7 @@ func getProjectFromRequestBody(r *http.Request) (models.CreateProjectRequest, er
 	return requestProject, nil
 }
 
-func createAddProjectHandler(database *gorm.DB) http.HandlerFunc {
+func createAddProjectHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 
@@ -40,13 +37,18 @@ func createAddProjectHandler(database *gorm.DB) http.HandlerFunc {
 		}
 
 		requestProject := models.Project{
-			Name:   requestBody.Name,
-			Client: requestBody.Client,
-			Type:   requestBody.Type,
+			Name:            requestBody.Name,
+			Client:          requestBody.Client,
+			Type:            requestBody.Type,
+			Timezone:        requestBody.Timezone,
+			SprintMinDays:   requestBody.SprintMinDays,
+			SprintMaxDays:   requestBody.SprintMaxDays,
+			EstimationScale: requestBody.EstimationScale,
 		}
 
 		projectId, err := createProject(
-			database,
+			r.Context(),
+			stores,
 			requestProject,
 		)
 		if err != nil {
@@ -64,18 +66,24 @@ func createAddProjectHandler(database *gorm.DB) http.HandlerFunc {
 	}
 }
 
-func createGetProjectsHandler(database *gorm.DB) http.HandlerFunc {
+func createGetProjectsHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 
-		projects, err := getProjects(database)
+		page, err := internal.GetPageFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		projects, err := getProjects(r.Context(), stores, page)
 		if err != nil {
 			internal.LogAndReturnErrorResponse(err, w)
 			return
 		}
 		response, err := json.Marshal(projects)
 		if err != nil {
-			log.WithField("error", err.Error()).Error("Error marshaling the response")
+			internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error marshaling the response")
 			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
 				ErrorMessage: "Error mashaling the response",
 				ErrorCode:    500,
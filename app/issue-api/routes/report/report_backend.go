@@ -0,0 +1,193 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	DEFAULT_VELOCITY_SPRINTS = 10
+	MAX_VELOCITY_SPRINTS     = 100
+	DEFAULT_VELOCITY_WINDOW  = 3
+	DEFAULT_SIMULATIONS      = 10000
+	MAX_SIMULATIONS          = 100000
+	// MAX_FORECAST_SPRINTS stops the simulations of the teams completing little work
+	MAX_FORECAST_SPRINTS = 1000
+)
+
+// reportPercentiles are the percentiles of the simulated numbers of sprints in the forecast, and of the flow times
+var reportPercentiles = []int{50, 70, 85, 95}
+
+type velocityOptions struct {
+	unit        string
+	sprints     int
+	window      int
+	simulations int
+}
+
+func getVelocityReport(ctx context.Context, stores models.Stores, projectId int, options velocityOptions) (models.VelocityReportResponse, error) {
+	if options.unit == "" {
+		options.unit = models.BURNDOWN_UNIT_POINTS
+	}
+	if options.unit != models.BURNDOWN_UNIT_POINTS && options.unit != models.BURNDOWN_UNIT_ISSUES {
+		return models.VelocityReportResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The unit must be %s or %s, got \"%s\"", models.BURNDOWN_UNIT_POINTS, models.BURNDOWN_UNIT_ISSUES, options.unit),
+			ErrorCode:    400,
+		}
+	}
+	if _, err := internal.GetProjectById(ctx, stores, projectId); err != nil {
+		return models.VelocityReportResponse{}, err
+	}
+
+	sprints, err := stores.Sprints.ListByProject(ctx, projectId, models.Page{})
+	if err != nil {
+		return models.VelocityReportResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	completedSprints := []models.Sprint{}
+	for _, sprint := range sprints {
+		if sprint.CompletedAt != nil {
+			completedSprints = append(completedSprints, sprint)
+		}
+	}
+	sort.SliceStable(completedSprints, func(i, j int) bool {
+		return completedSprints[i].CompletedAt.Before(*completedSprints[j].CompletedAt)
+	})
+	if len(completedSprints) > options.sprints {
+		completedSprints = completedSprints[len(completedSprints)-options.sprints:]
+	}
+
+	sprintIds := make([]uint, 0, len(completedSprints))
+	for _, sprint := range completedSprints {
+		sprintIds = append(sprintIds, sprint.ID)
+	}
+	points, err := stores.Sprints.SumPoints(ctx, projectId, sprintIds)
+	if err != nil {
+		return models.VelocityReportResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	openWork, err := stores.Issues.SumOpenWork(ctx, projectId)
+	if err != nil {
+		return models.VelocityReportResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+
+	report := models.VelocityReportResponse{
+		ProjectID: projectId,
+		Unit:      options.unit,
+		Window:    options.window,
+		Sprints:   []models.VelocitySprint{},
+	}
+	velocities := []int{}
+	for _, sprint := range completedSprints {
+		velocitySprint := models.VelocitySprint{
+			SprintID:    sprint.ID,
+			Number:      sprint.Number,
+			StartDate:   sprint.StartDate,
+			EndDate:     sprint.EndDate,
+			CompletedAt: *sprint.CompletedAt,
+			Committed:   points[sprint.ID].CommittedPoints,
+			Completed:   points[sprint.ID].CompletedPoints,
+		}
+		if options.unit == models.BURNDOWN_UNIT_ISSUES {
+			velocitySprint.Committed = points[sprint.ID].CommittedIssues
+			velocitySprint.Completed = points[sprint.ID].CompletedIssues
+		}
+		velocities = append(velocities, velocitySprint.Completed)
+		velocitySprint.RollingAverage = roundStatistic(getAverage(velocities[max(0, len(velocities)-options.window):]))
+		report.Sprints = append(report.Sprints, velocitySprint)
+	}
+	report.AverageVelocity = roundStatistic(getAverage(velocities))
+	report.StandardDeviation = roundStatistic(getStandardDeviation(velocities))
+
+	report.Forecast = models.VelocityForecast{
+		RemainingWork:     openWork.Points,
+		UnestimatedIssues: openWork.UnestimatedIssues,
+		Simulations:       options.simulations,
+	}
+	if options.unit == models.BURNDOWN_UNIT_ISSUES {
+		report.Forecast.RemainingWork = openWork.Issues
+		report.Forecast.UnestimatedIssues = 0
+	}
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	report.Forecast.Percentiles = forecastSprints(velocities, report.Forecast.RemainingWork, options.simulations, random)
+	return report, nil
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// roundStatistic keeps two decimals
+func roundStatistic(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+func getAverage(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, value := range values {
+		sum += value
+	}
+	return float64(sum) / float64(len(values))
+}
+
+// getStandardDeviation returns the sample standard deviation, 0 with less than 2 values
+func getStandardDeviation(values []int) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	average := getAverage(values)
+	squares := 0.0
+	for _, value := range values {
+		squares += (float64(value) - average) * (float64(value) - average)
+	}
+	return math.Sqrt(squares / float64(len(values)-1))
+}
+
+// forecastSprints simulates the sprints needed by remainingWork, every simulated sprint completing the work
+// of a past sprint drawn at random, and returns the percentiles of the numbers of sprints.
+// It returns no percentile when no past sprint completed any work.
+func forecastSprints(velocities []int, remainingWork int, simulations int, random *rand.Rand) []models.ForecastPercentile {
+	percentiles := []models.ForecastPercentile{}
+	hasVelocity := false
+	for _, velocity := range velocities {
+		hasVelocity = hasVelocity || velocity > 0
+	}
+	if !hasVelocity {
+		return percentiles
+	}
+
+	results := make([]int, simulations)
+	for simulation := range results {
+		work, sprints := remainingWork, 0
+		for work > 0 && sprints < MAX_FORECAST_SPRINTS {
+			work -= velocities[random.Intn(len(velocities))]
+			sprints++
+		}
+		results[simulation] = sprints
+	}
+	sort.Ints(results)
+	for _, percentile := range reportPercentiles {
+		index := int(math.Ceil(float64(percentile)/100*float64(simulations))) - 1
+		percentiles = append(percentiles, models.ForecastPercentile{Percentile: percentile, Sprints: results[max(0, index)]})
+	}
+	return percentiles
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"context"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"log"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVelocityStatistics(testCase *testing.T) {
+	testCase.Run("average and sample standard deviation", func(t *testing.T) {
+		require.Equal(t, 0.0, getAverage([]int{}))
+		require.Equal(t, 5.0, getAverage([]int{2, 4, 9}))
+		require.Equal(t, 0.0, getStandardDeviation([]int{8}))
+		require.Equal(t, 3.61, roundStatistic(getStandardDeviation([]int{2, 4, 9})))
+	})
+
+	testCase.Run("forecastSprints returns the percentiles of the simulated sprints", func(t *testing.T) {
+		random := rand.New(rand.NewSource(1))
+
+		percentiles := forecastSprints([]int{10}, 35, 100, random)
+		require.Equal(t, []models.ForecastPercentile{
+			{Percentile: 50, Sprints: 4},
+			{Percentile: 70, Sprints: 4},
+			{Percentile: 85, Sprints: 4},
+			{Percentile: 95, Sprints: 4},
+		}, percentiles)
+
+		percentiles = forecastSprints([]int{5, 10, 20}, 40, 1000, random)
+		require.Equal(t, 4, len(percentiles))
+		for index, percentile := range percentiles {
+			require.True(t, percentile.Sprints >= 2 && percentile.Sprints <= 8)
+			if index > 0 {
+				require.True(t, percentile.Sprints >= percentiles[index-1].Sprints)
+			}
+		}
+
+		require.Equal(t, 0, forecastSprints([]int{5}, 0, 10, random)[0].Sprints)
+		require.Equal(t, []models.ForecastPercentile{}, forecastSprints([]int{0, 0}, 10, 10, random))
+	})
+}
+
+func TestGetVelocityReport(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+	options := velocityOptions{sprints: DEFAULT_VELOCITY_SPRINTS, window: 2, simulations: 1000}
+
+	// completeSprintWithPoints runs a sprint whose issues of points are completed, except the last one
+	completeSprintWithPoints := func(t *testing.T, stores models.Stores, projectId int, number string, completedAt time.Time, points ...int) {
+		ctx := context.Background()
+		sprintId := int(internal.CreateTestSprint(stores, number, projectId))
+		for index := range points {
+			status := "Done"
+			if index == len(points)-1 {
+				status = "To Do"
+			}
+			issue := models.Issue{ProjectID: projectId, SprintID: sprintId, Type: "Task", Title: "Estimated", Status: status, StoryPoints: &points[index]}
+			require.Equal(t, nil, stores.Issues.Create(ctx, &issue))
+		}
+		require.Equal(t, nil, stores.Sprints.Start(ctx, projectId, sprintId, completedAt.AddDate(0, 0, -14)))
+		require.Equal(t, nil, stores.Sprints.Complete(ctx, projectId, sprintId, 0, completedAt))
+	}
+
+	testCase.Run("the velocity of the completed sprints and the forecast of the open issues", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := int(internal.CreateTestProject(stores))
+		completedAt := time.Date(2022, time.October, 14, 18, 0, 0, 0, time.UTC)
+		completeSprintWithPoints(t, stores, projectId, "1", completedAt, 5, 3, 8)
+		completeSprintWithPoints(t, stores, projectId, "3", completedAt.AddDate(0, 0, 28), 13, 2)
+		completeSprintWithPoints(t, stores, projectId, "2", completedAt.AddDate(0, 0, 14), 5, 5, 1)
+		internal.CreateTestSprint(stores, "planned", projectId)
+
+		report, err := getVelocityReport(context.Background(), stores, projectId, options)
+		require.Equal(t, nil, err)
+		require.Equal(t, models.BURNDOWN_UNIT_POINTS, report.Unit)
+		require.Equal(t, 3, len(report.Sprints))
+		expectedSprints := []struct {
+			number         string
+			committed      int
+			completed      int
+			rollingAverage float64
+		}{{"1", 16, 8, 8}, {"2", 11, 10, 9}, {"3", 15, 13, 11.5}}
+		for index, expected := range expectedSprints {
+			require.Equal(t, expected.number, report.Sprints[index].Number)
+			require.Equal(t, expected.committed, report.Sprints[index].Committed)
+			require.Equal(t, expected.completed, report.Sprints[index].Completed)
+			require.Equal(t, expected.rollingAverage, report.Sprints[index].RollingAverage)
+		}
+		require.Equal(t, 10.33, report.AverageVelocity)
+		require.Equal(t, 2.52, report.StandardDeviation)
+
+		// the carried over issues are in the backlog, 8 + 1 + 2 points, a single sprint completes them only with the velocity 13
+		require.Equal(t, 11, report.Forecast.RemainingWork)
+		require.Equal(t, 1000, report.Forecast.Simulations)
+		require.Equal(t, 4, len(report.Forecast.Percentiles))
+		require.Equal(t, 2, report.Forecast.Percentiles[0].Sprints)
+		require.Equal(t, 2, report.Forecast.Percentiles[3].Sprints)
+
+		options := options
+		options.unit = models.BURNDOWN_UNIT_ISSUES
+		options.sprints = 2
+		report, err = getVelocityReport(context.Background(), stores, projectId, options)
+		require.Equal(t, nil, err)
+		require.Equal(t, 2, len(report.Sprints))
+		require.Equal(t, "2", report.Sprints[0].Number)
+		require.Equal(t, 3, report.Sprints[0].Committed)
+		require.Equal(t, 2, report.Sprints[0].Completed)
+		require.Equal(t, 3, report.Forecast.RemainingWork)
+	})
+
+	testCase.Run("the project without completed sprints has no forecast", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		report, err := getVelocityReport(context.Background(), stores, int(projectId), options)
+		require.Equal(t, nil, err)
+		require.Equal(t, []models.VelocitySprint{}, report.Sprints)
+		require.Equal(t, 0.0, report.AverageVelocity)
+		require.Equal(t, 1, report.Forecast.UnestimatedIssues)
+		require.Equal(t, []models.ForecastPercentile{}, report.Forecast.Percentiles)
+	})
+
+	testCase.Run("getVelocityReport return error", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		_, err := getVelocityReport(context.Background(), stores, 100, options)
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Project with id \"100\" does not exists", ErrorCode: 404}, err)
+
+		options := options
+		options.unit = "hours"
+		_, err = getVelocityReport(context.Background(), stores, 100, options)
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+	})
+}
@@ -0,0 +1,258 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DEFAULT_FLOW_DAYS = 30
+	MAX_FLOW_DAYS     = 366
+)
+
+// dateLayout is the format of the days in the query parameters and in the reports
+const dateLayout = "2006-01-02"
+
+type flowOptions struct {
+	from   string
+	to     string
+	filter models.IssueFilter
+}
+
+func getFlowReport(ctx context.Context, stores models.Stores, projectId int, options flowOptions) (models.FlowReportResponse, error) {
+	project, err := internal.GetProjectById(ctx, stores, projectId)
+	if err != nil {
+		return models.FlowReportResponse{}, err
+	}
+	location := project.GetLocation()
+	now := time.Now()
+	firstDay, lastDay, err := parseFlowDays(options.from, options.to, now, location)
+	if err != nil {
+		return models.FlowReportResponse{}, err
+	}
+
+	issues, err := stores.Issues.ListByProject(ctx, projectId, options.filter)
+	if err != nil {
+		return models.FlowReportResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	changes, err := stores.Issues.ListProjectChanges(ctx, projectId, options.filter)
+	if err != nil {
+		return models.FlowReportResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+
+	report := buildFlowReport(projectId, issues, changes, firstDay, lastDay, now)
+	report.Type, report.Assignee = options.filter.Type, options.filter.Assignee
+	return report, nil
+}
+
+// parseFlowDays returns the beginnings of the first and the last day of the range in location.
+// The range ends today and lasts DEFAULT_FLOW_DAYS days when from and to are missing.
+func parseFlowDays(from string, to string, now time.Time, location *time.Location) (time.Time, time.Time, error) {
+	year, month, day := now.In(location).Date()
+	lastDay := time.Date(year, month, day, 0, 0, 0, 0, location)
+	parseDay := func(name string, value string) (time.Time, error) {
+		parsedDay, err := time.ParseInLocation(dateLayout, value, location)
+		if err != nil {
+			return time.Time{}, &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The %s date must be a YYYY-MM-DD day, got \"%s\"", name, value),
+				ErrorCode:    400,
+			}
+		}
+		return parsedDay, nil
+	}
+
+	var err error
+	if to != "" {
+		if lastDay, err = parseDay("to", to); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	firstDay := lastDay.AddDate(0, 0, 1-DEFAULT_FLOW_DAYS)
+	if from != "" {
+		if firstDay, err = parseDay("from", from); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if firstDay.After(lastDay) {
+		return time.Time{}, time.Time{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The from date %s is after the to date %s", firstDay.Format(dateLayout), lastDay.Format(dateLayout)),
+			ErrorCode:    400,
+		}
+	}
+	if !firstDay.AddDate(0, 0, MAX_FLOW_DAYS).After(lastDay) {
+		return time.Time{}, time.Time{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The range must not exceed %d days", MAX_FLOW_DAYS),
+			ErrorCode:    400,
+		}
+	}
+	return firstDay, lastDay, nil
+}
+
+// getStatusCategory orders the statuses to do, in progress and closed
+func getStatusCategory(status string) int {
+	if models.IsTodoIssueStatus(status) {
+		return 0
+	}
+	if models.IsClosedIssueStatus(status) {
+		return 2
+	}
+	return 1
+}
+
+func getDays(duration time.Duration) float64 {
+	return roundStatistic(duration.Hours() / 24)
+}
+
+// getFlowIssue reads the times of a closed issue in its changes, it returns false when the issue is not closed.
+// The issue is completed by the last change to a closed status, the reopened issues are completed again.
+func getFlowIssue(issue models.Issue, changes []models.IssueChange) (models.FlowIssue, bool) {
+	if len(changes) == 0 || !models.IsClosedIssueStatus(changes[len(changes)-1].Status) {
+		return models.FlowIssue{}, false
+	}
+	completedIndex := len(changes) - 1
+	for completedIndex > 0 && models.IsClosedIssueStatus(changes[completedIndex-1].Status) {
+		completedIndex--
+	}
+
+	flowIssue := models.FlowIssue{
+		IssueID:     issue.ID,
+		Title:       issue.Title,
+		Type:        issue.Type,
+		Assignee:    issue.Assignee,
+		CreatedAt:   changes[0].ChangedAt,
+		CompletedAt: changes[completedIndex].ChangedAt,
+	}
+	flowIssue.LeadTimeDays = getDays(flowIssue.CompletedAt.Sub(flowIssue.CreatedAt))
+	for _, change := range changes[:completedIndex] {
+		if getStatusCategory(change.Status) == 1 {
+			startedAt := change.ChangedAt
+			cycleTime := getDays(flowIssue.CompletedAt.Sub(startedAt))
+			flowIssue.StartedAt, flowIssue.CycleTimeDays = &startedAt, &cycleTime
+			break
+		}
+	}
+	return flowIssue, true
+}
+
+// getFlowTimeSummary returns the average and the nearest rank percentiles of times
+func getFlowTimeSummary(times []float64) models.FlowTimeSummary {
+	summary := models.FlowTimeSummary{Issues: len(times), Percentiles: []models.FlowTimePercentile{}}
+	if len(times) == 0 {
+		return summary
+	}
+	sortedTimes := append([]float64{}, times...)
+	sort.Float64s(sortedTimes)
+	sum := 0.0
+	for _, value := range sortedTimes {
+		sum += value
+	}
+	summary.AverageDays = roundStatistic(sum / float64(len(sortedTimes)))
+	for _, percentile := range reportPercentiles {
+		index := int(math.Ceil(float64(percentile)/100*float64(len(sortedTimes)))) - 1
+		summary.Percentiles = append(summary.Percentiles, models.FlowTimePercentile{Percentile: percentile, Days: sortedTimes[max(0, index)]})
+	}
+	return summary
+}
+
+// buildFlowReport computes the times of the issues completed from firstDay to the end of lastDay,
+// and counts the issues of the project by status at the end of each day until now
+func buildFlowReport(projectId int, issues []models.Issue, changes []models.IssueChange, firstDay time.Time, lastDay time.Time, now time.Time) models.FlowReportResponse {
+	report := models.FlowReportResponse{
+		ProjectID:      projectId,
+		From:           firstDay.Format(dateLayout),
+		To:             lastDay.Format(dateLayout),
+		Issues:         []models.FlowIssue{},
+		Statuses:       []string{},
+		CumulativeFlow: []models.CumulativeFlowDay{},
+	}
+
+	issueChanges := map[uint][]models.IssueChange{}
+	for _, change := range changes {
+		issueChanges[change.IssueID] = append(issueChanges[change.IssueID], change)
+	}
+	endOfRange := lastDay.AddDate(0, 0, 1)
+	leadTimes, cycleTimes := []float64{}, []float64{}
+	for _, issue := range issues {
+		flowIssue, closed := getFlowIssue(issue, issueChanges[issue.ID])
+		if !closed || flowIssue.CompletedAt.Before(firstDay) || !flowIssue.CompletedAt.Before(endOfRange) {
+			continue
+		}
+		report.Issues = append(report.Issues, flowIssue)
+		leadTimes = append(leadTimes, flowIssue.LeadTimeDays)
+		if flowIssue.CycleTimeDays != nil {
+			cycleTimes = append(cycleTimes, *flowIssue.CycleTimeDays)
+		}
+	}
+	sort.SliceStable(report.Issues, func(i, j int) bool { return report.Issues[i].CompletedAt.Before(report.Issues[j].CompletedAt) })
+	report.LeadTime = getFlowTimeSummary(leadTimes)
+	report.CycleTime = getFlowTimeSummary(cycleTimes)
+
+	// the statuses are ordered by category, then by their first change
+	statusOrder := map[string]int{}
+	states := map[uint]models.IssueChange{}
+	next := 0
+	for day := firstDay; !day.After(lastDay) && !day.After(now); day = day.AddDate(0, 0, 1) {
+		endOfDay := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		if endOfDay.After(now) {
+			endOfDay = now
+		}
+		for ; next < len(changes) && !changes[next].ChangedAt.After(endOfDay); next++ {
+			if _, ok := statusOrder[changes[next].Status]; !ok {
+				statusOrder[changes[next].Status] = len(statusOrder)
+			}
+			states[changes[next].IssueID] = changes[next]
+		}
+
+		flowDay := models.CumulativeFlowDay{Date: day.Format(dateLayout), Statuses: map[string]int{}}
+		for _, state := range states {
+			// the issues moved to the project later are counted from their move
+			if state.ProjectID == projectId {
+				flowDay.Statuses[state.Status]++
+			}
+		}
+		report.CumulativeFlow = append(report.CumulativeFlow, flowDay)
+	}
+
+	for _, flowDay := range report.CumulativeFlow {
+		for status := range flowDay.Statuses {
+			if !contains(report.Statuses, status) {
+				report.Statuses = append(report.Statuses, status)
+			}
+		}
+	}
+	sort.Slice(report.Statuses, func(i, j int) bool {
+		first, second := report.Statuses[i], report.Statuses[j]
+		if getStatusCategory(first) != getStatusCategory(second) {
+			return getStatusCategory(first) < getStatusCategory(second)
+		}
+		return statusOrder[first] < statusOrder[second]
+	})
+	for _, flowDay := range report.CumulativeFlow {
+		for _, status := range report.Statuses {
+			if _, ok := flowDay.Statuses[status]; !ok {
+				flowDay.Statuses[status] = 0
+			}
+		}
+	}
+	return report
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
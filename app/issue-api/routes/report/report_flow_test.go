@@ -0,0 +1,153 @@
+package report
+
+import (
+	"context"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFlowReport(testCase *testing.T) {
+	at := func(day int, hour int) time.Time {
+		return time.Date(2022, time.October, day, hour, 0, 0, 0, time.UTC)
+	}
+	days := func(value float64) *float64 {
+		return &value
+	}
+	issues := []models.Issue{
+		{ID: 1, Type: "Task", Title: "Started", Assignee: "alice"},
+		{ID: 2, Type: "Bug", Title: "Closed at once", Assignee: "bob"},
+		{ID: 3, Type: "Task", Title: "Open"},
+		{ID: 4, Type: "Task", Title: "Closed before the range"},
+		{ID: 5, Type: "Task", Title: "Reopened"},
+	}
+	changes := []models.IssueChange{
+		{IssueID: 4, ProjectID: 1, Status: "To Do", ChangedAt: time.Date(2022, time.September, 20, 9, 0, 0, 0, time.UTC)},
+		{IssueID: 4, ProjectID: 1, Status: "Done", ChangedAt: time.Date(2022, time.September, 25, 9, 0, 0, 0, time.UTC)},
+		{IssueID: 5, ProjectID: 1, Status: "To Do", ChangedAt: at(1, 0)},
+		{IssueID: 1, ProjectID: 1, Status: "To Do", ChangedAt: at(1, 9)},
+		{IssueID: 3, ProjectID: 1, Status: "To Do", ChangedAt: at(1, 12)},
+		{IssueID: 5, ProjectID: 1, Status: "In Progress", ChangedAt: at(1, 12)},
+		{IssueID: 2, ProjectID: 1, Status: "To Do", ChangedAt: at(2, 0)},
+		{IssueID: 5, ProjectID: 1, Status: "Done", ChangedAt: at(2, 0)},
+		{IssueID: 1, ProjectID: 1, Status: "In Progress", ChangedAt: at(2, 9)},
+		{IssueID: 5, ProjectID: 1, Status: "In Progress", ChangedAt: at(2, 12)},
+		{IssueID: 3, ProjectID: 1, Status: "In Progress", ChangedAt: at(3, 10)},
+		{IssueID: 2, ProjectID: 1, Status: "Done", ChangedAt: at(3, 12)},
+		{IssueID: 1, ProjectID: 1, SprintID: 4, Status: "In Progress", ChangedAt: at(3, 14)},
+		{IssueID: 1, ProjectID: 1, SprintID: 4, Status: "Done", ChangedAt: at(4, 9)},
+		{IssueID: 5, ProjectID: 1, Status: "Done", ChangedAt: at(5, 0)},
+	}
+
+	testCase.Run("the lead and cycle times of the completed issues and the cumulative flow", func(t *testing.T) {
+		report := buildFlowReport(1, issues, changes, at(1, 0), at(5, 0), at(10, 0))
+
+		require.Equal(t, "2022-10-01", report.From)
+		require.Equal(t, "2022-10-05", report.To)
+		require.Equal(t, 3, len(report.Issues))
+		expectedIssues := []struct {
+			issueId   uint
+			leadTime  float64
+			cycleTime *float64
+		}{{2, 1.5, nil}, {1, 3, days(2)}, {5, 4, days(3.5)}}
+		for index, expected := range expectedIssues {
+			require.Equal(t, expected.issueId, report.Issues[index].IssueID)
+			require.Equal(t, expected.leadTime, report.Issues[index].LeadTimeDays)
+			require.Equal(t, expected.cycleTime, report.Issues[index].CycleTimeDays)
+		}
+		require.Equal(t, "alice", report.Issues[1].Assignee)
+		require.Equal(t, at(2, 9), *report.Issues[1].StartedAt)
+
+		require.Equal(t, models.FlowTimeSummary{Issues: 3, AverageDays: 2.83, Percentiles: []models.FlowTimePercentile{
+			{Percentile: 50, Days: 3}, {Percentile: 70, Days: 4}, {Percentile: 85, Days: 4}, {Percentile: 95, Days: 4},
+		}}, report.LeadTime)
+		require.Equal(t, 2, report.CycleTime.Issues)
+		require.Equal(t, 2.75, report.CycleTime.AverageDays)
+		require.Equal(t, 2.0, report.CycleTime.Percentiles[0].Days)
+		require.Equal(t, 3.5, report.CycleTime.Percentiles[1].Days)
+
+		require.Equal(t, []string{"To Do", "In Progress", "Done"}, report.Statuses)
+		require.Equal(t, []models.CumulativeFlowDay{
+			{Date: "2022-10-01", Statuses: map[string]int{"To Do": 2, "In Progress": 1, "Done": 1}},
+			{Date: "2022-10-02", Statuses: map[string]int{"To Do": 2, "In Progress": 2, "Done": 1}},
+			{Date: "2022-10-03", Statuses: map[string]int{"To Do": 0, "In Progress": 3, "Done": 2}},
+			{Date: "2022-10-04", Statuses: map[string]int{"To Do": 0, "In Progress": 2, "Done": 3}},
+			{Date: "2022-10-05", Statuses: map[string]int{"To Do": 0, "In Progress": 1, "Done": 4}},
+		}, report.CumulativeFlow)
+	})
+
+	testCase.Run("the cumulative flow stops now", func(t *testing.T) {
+		report := buildFlowReport(1, issues, changes, at(1, 0), at(5, 0), at(2, 10))
+
+		require.Equal(t, 2, len(report.CumulativeFlow))
+		require.Equal(t, map[string]int{"To Do": 2, "In Progress": 1, "Done": 2}, report.CumulativeFlow[1].Statuses)
+	})
+
+	testCase.Run("parseFlowDays", func(t *testing.T) {
+		location, err := time.LoadLocation("Europe/Rome")
+		require.Equal(t, nil, err)
+		now := time.Date(2022, time.October, 14, 23, 30, 0, 0, time.UTC)
+
+		firstDay, lastDay, err := parseFlowDays("", "", now, location)
+		require.Equal(t, nil, err)
+		require.Equal(t, time.Date(2022, time.September, 16, 0, 0, 0, 0, location), firstDay)
+		require.Equal(t, time.Date(2022, time.October, 15, 0, 0, 0, 0, location), lastDay)
+
+		_, _, err = parseFlowDays("2022-10-15", "2022-10-14", now, location)
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The from date 2022-10-15 is after the to date 2022-10-14", ErrorCode: 400}, err)
+		_, _, err = parseFlowDays("2021-01-01", "2022-10-14", now, location)
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The range must not exceed 366 days", ErrorCode: 400}, err)
+		_, _, err = parseFlowDays("", "14/10/2022", now, location)
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+	})
+}
+
+func TestGetFlowReport(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	testCase.Run("the report of the issues of a type", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		for _, issueType := range []string{"Bug", "Task"} {
+			issue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: issueType, Title: "Flow", Status: "To Do"}
+			require.Equal(t, nil, stores.Issues.Create(ctx, &issue))
+			for _, status := range []string{"In Progress", "Done"} {
+				require.Equal(t, nil, stores.Issues.Update(ctx, models.Issue{ID: issue.ID, ProjectID: int(projectId), SprintID: int(sprintId), Status: status}))
+			}
+		}
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		report, err := getFlowReport(ctx, stores, int(projectId), flowOptions{filter: models.IssueFilter{Type: "Bug"}})
+		require.Equal(t, nil, err)
+		require.Equal(t, "Bug", report.Type)
+		require.Equal(t, 1, len(report.Issues))
+		require.Equal(t, "Bug", report.Issues[0].Type)
+		require.NotNil(t, report.Issues[0].StartedAt)
+		require.Equal(t, 1, report.LeadTime.Issues)
+		require.Equal(t, 1, report.CycleTime.Issues)
+		require.Equal(t, DEFAULT_FLOW_DAYS, len(report.CumulativeFlow))
+		require.Equal(t, map[string]int{"Done": 1}, report.CumulativeFlow[DEFAULT_FLOW_DAYS-1].Statuses)
+
+		report, err = getFlowReport(ctx, stores, int(projectId), flowOptions{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 2, len(report.Issues))
+		require.Equal(t, []string{"To Do", "Done"}, report.Statuses)
+		require.Equal(t, map[string]int{"To Do": 1, "Done": 2}, report.CumulativeFlow[DEFAULT_FLOW_DAYS-1].Statuses)
+	})
+
+	testCase.Run("getFlowReport return error", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		_, err := getFlowReport(context.Background(), stores, 100, flowOptions{})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Project with id \"100\" does not exists", ErrorCode: 404}, err)
+	})
+}
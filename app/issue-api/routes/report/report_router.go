@@ -0,0 +1,61 @@
+package report
+
+import (
+	"issue-service/app/issue-api/routes/models"
+	"net/http"
+	"strings"
+)
+
+type reportRouter struct {
+	routes models.Routes
+	stores models.Stores
+}
+
+func NewRouter(stores models.Stores) models.Router {
+	r := &reportRouter{stores: stores}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routers to the checkpoint controller
+func (r *reportRouter) Routes() models.Routes {
+	return r.routes
+}
+
+func (r *reportRouter) initRoutes() {
+	r.routes = models.Routes{
+		models.Route{
+			Name:        "GetVelocityReport",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/reports/velocity",
+			HandlerFunc: createGetVelocityReportHandler,
+			Summary:     "Get the velocity of the completed sprints of a project, and forecast the sprints needed by its open issues",
+			QueryParameters: []models.QueryParameter{
+				{Name: "unit", Type: "string", Description: "points or issues, the work counted, points when missing"},
+				{Name: "sprints", Type: "integer", Description: "number of the latest completed sprints in the report, 10 when missing"},
+				{Name: "window", Type: "integer", Description: "number of sprints of the rolling average, 3 when missing"},
+				{Name: "simulations", Type: "integer", Description: "number of simulations of the forecast, 10000 when missing"},
+			},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The velocity report", Body: models.VelocityReportResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetFlowReport",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/reports/flow",
+			HandlerFunc: createGetFlowReportHandler,
+			Summary:     "Get the lead and cycle times of the issues of a project completed in a range of days, and its cumulative flow",
+			QueryParameters: []models.QueryParameter{
+				{Name: "from", Type: "string", Description: "the first day, YYYY-MM-DD, 29 days before the last day when missing"},
+				{Name: "to", Type: "string", Description: "the last day, YYYY-MM-DD, today in the timezone of the project when missing"},
+				{Name: "type", Type: "string", Description: "the type of the issues, every type when missing"},
+				{Name: "assignee", Type: "string", Description: "the assignee of the issues, every assignee when missing"},
+			},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The flow report", Body: models.FlowReportResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+	}
+}
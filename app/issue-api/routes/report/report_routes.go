@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// getIntQueryParameter reads an integer query parameter between minimum and maximum, defaultValue when it is missing
+func getIntQueryParameter(r *http.Request, name string, defaultValue int, minimum int, maximum int) (int, error) {
+	parameter := r.URL.Query().Get(name)
+	if parameter == "" {
+		return defaultValue, nil
+	}
+	value, err := strconv.Atoi(parameter)
+	if err != nil || value < minimum || value > maximum {
+		return 0, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("%s must be between %d and %d", name, minimum, maximum),
+			ErrorCode:    400,
+		}
+	}
+	return value, nil
+}
+
+func createGetVelocityReportHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := strconv.Atoi(mux.Vars(r)["projectId"])
+		if err != nil {
+			errorResponse := &models.ErrorResponse{
+				ErrorMessage: "Error parsing projectId to int",
+				ErrorCode:    500,
+			}
+			internal.LogAndReturnErrorResponse(errorResponse, w)
+			return
+		}
+
+		options := velocityOptions{unit: r.URL.Query().Get("unit")}
+		if options.sprints, err = getIntQueryParameter(r, "sprints", DEFAULT_VELOCITY_SPRINTS, 1, MAX_VELOCITY_SPRINTS); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+		if options.window, err = getIntQueryParameter(r, "window", DEFAULT_VELOCITY_WINDOW, 1, MAX_VELOCITY_SPRINTS); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+		if options.simulations, err = getIntQueryParameter(r, "simulations", DEFAULT_SIMULATIONS, 1, MAX_SIMULATIONS); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		report, err := getVelocityReport(r.Context(), stores, projectId, options)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(report)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createGetFlowReportHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := strconv.Atoi(mux.Vars(r)["projectId"])
+		if err != nil {
+			errorResponse := &models.ErrorResponse{
+				ErrorMessage: "Error parsing projectId to int",
+				ErrorCode:    500,
+			}
+			internal.LogAndReturnErrorResponse(errorResponse, w)
+			return
+		}
+
+		query := r.URL.Query()
+		report, err := getFlowReport(r.Context(), stores, projectId, flowOptions{
+			from:   query.Get("from"),
+			to:     query.Get("to"),
+			filter: models.IssueFilter{Type: query.Get("type"), Assignee: query.Get("assignee")},
+		})
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(report)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
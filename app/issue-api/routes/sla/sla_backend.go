@@ -0,0 +1,287 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_WITHIN_MINUTES = 60
+	MAX_WITHIN_MINUTES     = 525600
+)
+
+// getProjectPolicy returns the SLA policy of the project, an error response when the project or its policy does not exist
+func getProjectPolicy(ctx context.Context, stores models.Stores, projectId int) (models.SlaPolicy, error) {
+	if _, err := internal.GetProjectById(ctx, stores, projectId); err != nil {
+		return models.SlaPolicy{}, err
+	}
+	policy, err := stores.Sla.GetPolicy(ctx, projectId)
+	if errors.Is(err, internal.ErrNotFound) {
+		return models.SlaPolicy{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Project with id \"%d\" has no SLA policy", projectId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return models.SlaPolicy{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return policy, nil
+}
+
+// validateTargets checks that every target sets a time and that a priority has a single target by type
+func validateTargets(targets []models.SlaTarget) error {
+	typeTargets := map[string]bool{}
+	for _, target := range targets {
+		if target.ResponseMinutes == nil && target.ResolutionMinutes == nil {
+			return &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The target of the priority %s sets neither responseMinutes nor resolutionMinutes", target.Priority),
+				ErrorCode:    400,
+			}
+		}
+		key := target.Priority + "/" + strings.ToLower(target.Type)
+		if typeTargets[key] {
+			return &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The priority %s has two targets for the type \"%s\"", target.Priority, target.Type),
+				ErrorCode:    400,
+			}
+		}
+		typeTargets[key] = true
+	}
+	return nil
+}
+
+func saveSlaPolicy(ctx context.Context, stores models.Stores, policy models.SlaPolicy) error {
+	if _, err := internal.GetProjectById(ctx, stores, policy.ProjectID); err != nil {
+		return err
+	}
+	if err := validateTargets(policy.Targets); err != nil {
+		return err
+	}
+	if policy.PausedStatuses == nil {
+		policy.PausedStatuses = []string{}
+	}
+
+	err := stores.Sla.SavePolicy(ctx, &policy)
+	if internal.IsDuplicateKeyError(err) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The SLA policy of project \"%d\" is being created by another request", policy.ProjectID),
+			ErrorCode:    409,
+		}
+	}
+	if err != nil {
+		return &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return nil
+}
+
+func getSlaPolicy(ctx context.Context, stores models.Stores, projectId int) (models.SlaPolicyResponse, error) {
+	policy, err := getProjectPolicy(ctx, stores, projectId)
+	if err != nil {
+		return models.SlaPolicyResponse{}, err
+	}
+	return models.SlaPolicyResponse{
+		ProjectID:      policy.ProjectID,
+		PausedStatuses: policy.PausedStatuses,
+		Targets:        policy.Targets,
+		UpdatedAt:      policy.UpdatedAt,
+	}, nil
+}
+
+func getMinutes(duration time.Duration) int {
+	return int(math.Floor(duration.Minutes()))
+}
+
+// getSlaClock counts the time of the clock of kind over the changes of an issue, from its first change until now.
+// The clock runs while the issue is neither closed nor in a paused status, the response clock stops for good
+// at the first change to a status that is not to do.
+func getSlaClock(kind string, targetMinutes int, policy models.SlaPolicy, changes []models.IssueChange, now time.Time) models.SlaClock {
+	target := time.Duration(targetMinutes) * time.Minute
+	elapsed := time.Duration(0)
+	var breachedAt *time.Time
+	stopped := false
+	for index, change := range changes {
+		if kind == models.SLA_KIND_RESPONSE && !models.IsTodoIssueStatus(change.Status) {
+			stopped = true
+			break
+		}
+		end := now
+		if index+1 < len(changes) {
+			end = changes[index+1].ChangedAt
+		}
+		if policy.IsPaused(change.Status) || models.IsClosedIssueStatus(change.Status) || !end.After(change.ChangedAt) {
+			continue
+		}
+		duration := end.Sub(change.ChangedAt)
+		if breachedAt == nil && elapsed+duration > target {
+			at := change.ChangedAt.Add(target - elapsed)
+			breachedAt = &at
+		}
+		elapsed += duration
+	}
+	status := changes[len(changes)-1].Status
+	if kind == models.SLA_KIND_RESOLUTION && models.IsClosedIssueStatus(status) {
+		stopped = true
+	}
+
+	clock := models.SlaClock{
+		TargetMinutes:    targetMinutes,
+		ElapsedMinutes:   getMinutes(elapsed),
+		RemainingMinutes: getMinutes(target - elapsed),
+		BreachedAt:       breachedAt,
+	}
+	switch {
+	case breachedAt != nil:
+		clock.State = models.SLA_STATE_BREACHED
+	case stopped:
+		clock.State = models.SLA_STATE_MET
+	case policy.IsPaused(status):
+		clock.State = models.SLA_STATE_PAUSED
+	default:
+		clock.State = models.SLA_STATE_RUNNING
+		dueAt := now.Add(target - elapsed)
+		clock.DueAt = &dueAt
+	}
+	return clock
+}
+
+// getSlaIssue computes the clocks of the issue, it returns false when the policy sets no target for the issue
+func getSlaIssue(policy models.SlaPolicy, issue models.Issue, changes []models.IssueChange, now time.Time) (models.SlaIssue, bool) {
+	target, ok := policy.GetTarget(issue)
+	if !ok {
+		return models.SlaIssue{}, false
+	}
+	if len(changes) == 0 {
+		changes = []models.IssueChange{{IssueID: issue.ID, ProjectID: issue.ProjectID, Status: issue.Status, ChangedAt: issue.CreatedAt}}
+	}
+
+	slaIssue := models.SlaIssue{
+		IssueID:  issue.ID,
+		SprintID: issue.SprintID,
+		Type:     issue.Type,
+		Title:    issue.Title,
+		Status:   issue.Status,
+		Assignee: issue.Assignee,
+		Priority: issue.Priority,
+	}
+	if target.ResponseMinutes != nil {
+		clock := getSlaClock(models.SLA_KIND_RESPONSE, *target.ResponseMinutes, policy, changes, now)
+		slaIssue.Response = &clock
+	}
+	if target.ResolutionMinutes != nil {
+		clock := getSlaClock(models.SLA_KIND_RESOLUTION, *target.ResolutionMinutes, policy, changes, now)
+		slaIssue.Resolution = &clock
+	}
+	return slaIssue, true
+}
+
+// getProjectSlaIssues computes the clocks of the open issues of the project of policy with a target, ordered by rank.
+// The closed issues are not loaded: their clocks are stopped, and their past breaches are never flagged.
+func getProjectSlaIssues(ctx context.Context, stores models.Stores, policy models.SlaPolicy, now time.Time) ([]models.SlaIssue, error) {
+	issues, err := stores.Issues.ListByProject(ctx, policy.ProjectID, models.IssueFilter{Open: true})
+	if err != nil {
+		return nil, err
+	}
+	changes, err := stores.Issues.ListProjectChanges(ctx, policy.ProjectID, models.IssueFilter{Open: true})
+	if err != nil {
+		return nil, err
+	}
+	issueChanges := map[uint][]models.IssueChange{}
+	for _, change := range changes {
+		issueChanges[change.IssueID] = append(issueChanges[change.IssueID], change)
+	}
+
+	slaIssues := []models.SlaIssue{}
+	for _, issue := range issues {
+		if slaIssue, ok := getSlaIssue(policy, issue, issueChanges[issue.ID], now); ok {
+			slaIssues = append(slaIssues, slaIssue)
+		}
+	}
+	return slaIssues, nil
+}
+
+// isAtRisk tells whether the clock is breached or runs out within withinMinutes
+func isAtRisk(clock *models.SlaClock, withinMinutes int) bool {
+	if clock == nil {
+		return false
+	}
+	return clock.State == models.SLA_STATE_BREACHED || clock.State == models.SLA_STATE_RUNNING && clock.RemainingMinutes < withinMinutes
+}
+
+// selectSlaIssues keeps the issues not closed with a clock at risk, the most urgent first,
+// and sets when the checker flagged the breaches of their clocks
+func selectSlaIssues(slaIssues []models.SlaIssue, breaches []models.SlaBreach, withinMinutes int) []models.SlaIssue {
+	flaggedAt := map[string]time.Time{}
+	for _, breach := range breaches {
+		flaggedAt[fmt.Sprintf("%d/%s", breach.IssueID, breach.Kind)] = breach.CreatedAt
+	}
+	setFlaggedAt := func(issueId uint, kind string, clock *models.SlaClock) {
+		if at, ok := flaggedAt[fmt.Sprintf("%d/%s", issueId, kind)]; ok && clock != nil {
+			clock.FlaggedAt = &at
+		}
+	}
+	// getUrgency returns the remaining minutes of the clock at risk that runs out first
+	getUrgency := func(slaIssue models.SlaIssue) int {
+		urgency := math.MaxInt32
+		for _, clock := range []*models.SlaClock{slaIssue.Response, slaIssue.Resolution} {
+			if isAtRisk(clock, withinMinutes) && clock.RemainingMinutes < urgency {
+				urgency = clock.RemainingMinutes
+			}
+		}
+		return urgency
+	}
+
+	selected := []models.SlaIssue{}
+	for _, slaIssue := range slaIssues {
+		if models.IsClosedIssueStatus(slaIssue.Status) {
+			continue
+		}
+		if !isAtRisk(slaIssue.Response, withinMinutes) && !isAtRisk(slaIssue.Resolution, withinMinutes) {
+			continue
+		}
+		setFlaggedAt(slaIssue.IssueID, models.SLA_KIND_RESPONSE, slaIssue.Response)
+		setFlaggedAt(slaIssue.IssueID, models.SLA_KIND_RESOLUTION, slaIssue.Resolution)
+		selected = append(selected, slaIssue)
+	}
+	sort.SliceStable(selected, func(i, j int) bool { return getUrgency(selected[i]) < getUrgency(selected[j]) })
+	return selected
+}
+
+func getSlaIssues(ctx context.Context, stores models.Stores, projectId int, withinMinutes int) (models.SlaIssuesResponse, error) {
+	policy, err := getProjectPolicy(ctx, stores, projectId)
+	if err != nil {
+		return models.SlaIssuesResponse{}, err
+	}
+	slaIssues, err := getProjectSlaIssues(ctx, stores, policy, time.Now())
+	if err != nil {
+		return models.SlaIssuesResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	breaches, err := stores.Sla.ListBreaches(ctx, projectId)
+	if err != nil {
+		return models.SlaIssuesResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+
+	return models.SlaIssuesResponse{
+		ProjectID:     projectId,
+		WithinMinutes: withinMinutes,
+		Issues:        selectSlaIssues(slaIssues, breaches, withinMinutes),
+	}, nil
+}
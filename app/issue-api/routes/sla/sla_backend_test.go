@@ -0,0 +1,214 @@
+package sla
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSlaClock(testCase *testing.T) {
+	start := time.Date(2022, time.October, 3, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+	policy := models.SlaPolicy{PausedStatuses: []string{"Blocked"}}
+	changes := []models.IssueChange{
+		{IssueID: 1, Status: "To Do", ChangedAt: at(0)},
+		{IssueID: 1, Status: "In Progress", ChangedAt: at(30)},
+		{IssueID: 1, Status: "blocked", ChangedAt: at(60)},
+		{IssueID: 1, Status: "In Progress", ChangedAt: at(120)},
+	}
+
+	testCase.Run("the response clock stops when the issue leaves the statuses to do", func(t *testing.T) {
+		clock := getSlaClock(models.SLA_KIND_RESPONSE, 60, policy, changes, at(150))
+
+		require.Equal(t, models.SlaClock{State: models.SLA_STATE_MET, TargetMinutes: 60, ElapsedMinutes: 30, RemainingMinutes: 30}, clock)
+	})
+
+	testCase.Run("the resolution clock does not run in a paused status", func(t *testing.T) {
+		clock := getSlaClock(models.SLA_KIND_RESOLUTION, 60, policy, changes, at(150))
+		require.Equal(t, models.SLA_STATE_BREACHED, clock.State)
+		require.Equal(t, 90, clock.ElapsedMinutes)
+		require.Equal(t, -30, clock.RemainingMinutes)
+		require.Equal(t, at(120), *clock.BreachedAt)
+		require.Nil(t, clock.DueAt)
+
+		clock = getSlaClock(models.SLA_KIND_RESOLUTION, 120, policy, changes[:3], at(100))
+		require.Equal(t, models.SlaClock{State: models.SLA_STATE_PAUSED, TargetMinutes: 120, ElapsedMinutes: 60, RemainingMinutes: 60}, clock)
+
+		clock = getSlaClock(models.SLA_KIND_RESOLUTION, 240, policy, changes, at(150))
+		require.Equal(t, models.SLA_STATE_RUNNING, clock.State)
+		require.Equal(t, 150, clock.RemainingMinutes)
+		require.Equal(t, at(300), *clock.DueAt)
+	})
+
+	testCase.Run("the resolution clock stops when the issue is closed", func(t *testing.T) {
+		closedChanges := []models.IssueChange{{IssueID: 2, Status: "To Do", ChangedAt: at(0)}, {IssueID: 2, Status: "Done", ChangedAt: at(45)}}
+
+		clock := getSlaClock(models.SLA_KIND_RESOLUTION, 60, policy, closedChanges, at(600))
+		require.Equal(t, models.SlaClock{State: models.SLA_STATE_MET, TargetMinutes: 60, ElapsedMinutes: 45, RemainingMinutes: 15}, clock)
+	})
+
+	testCase.Run("selectSlaIssues keeps the clocks at risk, the most urgent first", func(t *testing.T) {
+		flaggedAt := at(130)
+		slaIssues := []models.SlaIssue{
+			{IssueID: 1, Status: "In Progress", Resolution: &models.SlaClock{State: models.SLA_STATE_RUNNING, RemainingMinutes: 50}},
+			{IssueID: 2, Status: "To Do", Response: &models.SlaClock{State: models.SLA_STATE_RUNNING, RemainingMinutes: 90}},
+			{IssueID: 3, Status: "Blocked", Resolution: &models.SlaClock{State: models.SLA_STATE_PAUSED, RemainingMinutes: 5}},
+			{IssueID: 4, Status: "Done", Resolution: &models.SlaClock{State: models.SLA_STATE_BREACHED, RemainingMinutes: -5}},
+			{IssueID: 5, Status: "In Progress",
+				Response:   &models.SlaClock{State: models.SLA_STATE_MET, RemainingMinutes: 10},
+				Resolution: &models.SlaClock{State: models.SLA_STATE_BREACHED, RemainingMinutes: -30}},
+		}
+		breaches := []models.SlaBreach{{IssueID: 5, Kind: models.SLA_KIND_RESOLUTION, CreatedAt: flaggedAt}}
+
+		selected := selectSlaIssues(slaIssues, breaches, 60)
+		require.Equal(t, 2, len(selected))
+		require.Equal(t, []uint{5, 1}, []uint{selected[0].IssueID, selected[1].IssueID})
+		require.Equal(t, flaggedAt, *selected[0].Resolution.FlaggedAt)
+		require.Nil(t, selected[0].Response.FlaggedAt)
+
+		require.Equal(t, 3, len(selectSlaIssues(slaIssues, breaches, 120)))
+	})
+}
+
+// failingSlaStore fails to list the breaches of a project
+type failingSlaStore struct {
+	models.SlaStore
+	failingProjectId int
+}
+
+func (store failingSlaStore) ListBreaches(ctx context.Context, projectId int) ([]models.SlaBreach, error) {
+	if projectId == store.failingProjectId {
+		return nil, errors.New("connection lost")
+	}
+	return store.SlaStore.ListBreaches(ctx, projectId)
+}
+
+func TestSla(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+	minutes := func(value int) *int {
+		return &value
+	}
+
+	testCase.Run("save and get the SLA policy", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, _ := internal.CreateProjectAndSprint(stores)
+
+		_, err := getSlaPolicy(ctx, stores, int(projectId))
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: fmt.Sprintf("Project with id \"%d\" has no SLA policy", projectId), ErrorCode: 404}, err)
+
+		targets := []models.SlaTarget{{Priority: "P1", ResponseMinutes: minutes(30)}}
+		require.Equal(t, nil, saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: int(projectId), Targets: targets}))
+		targets = append(targets, models.SlaTarget{Priority: "P1", Type: "Bug", ResolutionMinutes: minutes(240)})
+		require.Equal(t, nil, saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: int(projectId), PausedStatuses: []string{"Blocked"}, Targets: targets}))
+
+		policy, err := getSlaPolicy(ctx, stores, int(projectId))
+		require.Equal(t, nil, err)
+		require.Equal(t, []string{"Blocked"}, policy.PausedStatuses)
+		require.Equal(t, targets, policy.Targets)
+	})
+
+	testCase.Run("saveSlaPolicy return error", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, _ := internal.CreateProjectAndSprint(stores)
+
+		err := saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: 100, Targets: []models.SlaTarget{{Priority: "P1", ResponseMinutes: minutes(30)}}})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Project with id \"100\" does not exists", ErrorCode: 404}, err)
+		err = saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: int(projectId), Targets: []models.SlaTarget{{Priority: "P1"}}})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The target of the priority P1 sets neither responseMinutes nor resolutionMinutes", ErrorCode: 400}, err)
+		err = saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: int(projectId), Targets: []models.SlaTarget{
+			{Priority: "P2", Type: "Bug", ResponseMinutes: minutes(30)}, {Priority: "P2", Type: "bug", ResolutionMinutes: minutes(60)},
+		}})
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The priority P2 has two targets for the type \"bug\"", ErrorCode: 400}, err)
+	})
+
+	testCase.Run("the issues at risk and the checker", func(t *testing.T) {
+		ctx := context.Background()
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		require.Equal(t, nil, saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: int(projectId), Targets: []models.SlaTarget{
+			{Priority: "P0", ResponseMinutes: minutes(15), ResolutionMinutes: minutes(120)},
+			{Priority: "P2", ResolutionMinutes: minutes(600)},
+		}}))
+
+		createIssue := func(title string, priority string, createdAgo time.Duration) uint {
+			issue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Bug", Title: title, Status: "To Do", Priority: priority}
+			require.Equal(t, nil, stores.Issues.Create(ctx, &issue))
+			require.Equal(t, nil, database.Model(&models.IssueChange{}).Where("issue_id = ?", issue.ID).Update("changed_at", time.Now().Add(-createdAgo)).Error)
+			return issue.ID
+		}
+		breachedId := createIssue("Breached", "P0", 30*time.Minute)
+		dueId := createIssue("Due", "P2", 9*time.Hour+30*time.Minute)
+		createIssue("Not due", "P2", time.Hour)
+		createIssue("No target", "P3", 24*time.Hour)
+		closedId := createIssue("Closed", "P0", 2*time.Hour)
+		require.Equal(t, nil, stores.Issues.Update(ctx, models.Issue{ID: closedId, ProjectID: int(projectId), SprintID: int(sprintId), Status: "Done"}))
+
+		response, err := getSlaIssues(ctx, stores, int(projectId), DEFAULT_WITHIN_MINUTES)
+		require.Equal(t, nil, err)
+		require.Equal(t, 2, len(response.Issues))
+		require.Equal(t, breachedId, response.Issues[0].IssueID)
+		require.Equal(t, models.SLA_STATE_BREACHED, response.Issues[0].Response.State)
+		require.Equal(t, models.SLA_STATE_RUNNING, response.Issues[0].Resolution.State)
+		require.Nil(t, response.Issues[0].Response.FlaggedAt)
+		require.Equal(t, dueId, response.Issues[1].IssueID)
+		require.Nil(t, response.Issues[1].Response)
+
+		checker := NewChecker(stores, time.Minute)
+		flagged, err := checker.Check(ctx, time.Now())
+		require.Equal(t, nil, err)
+		require.Equal(t, 1, flagged, "the past breaches of the closed issue are not flagged")
+		flagged, err = checker.Check(ctx, time.Now())
+		require.Equal(t, nil, err)
+		require.Equal(t, 0, flagged)
+
+		response, err = getSlaIssues(ctx, stores, int(projectId), DEFAULT_WITHIN_MINUTES)
+		require.Equal(t, nil, err)
+		require.NotNil(t, response.Issues[0].Response.FlaggedAt)
+	})
+
+	testCase.Run("the checker checks the other projects when one fails", func(t *testing.T) {
+		ctx := context.Background()
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectIds := []int{}
+		for index := 0; index < 2; index++ {
+			project := models.Project{Name: fmt.Sprintf("project %d", index), Type: "scrum"}
+			require.Equal(t, nil, stores.Projects.Create(ctx, &project))
+			projectIds = append(projectIds, int(project.ID))
+			require.Equal(t, nil, saveSlaPolicy(ctx, stores, models.SlaPolicy{ProjectID: int(project.ID), Targets: []models.SlaTarget{
+				{Priority: "P0", ResponseMinutes: minutes(15)},
+			}}))
+			issue := models.Issue{ProjectID: int(project.ID), Type: "Bug", Title: "Breached", Status: "To Do", Priority: "P0"}
+			require.Equal(t, nil, stores.Issues.Create(ctx, &issue))
+			require.Equal(t, nil, database.Model(&models.IssueChange{}).Where("issue_id = ?", issue.ID).Update("changed_at", time.Now().Add(-time.Hour)).Error)
+		}
+		stores.Sla = failingSlaStore{SlaStore: stores.Sla, failingProjectId: projectIds[0]}
+
+		flagged, err := NewChecker(stores, time.Minute).Check(ctx, time.Now())
+		require.Equal(t, 1, flagged, "the project after the failing one is checked")
+		require.EqualError(t, err, fmt.Sprintf("checking the SLA breaches: project %d: connection lost", projectIds[0]))
+	})
+
+	testCase.Run("getSlaIssues return error", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+
+		_, err := getSlaIssues(context.Background(), stores, 100, DEFAULT_WITHIN_MINUTES)
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "Project with id \"100\" does not exists", ErrorCode: 404}, err)
+	})
+}
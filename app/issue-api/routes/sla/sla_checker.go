@@ -0,0 +1,136 @@
+package sla
+
+import (
+	"context"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// Checker flags the SLA breaches as they happen, checking the issues of the projects with a policy every interval
+type Checker struct {
+	stores   models.Stores
+	interval time.Duration
+	breaches *prometheus.CounterVec
+}
+
+func NewChecker(stores models.Stores, interval time.Duration) *Checker {
+	return &Checker{
+		stores:   stores,
+		interval: interval,
+		breaches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "issue_service",
+			Name:      "sla_breaches_total",
+			Help:      "Number of SLA breaches flagged by the checker, by clock kind and issue priority.",
+		}, []string{"kind", "priority"}),
+	}
+}
+
+// Collector exposes the number of breaches flagged by the checker
+func (checker *Checker) Collector() prometheus.Collector {
+	return checker.breaches
+}
+
+// Run checks every interval until ctx is done, it returns at once when the interval is not positive
+func (checker *Checker) Run(ctx context.Context) {
+	if checker.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(checker.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := checker.Check(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.WithField("error", err.Error()).Error("Error checking the SLA breaches")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check flags the breaches at now of the open issues that are not flagged yet, and returns their number.
+// A project failing to be checked is logged and does not stop the check of the others, the error lists every failure
+func (checker *Checker) Check(ctx context.Context, now time.Time) (int, error) {
+	policies, err := checker.stores.Sla.ListPolicies(ctx)
+	if err != nil {
+		return 0, err
+	}
+	flagged := 0
+	failures := []string{}
+	for _, policy := range policies {
+		if ctx.Err() != nil {
+			return flagged, ctx.Err()
+		}
+		policyFlagged, err := checker.checkPolicy(ctx, policy, now)
+		flagged += policyFlagged
+		if err != nil {
+			log.WithFields(log.Fields{
+				"project_id": policy.ProjectID,
+				"error":      err.Error(),
+			}).Error("Error checking the SLA breaches of the project")
+			failures = append(failures, fmt.Sprintf("project %d: %s", policy.ProjectID, err.Error()))
+		}
+	}
+	if len(failures) > 0 {
+		return flagged, fmt.Errorf("checking the SLA breaches: %s", strings.Join(failures, "; "))
+	}
+	return flagged, nil
+}
+
+// checkPolicy flags the new breaches of the issues of the project of policy, and returns their number
+func (checker *Checker) checkPolicy(ctx context.Context, policy models.SlaPolicy, now time.Time) (int, error) {
+	slaIssues, err := getProjectSlaIssues(ctx, checker.stores, policy, now)
+	if err != nil {
+		return 0, err
+	}
+	breaches, err := checker.stores.Sla.ListBreaches(ctx, policy.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	flaggedBreaches := map[string]bool{}
+	for _, breach := range breaches {
+		flaggedBreaches[fmt.Sprintf("%d/%s", breach.IssueID, breach.Kind)] = true
+	}
+
+	flagged := 0
+	for _, slaIssue := range slaIssues {
+		clocks := []struct {
+			kind  string
+			clock *models.SlaClock
+		}{{models.SLA_KIND_RESPONSE, slaIssue.Response}, {models.SLA_KIND_RESOLUTION, slaIssue.Resolution}}
+		for _, kindClock := range clocks {
+			if kindClock.clock == nil || kindClock.clock.BreachedAt == nil || flaggedBreaches[fmt.Sprintf("%d/%s", slaIssue.IssueID, kindClock.kind)] {
+				continue
+			}
+			breach := models.SlaBreach{
+				ProjectID:  policy.ProjectID,
+				IssueID:    slaIssue.IssueID,
+				Kind:       kindClock.kind,
+				BreachedAt: *kindClock.clock.BreachedAt,
+			}
+			isNew, err := checker.stores.Sla.FlagBreach(ctx, &breach)
+			if err != nil {
+				return flagged, err
+			}
+			if !isNew {
+				continue
+			}
+			flagged++
+			checker.breaches.WithLabelValues(breach.Kind, slaIssue.Priority).Inc()
+			log.WithFields(log.Fields{
+				"project_id":  breach.ProjectID,
+				"issue_id":    breach.IssueID,
+				"kind":        breach.Kind,
+				"priority":    slaIssue.Priority,
+				"breached_at": breach.BreachedAt,
+			}).Warn("SLA breached")
+		}
+	}
+	return flagged, nil
+}
@@ -0,0 +1,64 @@
+package sla
+
+import (
+	"issue-service/app/issue-api/routes/models"
+	"net/http"
+	"strings"
+)
+
+type slaRouter struct {
+	routes models.Routes
+	stores models.Stores
+}
+
+func NewRouter(stores models.Stores) models.Router {
+	r := &slaRouter{stores: stores}
+	r.initRoutes()
+	return r
+}
+
+// Routes returns the available routers to the checkpoint controller
+func (r *slaRouter) Routes() models.Routes {
+	return r.routes
+}
+
+func (r *slaRouter) initRoutes() {
+	r.routes = models.Routes{
+		models.Route{
+			Name:        "SaveSlaPolicy",
+			Method:      strings.ToUpper("Put"),
+			Pattern:     "/v1/projects/{projectId}/sla",
+			HandlerFunc: createSaveSlaPolicyHandler,
+			Summary:     "Create or replace the SLA policy of a project, its targets by priority and issue type and its paused statuses",
+			RequestBody: models.SaveSlaPolicyRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The SLA policy is saved"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetSlaPolicy",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/sla",
+			HandlerFunc: createGetSlaPolicyHandler,
+			Summary:     "Get the SLA policy of a project",
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The SLA policy", Body: models.SlaPolicyResponse{}},
+			}, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetSlaIssues",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/sla/issues",
+			HandlerFunc: createGetSlaIssuesHandler,
+			Summary:     "List the issues of a project not closed whose SLA is breached or about to breach, the most urgent first",
+			QueryParameters: []models.QueryParameter{
+				{Name: "within", Type: "integer", Description: "minutes before the end of a running clock for its issue to be listed, 60 when missing"},
+			},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The issues with their SLA clocks", Body: models.SlaIssuesResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+	}
+}
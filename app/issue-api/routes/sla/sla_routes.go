@@ -0,0 +1,116 @@
+package sla
+
+import (
+	"encoding/json"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+func getProjectIdFromRequest(r *http.Request) (int, error) {
+	projectId, err := strconv.Atoi(mux.Vars(r)["projectId"])
+	if err != nil {
+		return 0, &models.ErrorResponse{
+			ErrorMessage: "Error parsing projectId to int",
+			ErrorCode:    500,
+		}
+	}
+	return projectId, nil
+}
+
+func createSaveSlaPolicyHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+		var requestBody models.SaveSlaPolicyRequest
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error reading request body",
+				ErrorCode:    400,
+			}, w)
+			return
+		}
+		if err := internal.ValidateRequest(requestBody); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		policy := models.SlaPolicy{ProjectID: projectId, PausedStatuses: requestBody.PausedStatuses, Targets: requestBody.Targets}
+		if err := saveSlaPolicy(r.Context(), stores, policy); err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func createGetSlaPolicyHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		policy, err := getSlaPolicy(r.Context(), stores, projectId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(policy)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createGetSlaIssuesHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := getProjectIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+		withinMinutes := DEFAULT_WITHIN_MINUTES
+		if within := r.URL.Query().Get("within"); within != "" {
+			withinMinutes, err = strconv.Atoi(within)
+			if err != nil || withinMinutes < 0 || withinMinutes > MAX_WITHIN_MINUTES {
+				internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+					ErrorMessage: fmt.Sprintf("within must be between 0 and %d", MAX_WITHIN_MINUTES),
+					ErrorCode:    400,
+				}, w)
+				return
+			}
+		}
+
+		issues, err := getSlaIssues(r.Context(), stores, projectId, withinMinutes)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(issues)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
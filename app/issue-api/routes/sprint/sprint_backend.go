@@ -1,34 +1,124 @@
 package sprint
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
-
-	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
+	"time"
 )
 
-func createSprint(database *gorm.DB, sprint models.Sprint) (uint, error) {
-	result := database.Create(&sprint)
+// dateLayout is the format of the days in the query parameters and in the error messages
+const dateLayout = "2006-01-02"
+
+// getDay returns the calendar day of instant in location, at midnight UTC
+func getDay(instant time.Time, location *time.Location) time.Time {
+	year, month, day := instant.In(location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// getSprintDays returns the first and the last day of the sprint in the timezone of its project
+func getSprintDays(sprint models.Sprint, location *time.Location) (time.Time, time.Time) {
+	return getDay(sprint.StartDate, location), getDay(sprint.EndDate, location)
+}
+
+// isScheduled is false for the sprints created without dates, before the dates were required
+func isScheduled(sprint models.Sprint) bool {
+	return !sprint.StartDate.IsZero() && !sprint.EndDate.IsZero()
+}
+
+// validateSprintDates checks the dates of sprint against the length limits of the project,
+// and against the dates of the other sprints of the project
+func validateSprintDates(ctx context.Context, stores models.Stores, project models.Project, sprint models.Sprint) error {
+	if !isScheduled(sprint) {
+		return &models.ErrorResponse{
+			ErrorMessage: "The start date and the end date of the sprint are required",
+			ErrorCode:    400,
+		}
+	}
+	if !sprint.EndDate.After(sprint.StartDate) {
+		return &models.ErrorResponse{
+			ErrorMessage: "The end date of the sprint must be after its start date",
+			ErrorCode:    400,
+		}
+	}
+
+	location := project.GetLocation()
+	firstDay, lastDay := getSprintDays(sprint, location)
+	days := int(lastDay.Sub(firstDay).Hours()/24) + 1
+	if project.SprintMinDays > 0 && days < project.SprintMinDays {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprints of project \"%d\" last at least %d days, got %d", project.ID, project.SprintMinDays, days),
+			ErrorCode:    400,
+		}
+	}
+	if project.SprintMaxDays > 0 && days > project.SprintMaxDays {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprints of project \"%d\" last at most %d days, got %d", project.ID, project.SprintMaxDays, days),
+			ErrorCode:    400,
+		}
+	}
+
+	sprints, err := stores.Sprints.ListByProject(ctx, int(project.ID), models.Page{})
+	if err != nil {
+		return &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	for _, other := range sprints {
+		if other.ID != sprint.ID && sprint.Overlaps(other) {
+			otherFirstDay, otherLastDay := getSprintDays(other, location)
+			return &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The sprint overlaps the sprint with id \"%d\", from %s to %s",
+					other.ID, otherFirstDay.Format(dateLayout), otherLastDay.Format(dateLayout)),
+				ErrorCode: 409,
+			}
+		}
+	}
+	return nil
+}
+
+// getConcurrentOverlapError answers the sprints whose dates were checked, then overlapped by a concurrent request
+func getConcurrentOverlapError(projectId int) error {
+	return &models.ErrorResponse{
+		ErrorMessage: fmt.Sprintf("The sprint overlaps another sprint of project \"%d\"", projectId),
+		ErrorCode:    409,
+	}
+}
 
-	if result.Error != nil {
-		log.WithField("error", result.Error.Error()).Error("Error creating new sprint")
-		if internal.IsDuplicateKeyError(result.Error) {
+func createSprint(ctx context.Context, stores models.Stores, sprint models.Sprint) (uint, error) {
+	project, err := internal.GetProjectById(ctx, stores, sprint.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateSprintDates(ctx, stores, project, sprint); err != nil {
+		return 0, err
+	}
+
+	err = stores.Sprints.Create(ctx, &sprint)
+
+	if err != nil {
+		internal.RequestLogger(ctx).WithField("error", err.Error()).Error("Error creating new sprint")
+		if errors.Is(err, internal.ErrOverlap) {
+			return 0, getConcurrentOverlapError(sprint.ProjectID)
+		}
+		if internal.IsDuplicateKeyError(err) {
 			return 0, &models.ErrorResponse{
 				ErrorMessage: fmt.Sprintf("Sprint with number \"%s\" already exists", sprint.Number),
 				ErrorCode:    409,
 			}
 		}
 
-		if internal.IsForeignKeyError(result.Error) {
+		if internal.IsForeignKeyError(err) {
 			return 0, &models.ErrorResponse{
 				ErrorMessage: fmt.Sprintf("Project with id \"%d\" does not exists", sprint.ProjectID),
 				ErrorCode:    404,
 			}
 		}
 		return 0, &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 
@@ -37,42 +127,267 @@ func createSprint(database *gorm.DB, sprint models.Sprint) (uint, error) {
 	return sprint.ID, nil
 }
 
-func patchSprint(database *gorm.DB, sprint models.Sprint) error {
-	result := database.Model(&sprint).Updates(sprint)
+func patchSprint(ctx context.Context, stores models.Stores, sprint models.Sprint) error {
+	foundSprint, err := internal.GetProjectSprint(ctx, stores, sprint.ProjectID, int(sprint.ID))
+	if err != nil {
+		return err
+	}
+	if !sprint.StartDate.IsZero() || !sprint.EndDate.IsZero() {
+		project, err := internal.GetProjectById(ctx, stores, sprint.ProjectID)
+		if err != nil {
+			return err
+		}
+		if !sprint.StartDate.IsZero() {
+			foundSprint.StartDate = sprint.StartDate
+		}
+		if !sprint.EndDate.IsZero() {
+			foundSprint.EndDate = sprint.EndDate
+		}
+		if err := validateSprintDates(ctx, stores, project, foundSprint); err != nil {
+			return err
+		}
+	}
 
-	if result.Error != nil {
-		if internal.IsForeignKeyError(result.Error) {
+	// the sprint is never moved to another project, only its own fields are updated
+	err = stores.Sprints.Update(ctx, sprint)
+
+	if errors.Is(err, internal.ErrNotFound) {
+		return &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" does not exists", sprint.ID),
+			ErrorCode:    404,
+		}
+	}
+	if errors.Is(err, internal.ErrOverlap) {
+		return getConcurrentOverlapError(sprint.ProjectID)
+	}
+	if err != nil {
+		if internal.IsDuplicateKeyError(err) {
 			return &models.ErrorResponse{
-				ErrorMessage: fmt.Sprintf("Project with id \"%d\" does not exists", sprint.ProjectID),
-				ErrorCode:    404,
+				ErrorMessage: fmt.Sprintf("Sprint with number \"%s\" already exists", sprint.Number),
+				ErrorCode:    409,
 			}
 		}
 
 		return &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 	}
-	if result.RowsAffected == 0 {
-		return &models.ErrorResponse{
-			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" does not exists", sprint.ID),
-			ErrorCode:    404,
+	return nil
+}
+
+// getSprintResponses adds to the sprints their committed and completed points, summed by the database
+func getSprintResponses(ctx context.Context, stores models.Stores, projectId int, sprints []models.Sprint) ([]models.GetSprintResponse, error) {
+	sprintIds := make([]uint, 0, len(sprints))
+	for _, sprint := range sprints {
+		sprintIds = append(sprintIds, sprint.ID)
+	}
+	points, err := stores.Sprints.SumPoints(ctx, projectId, sprintIds)
+	if err != nil {
+		return []models.GetSprintResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
 		}
 	}
-	return nil
+
+	responses := []models.GetSprintResponse{}
+	for _, sprint := range sprints {
+		responses = append(responses, sprint.GetSprintResponseWithPoints(points[sprint.ID]))
+	}
+	return responses, nil
 }
 
-func getSprints(database *gorm.DB, projectId int) ([]models.GetSprintResponse, error) {
-	sprints := []models.GetSprintResponse{}
+// getSprintResponse adds to the sprint its committed and completed points
+func getSprintResponse(ctx context.Context, stores models.Stores, sprint models.Sprint) (models.GetSprintResponse, error) {
+	responses, err := getSprintResponses(ctx, stores, sprint.ProjectID, []models.Sprint{sprint})
+	if err != nil {
+		return models.GetSprintResponse{}, err
+	}
+	return responses[0], nil
+}
 
-	result := database.Model(&models.Sprint{}).Where("project_id = ?", projectId).Find(&sprints)
+func getSprints(ctx context.Context, stores models.Stores, projectId int, page models.Page) ([]models.GetSprintResponse, error) {
+	if _, err := internal.GetProjectById(ctx, stores, projectId); err != nil {
+		return []models.GetSprintResponse{}, err
+	}
 
-	if result.Error != nil {
+	foundSprints, err := stores.Sprints.ListByProject(ctx, projectId, page)
+
+	if err != nil {
 		return []models.GetSprintResponse{}, &models.ErrorResponse{
-			ErrorMessage: result.Error.Error(),
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+
+	return getSprintResponses(ctx, stores, projectId, foundSprints)
+}
+
+// getCurrentSprint returns the sprint covering the day, YYYY-MM-DD, in the timezone of the project.
+// The day is today when it is empty.
+func getCurrentSprint(ctx context.Context, stores models.Stores, projectId int, day string) (models.GetSprintResponse, error) {
+	project, err := internal.GetProjectById(ctx, stores, projectId)
+	if err != nil {
+		return models.GetSprintResponse{}, err
+	}
+	location := project.GetLocation()
+	currentDay := getDay(time.Now(), location)
+	if day != "" {
+		if currentDay, err = time.Parse(dateLayout, day); err != nil {
+			return models.GetSprintResponse{}, &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The date must be a YYYY-MM-DD day, got \"%s\"", day),
+				ErrorCode:    400,
+			}
+		}
+	}
+
+	sprints, err := stores.Sprints.ListByProject(ctx, projectId, models.Page{})
+	if err != nil {
+		return models.GetSprintResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	// a sprint starting when the previous one ends shares its day, as the sprints created before the overlaps
+	// were rejected, the latest one wins
+	var currentSprint *models.Sprint
+	for index, sprint := range sprints {
+		if !isScheduled(sprint) {
+			continue
+		}
+		firstDay, lastDay := getSprintDays(sprint, location)
+		if !currentDay.Before(firstDay) && !currentDay.After(lastDay) &&
+			(currentSprint == nil || sprint.StartDate.After(currentSprint.StartDate)) {
+			currentSprint = &sprints[index]
+		}
+	}
+	if currentSprint == nil {
+		return models.GetSprintResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("No sprint of project \"%d\" covers %s", projectId, currentDay.Format(dateLayout)),
+			ErrorCode:    404,
+		}
+	}
+	return getSprintResponse(ctx, stores, *currentSprint)
+}
+
+func startSprint(ctx context.Context, stores models.Stores, projectId int, sprintId int) (models.GetSprintResponse, error) {
+	sprint, err := internal.GetProjectSprint(ctx, stores, projectId, sprintId)
+	if err != nil {
+		return models.GetSprintResponse{}, err
+	}
+	if sprint.StartedAt != nil || sprint.CompletedAt != nil {
+		return models.GetSprintResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" is already started", sprintId),
+			ErrorCode:    409,
+		}
+	}
+	if active, err := stores.Sprints.GetActive(ctx, projectId); err == nil {
+		return models.GetSprintResponse{}, getActiveSprintErrorResponse(projectId, int(active.ID))
+	}
+
+	err = stores.Sprints.Start(ctx, projectId, sprintId, time.Now())
+	if errors.Is(err, internal.ErrNotFound) {
+		return models.GetSprintResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" is already started", sprintId),
+			ErrorCode:    409,
+		}
+	}
+	if internal.IsDuplicateKeyError(err) {
+		return models.GetSprintResponse{}, getActiveSprintErrorResponse(projectId, 0)
+	}
+	if err != nil {
+		internal.RequestLogger(ctx).WithField("error", err.Error()).Error("Error starting sprint")
+		return models.GetSprintResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
 			ErrorCode:    500,
 		}
 	}
 
-	return sprints, nil
+	sprint, err = internal.GetProjectSprint(ctx, stores, projectId, sprintId)
+	if err != nil {
+		return models.GetSprintResponse{}, err
+	}
+	return getSprintResponse(ctx, stores, sprint)
+}
+
+func getActiveSprintErrorResponse(projectId int, activeSprintId int) error {
+	message := fmt.Sprintf("Another sprint of project \"%d\" is active", projectId)
+	if activeSprintId != 0 {
+		message = fmt.Sprintf("Sprint with id \"%d\" of project \"%d\" is active", activeSprintId, projectId)
+	}
+	return &models.ErrorResponse{
+		ErrorMessage: message,
+		ErrorCode:    409,
+	}
+}
+
+func completeSprint(ctx context.Context, stores models.Stores, projectId int, sprintId int, nextSprintId int) (models.SprintCompletionResponse, error) {
+	sprint, err := internal.GetProjectSprint(ctx, stores, projectId, sprintId)
+	if err != nil {
+		return models.SprintCompletionResponse{}, err
+	}
+	if !sprint.IsActive() {
+		return models.SprintCompletionResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" is not active", sprintId),
+			ErrorCode:    409,
+		}
+	}
+	if nextSprintId == sprintId {
+		return models.SprintCompletionResponse{}, &models.ErrorResponse{
+			ErrorMessage: "The unfinished issues cannot be carried over to the completed sprint",
+			ErrorCode:    400,
+		}
+	}
+	if nextSprintId != 0 {
+		nextSprint, err := internal.GetProjectSprint(ctx, stores, projectId, nextSprintId)
+		if err != nil {
+			return models.SprintCompletionResponse{}, err
+		}
+		if nextSprint.CompletedAt != nil {
+			return models.SprintCompletionResponse{}, &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" is already completed", nextSprintId),
+				ErrorCode:    409,
+			}
+		}
+	}
+
+	err = stores.Sprints.Complete(ctx, projectId, sprintId, nextSprintId, time.Now())
+	if errors.Is(err, internal.ErrNotFound) {
+		// a concurrent request completed the sprint, or the next sprint
+		return models.SprintCompletionResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" is not active, or the next sprint is completed", sprintId),
+			ErrorCode:    409,
+		}
+	}
+	if err != nil {
+		internal.RequestLogger(ctx).WithField("error", err.Error()).Error("Error completing sprint")
+		return models.SprintCompletionResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+
+	return getSprintCompletion(ctx, stores, projectId, sprintId)
+}
+
+func getSprintCompletion(ctx context.Context, stores models.Stores, projectId int, sprintId int) (models.SprintCompletionResponse, error) {
+	sprint, err := internal.GetProjectSprint(ctx, stores, projectId, sprintId)
+	if err != nil {
+		return models.SprintCompletionResponse{}, err
+	}
+	if sprint.CompletedAt == nil {
+		return models.SprintCompletionResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" is not completed", sprintId),
+			ErrorCode:    404,
+		}
+	}
+
+	snapshot, err := stores.Sprints.ListSnapshot(ctx, projectId, sprintId)
+	if err != nil {
+		return models.SprintCompletionResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return sprint.GetSprintCompletionResponse(snapshot), nil
 }
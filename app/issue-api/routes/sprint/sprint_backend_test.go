@@ -1,7 +1,7 @@
 package sprint
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
@@ -13,7 +13,6 @@ import (
 )
 
 var sprintNumber = "12345"
-var projectId = 1
 
 func TestCreateSprint(testCase *testing.T) {
 	config, err := internal.GetConfig("../../../../.env")
@@ -22,58 +21,55 @@ func TestCreateSprint(testCase *testing.T) {
 		return
 	}
 
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
 	expectedSprintNumber := "12345"
-	expectedResponse := 1
-	expectedJsonReponse, _ := json.Marshal(expectedResponse)
-	projectId := 1
 	inputSprint := models.Sprint{
 		Number:    expectedSprintNumber,
-		ProjectID: projectId,
 		StartDate: time.Now(),
 		EndDate:   time.Now().AddDate(0, 0, 7),
 		Completed: false,
 	}
 
 	testCase.Run("createSprint return the new id", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
 		inputProject := models.Project{
 			Name:   internal.GetRandomStringName(10),
 			Type:   "project-type",
 			Client: "project-client",
 		}
 		database.Create(&inputProject)
-		response, err := createSprint(database, inputSprint)
+		inputSprint.ProjectID = int(inputProject.ID)
+		response, err := createSprint(context.Background(), stores, inputSprint)
 
 		var foundSprint models.Sprint
 
 		database.First(&foundSprint)
 
 		require.Equal(t, nil, err)
-		require.Equal(t, string(expectedJsonReponse), fmt.Sprint(response))
+		require.Equal(t, foundSprint.ID, response)
 	})
 
 	testCase.Run("successfully create two sprint", func(t *testing.T) {
 		expectedSprint2Number := "98765"
 
-		internal.SetupAndResetDatabase(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
 		inputProject := models.Project{
 			Name:   internal.GetRandomStringName(10),
 			Type:   "project-type",
 			Client: "project-client",
 		}
 		database.Create(&inputProject)
+		inputSprint.ProjectID = int(inputProject.ID)
 
-		createSprint(database, inputSprint)
+		createSprint(context.Background(), stores, inputSprint)
 
 		inputSprint2 := inputSprint
 		inputSprint2.Number = expectedSprint2Number
+		inputSprint2.StartDate = inputSprint.EndDate.AddDate(0, 0, 1)
+		inputSprint2.EndDate = inputSprint.EndDate.AddDate(0, 0, 8)
 
-		createSprint(database, inputSprint2)
+		createSprint(context.Background(), stores, inputSprint2)
 
 		var foundSprints []models.Sprint
 
@@ -88,29 +84,36 @@ func TestCreateSprint(testCase *testing.T) {
 	testCase.Run("createSprint returns error if sprint with same number already exits", func(t *testing.T) {
 		expectedError := fmt.Sprintf("Sprint with number \"%s\" already exists", expectedSprintNumber)
 
-		internal.SetupAndResetDatabase(database)
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
 		inputProject := models.Project{
 			Name:   internal.GetRandomStringName(10),
 			Type:   "project-type",
 			Client: "project-client",
 		}
 		database.Create(&inputProject)
+		inputSprint.ProjectID = int(inputProject.ID)
 
-		_, err1 := createSprint(database, inputSprint)
+		_, err1 := createSprint(context.Background(), stores, inputSprint)
 
 		require.Equal(t, nil, err1)
 
-		_, err2 := createSprint(database, inputSprint)
+		nextSprint := inputSprint
+		nextSprint.StartDate = inputSprint.EndDate.AddDate(0, 0, 1)
+		nextSprint.EndDate = inputSprint.EndDate.AddDate(0, 0, 8)
+		_, err2 := createSprint(context.Background(), stores, nextSprint)
 
 		require.Equal(t, expectedError, err2.Error())
 	})
 
 	testCase.Run("createSprint returns error if project does not exists", func(t *testing.T) {
-		expectedError := fmt.Sprintf("Project with id \"%d\" does not exists", projectId)
+		nonExistingProjectId := 99999
+		expectedError := fmt.Sprintf("Project with id \"%d\" does not exists", nonExistingProjectId)
+		inputSprint.ProjectID = nonExistingProjectId
 
-		internal.SetupAndResetDatabase(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
 
-		_, err := createSprint(database, inputSprint)
+		_, err := createSprint(context.Background(), stores, inputSprint)
 
 		require.Equal(t, expectedError, err.Error())
 	})
@@ -123,60 +126,82 @@ func TestPatchSprint(testCase *testing.T) {
 		return
 	}
 
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
-
-	testCase.Run("patchSprint update the Completed field only", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		_, sprintId := internal.CreateProjectAndSprint(database)
+	testCase.Run("patchSprint update the MaxIssuePerSprint field only", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
 
 		inputSprint := models.Sprint{
-			ID:        sprintId,
-			Completed: true,
+			ID:                sprintId,
+			ProjectID:         int(projectId),
+			MaxIssuePerSprint: 10,
+			Completed:         true,
 		}
 
-		err := patchSprint(database, inputSprint)
+		err := patchSprint(context.Background(), stores, inputSprint)
 		require.Equal(t, nil, err)
 
 		var foundSprint models.Sprint
 		database.First(&foundSprint)
 
-		require.Equal(t, true, foundSprint.Completed)
+		require.Equal(t, 10, foundSprint.MaxIssuePerSprint)
+		require.Equal(t, false, foundSprint.Completed, "A sprint is only completed by completeSprint")
 		require.Equal(t, sprintNumber, foundSprint.Number)
 	})
 
 	testCase.Run("createSprint returns error if project does not exists", func(t *testing.T) {
 		nonExistingProjectId := 99999
 		expectedError := fmt.Sprintf("Project with id \"%d\" does not exists", nonExistingProjectId)
-		internal.SetupAndResetDatabase(database)
-		_, sprintId := internal.CreateProjectAndSprint(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		_, sprintId := internal.CreateProjectAndSprint(stores)
 
 		patchSprintInput := models.Sprint{
 			ID:        sprintId,
 			ProjectID: nonExistingProjectId,
-			Completed: true,
+			Number:    "2",
 		}
 
-		err := patchSprint(database, patchSprintInput)
+		err := patchSprint(context.Background(), stores, patchSprintInput)
 		require.Equal(t, expectedError, err.Error())
 	})
 
 	testCase.Run("patchSprint return error if sprint does not exist", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := internal.CreateTestProject(stores)
 		wrongSprintId := uint(999999)
 		expectedError := fmt.Sprintf("Sprint with id \"%d\" does not exists", wrongSprintId)
 
 		patchSprintInput := models.Sprint{
 			ID:        wrongSprintId,
-			Completed: true,
+			ProjectID: int(projectId),
+			Number:    "2",
 		}
 
-		err := patchSprint(database, patchSprintInput)
+		err := patchSprint(context.Background(), stores, patchSprintInput)
 		require.Equal(t, expectedError, err.Error())
 	})
+
+	testCase.Run("patchSprint return error if sprint belongs to another project", func(t *testing.T) {
+		database := internal.NewTestDatabase(t, config)
+		stores := internal.NewGormStores(database)
+		_, sprintId := internal.CreateProjectAndSprint(stores)
+		otherProjectId := internal.CreateTestProject(stores)
+		expectedError := fmt.Sprintf("Sprint with id \"%d\" does not exists", sprintId)
+
+		patchSprintInput := models.Sprint{
+			ID:        sprintId,
+			ProjectID: int(otherProjectId),
+			Number:    "2",
+		}
+
+		err := patchSprint(context.Background(), stores, patchSprintInput)
+		require.Equal(t, expectedError, err.Error())
+
+		var foundSprint models.Sprint
+		database.First(&foundSprint, sprintId)
+		require.Equal(t, false, foundSprint.Completed)
+		require.NotEqual(t, int(otherProjectId), foundSprint.ProjectID)
+	})
 }
 
 func TestGetSprints(testCase *testing.T) {
@@ -186,17 +211,11 @@ func TestGetSprints(testCase *testing.T) {
 		return
 	}
 
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
-
 	testCase.Run("getSprint return one sprint", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		_, sprintId := internal.CreateProjectAndSprint(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
 
-		foundSprints, err := getSprints(database, projectId)
+		foundSprints, err := getSprints(context.Background(), stores, int(projectId), models.Page{})
 
 		require.Equal(t, nil, err)
 		require.Equal(t, sprintNumber, foundSprints[0].Number)
@@ -204,13 +223,13 @@ func TestGetSprints(testCase *testing.T) {
 	})
 
 	testCase.Run("getSprint return a list of sprints", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
 		newSprintNumber := "newSprint"
 
-		projectId, sprint1Id := internal.CreateProjectAndSprint(database)
-		sprint2Id := internal.CreateTestSprint(database, newSprintNumber, int(projectId))
+		projectId, sprint1Id := internal.CreateProjectAndSprint(stores)
+		sprint2Id := internal.CreateTestSprint(stores, newSprintNumber, int(projectId))
 
-		foundSprints, err := getSprints(database, int(projectId))
+		foundSprints, err := getSprints(context.Background(), stores, int(projectId), models.Page{})
 
 		require.Equal(t, nil, err)
 		require.Equal(t, sprintNumber, foundSprints[0].Number)
@@ -219,17 +238,354 @@ func TestGetSprints(testCase *testing.T) {
 		require.Equal(t, sprint2Id, foundSprints[1].ID)
 	})
 
-	testCase.Run("getSprint return empty list if project does not exist", func(t *testing.T) {
+	testCase.Run("getSprint return error if project does not exist", func(t *testing.T) {
 		nonExistingProjectId := 99999
-		internal.SetupAndResetDatabase(database)
+		expectedError := fmt.Sprintf("Project with id \"%d\" does not exists", nonExistingProjectId)
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
 
-		internal.CreateProjectAndSprint(database)
+		internal.CreateProjectAndSprint(stores)
 
-		foundSprints, err := getSprints(database, nonExistingProjectId)
+		foundSprints, err := getSprints(context.Background(), stores, nonExistingProjectId, models.Page{})
 
-		require.Equal(t, nil, err)
+		require.Equal(t, expectedError, err.Error())
 		require.Equal(t, []models.GetSprintResponse{}, foundSprints)
 
 	})
 
 }
+
+func getSnapshotIssueIds(issues []models.SprintSnapshotIssueResponse) []uint {
+	ids := []uint{}
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	return ids
+}
+
+func TestSprintLifecycle(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	testCase.Run("completeSprint carries the unfinished issues over to the next sprint", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		nextSprintId := internal.CreateTestSprint(stores, "12346", int(projectId))
+		doneIssueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		openIssueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		removedIssueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		startedSprint, err := startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+		require.NotNil(t, startedSprint.StartedAt)
+
+		addedIssueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, stores.Issues.Update(ctx, models.Issue{ID: doneIssueId, ProjectID: int(projectId), SprintID: int(sprintId), Status: "Done"}))
+		require.Equal(t, nil, stores.Issues.Move(ctx, models.Issue{ID: removedIssueId, ProjectID: int(projectId), SprintID: int(sprintId)}, int(projectId), int(nextSprintId)))
+
+		completion, err := completeSprint(ctx, stores, int(projectId), int(sprintId), int(nextSprintId))
+
+		require.Equal(t, nil, err)
+		require.Equal(t, int(nextSprintId), completion.NextSprintID)
+		require.Equal(t, []uint{doneIssueId, openIssueId, removedIssueId}, getSnapshotIssueIds(completion.Committed))
+		require.Equal(t, []uint{addedIssueId}, getSnapshotIssueIds(completion.Added))
+		require.Equal(t, []uint{doneIssueId}, getSnapshotIssueIds(completion.Completed))
+		require.Equal(t, []uint{openIssueId, addedIssueId}, getSnapshotIssueIds(completion.CarriedOver))
+		require.Equal(t, []uint{removedIssueId}, getSnapshotIssueIds(completion.Removed))
+
+		nextSprintIssues, err := stores.Issues.ListBySprint(ctx, int(projectId), int(nextSprintId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 3, len(nextSprintIssues))
+
+		sprint, err := stores.Sprints.Get(ctx, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+		require.True(t, sprint.Completed)
+		require.NotNil(t, sprint.CompletedAt)
+
+		savedCompletion, err := getSprintCompletion(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+		require.Equal(t, completion, savedCompletion)
+	})
+
+	testCase.Run("completeSprint carries the unfinished issues over to the backlog", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+		_, err := startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+
+		completion, err := completeSprint(ctx, stores, int(projectId), int(sprintId), 0)
+
+		require.Equal(t, nil, err)
+		require.Equal(t, 0, completion.NextSprintID)
+		require.Equal(t, []uint{issueId}, getSnapshotIssueIds(completion.CarriedOver))
+		backlogIssues, err := stores.Issues.ListBySprint(ctx, int(projectId), 0, models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 1, len(backlogIssues))
+		require.Equal(t, issueId, backlogIssues[0].ID)
+		require.Equal(t, 0, backlogIssues[0].SprintID)
+	})
+
+	testCase.Run("startSprint returns 409 if another sprint is active", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherSprintId := internal.CreateTestSprint(stores, "12346", int(projectId))
+		_, err := startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+
+		_, err = startSprint(ctx, stores, int(projectId), int(otherSprintId))
+
+		require.Equal(t, fmt.Sprintf("Sprint with id \"%d\" of project \"%d\" is active", sprintId, projectId), err.Error())
+		require.Equal(t, 409, err.(*models.ErrorResponse).ErrorCode)
+
+		_, err = startSprint(ctx, stores, int(projectId), int(sprintId))
+
+		require.Equal(t, fmt.Sprintf("Sprint with id \"%d\" is already started", sprintId), err.Error())
+	})
+
+	testCase.Run("completeSprint returns 409 if the sprint is not active", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+
+		_, err := completeSprint(context.Background(), stores, int(projectId), int(sprintId), 0)
+
+		require.Equal(t, fmt.Sprintf("Sprint with id \"%d\" is not active", sprintId), err.Error())
+		require.Equal(t, 409, err.(*models.ErrorResponse).ErrorCode)
+	})
+
+	testCase.Run("completeSprint returns 400 if the next sprint is the completed sprint", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		_, err := startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+
+		_, err = completeSprint(ctx, stores, int(projectId), int(sprintId), int(sprintId))
+
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+	})
+
+	testCase.Run("completeSprint returns 409 if the next sprint is completed", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, completedSprintId := internal.CreateProjectAndSprint(stores)
+		sprintId := internal.CreateTestSprint(stores, "12346", int(projectId))
+		_, err := startSprint(ctx, stores, int(projectId), int(completedSprintId))
+		require.Equal(t, nil, err)
+		_, err = completeSprint(ctx, stores, int(projectId), int(completedSprintId), 0)
+		require.Equal(t, nil, err)
+		_, err = startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+
+		_, err = completeSprint(ctx, stores, int(projectId), int(sprintId), int(completedSprintId))
+
+		require.Equal(t, fmt.Sprintf("Sprint with id \"%d\" is already completed", completedSprintId), err.Error())
+		require.Equal(t, 409, err.(*models.ErrorResponse).ErrorCode)
+	})
+
+	testCase.Run("getSprintCompletion returns 404 if the sprint is not completed", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+
+		_, err := getSprintCompletion(context.Background(), stores, int(projectId), int(sprintId))
+
+		require.Equal(t, fmt.Sprintf("Sprint with id \"%d\" is not completed", sprintId), err.Error())
+		require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode)
+	})
+}
+
+func TestSprintDates(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	firstDay := time.Date(2022, 10, 3, 9, 0, 0, 0, time.UTC)
+	createProject := func(stores models.Stores, project models.Project) int {
+		project.Name = internal.GetRandomStringName(10)
+		project.Type = "project-type"
+		stores.Projects.Create(context.Background(), &project)
+		return int(project.ID)
+	}
+
+	testCase.Run("createSprint returns 400 if the end date is not after the start date", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := createProject(stores, models.Project{})
+
+		_, err := createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, -1)})
+
+		require.Equal(t, "The end date of the sprint must be after its start date", err.Error())
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+	})
+
+	testCase.Run("createSprint returns 400 if the sprint is shorter or longer than the project allows", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := createProject(stores, models.Project{SprintMinDays: 7, SprintMaxDays: 14})
+
+		_, err := createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 5)})
+		require.Equal(t, fmt.Sprintf("Sprints of project \"%d\" last at least 7 days, got 6", projectId), err.Error())
+
+		_, err = createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 14)})
+		require.Equal(t, fmt.Sprintf("Sprints of project \"%d\" last at most 14 days, got 15", projectId), err.Error())
+
+		_, err = createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 13)})
+		require.Equal(t, nil, err)
+	})
+
+	testCase.Run("createSprint returns 409 if the sprint overlaps another sprint", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := createProject(stores, models.Project{})
+		sprintId, err := createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 11)})
+		require.Equal(t, nil, err)
+
+		_, err = createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "2", StartDate: firstDay.AddDate(0, 0, 11).Add(-time.Minute), EndDate: firstDay.AddDate(0, 0, 25)})
+
+		require.Equal(t, fmt.Sprintf("The sprint overlaps the sprint with id \"%d\", from 2022-10-03 to 2022-10-14", sprintId), err.Error())
+		require.Equal(t, 409, err.(*models.ErrorResponse).ErrorCode)
+
+		// the next sprint starts when the previous one ends
+		_, err = createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "2", StartDate: firstDay.AddDate(0, 0, 11), EndDate: firstDay.AddDate(0, 0, 25)})
+		require.Equal(t, nil, err)
+	})
+
+	testCase.Run("the stores reject the overlaps checked by concurrent requests", func(t *testing.T) {
+		for name, stores := range map[string]models.Stores{
+			"gorm":   internal.NewGormStores(internal.NewTestDatabase(t, config)),
+			"memory": internal.NewMemoryStores(),
+		} {
+			projectId := createProject(stores, models.Project{})
+			_, err := createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 11)})
+			require.Equal(t, nil, err, name)
+			nextSprint := models.Sprint{ProjectID: projectId, Number: "2", StartDate: firstDay.AddDate(0, 0, 11), EndDate: firstDay.AddDate(0, 0, 25)}
+			require.Equal(t, nil, stores.Sprints.Create(context.Background(), &nextSprint), name)
+
+			overlapping := models.Sprint{ProjectID: projectId, Number: "3", StartDate: firstDay.AddDate(0, 0, 5), EndDate: firstDay.AddDate(0, 0, 12)}
+			require.ErrorIs(t, stores.Sprints.Create(context.Background(), &overlapping), internal.ErrOverlap, name)
+			err = stores.Sprints.Update(context.Background(), models.Sprint{ID: nextSprint.ID, ProjectID: projectId, StartDate: firstDay.AddDate(0, 0, 10)})
+			require.ErrorIs(t, err, internal.ErrOverlap, name)
+		}
+	})
+
+	testCase.Run("patchSprint returns 409 if the new dates overlap another sprint", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := createProject(stores, models.Project{})
+		sprintId, _ := createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 11)})
+		nextSprintId, _ := createSprint(context.Background(), stores, models.Sprint{ProjectID: projectId, Number: "2", StartDate: firstDay.AddDate(0, 0, 14), EndDate: firstDay.AddDate(0, 0, 25)})
+
+		err := patchSprint(context.Background(), stores, models.Sprint{ID: sprintId, ProjectID: projectId, EndDate: firstDay.AddDate(0, 0, 15)})
+		require.Equal(t, fmt.Sprintf("The sprint overlaps the sprint with id \"%d\", from 2022-10-17 to 2022-10-28", nextSprintId), err.Error())
+
+		err = patchSprint(context.Background(), stores, models.Sprint{ID: sprintId, ProjectID: projectId, EndDate: firstDay.AddDate(0, 0, 14)})
+		require.Equal(t, nil, err)
+
+		err = patchSprint(context.Background(), stores, models.Sprint{ID: sprintId, ProjectID: projectId, StartDate: firstDay.AddDate(0, 0, 20)})
+		require.Equal(t, "The end date of the sprint must be after its start date", err.Error())
+	})
+
+	testCase.Run("getCurrentSprint counts the days in the timezone of the project", func(t *testing.T) {
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId := createProject(stores, models.Project{Timezone: "America/New_York"})
+		// 2022-10-17T02:00Z is still October 16 in New York
+		sprintId, err := createSprint(context.Background(), stores, models.Sprint{
+			ProjectID: projectId,
+			Number:    "1",
+			StartDate: time.Date(2022, 10, 3, 14, 0, 0, 0, time.UTC),
+			EndDate:   time.Date(2022, 10, 17, 2, 0, 0, 0, time.UTC),
+		})
+		require.Equal(t, nil, err)
+
+		sprint, err := getCurrentSprint(context.Background(), stores, projectId, "2022-10-16")
+		require.Equal(t, nil, err)
+		require.Equal(t, sprintId, sprint.ID)
+
+		_, err = getCurrentSprint(context.Background(), stores, projectId, "2022-10-17")
+		require.Equal(t, fmt.Sprintf("No sprint of project \"%d\" covers 2022-10-17", projectId), err.Error())
+		require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode)
+
+		_, err = getCurrentSprint(context.Background(), stores, projectId, "17/10/2022")
+		require.Equal(t, 400, err.(*models.ErrorResponse).ErrorCode)
+	})
+}
+
+func TestSprintPoints(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	createEstimatedIssue := func(stores models.Stores, projectId int, sprintId int, points int, status string) uint {
+		issue := models.Issue{ProjectID: projectId, SprintID: sprintId, Type: "Task", Title: "Estimated", Status: status, StoryPoints: &points}
+		stores.Issues.Create(context.Background(), &issue)
+		return issue.ID
+	}
+
+	testCase.Run("the sprint responses sum the committed and the completed points", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		createEstimatedIssue(stores, int(projectId), int(sprintId), 5, "To Do")
+		doneIssueId := createEstimatedIssue(stores, int(projectId), int(sprintId), 3, "To Do")
+		createEstimatedIssue(stores, int(projectId), int(sprintId), 2, "Done")
+		internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		sprints, err := getSprints(ctx, stores, int(projectId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 10, sprints[0].CommittedPoints)
+		require.Equal(t, 2, sprints[0].CompletedPoints)
+
+		_, err = startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+		// the issues added after the start are not committed
+		createEstimatedIssue(stores, int(projectId), int(sprintId), 8, "Done")
+		require.Equal(t, nil, stores.Issues.Update(ctx, models.Issue{ID: doneIssueId, ProjectID: int(projectId), SprintID: int(sprintId), Status: "Done"}))
+
+		sprints, err = getSprints(ctx, stores, int(projectId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 10, sprints[0].CommittedPoints)
+		require.Equal(t, 13, sprints[0].CompletedPoints)
+
+		completion, err := completeSprint(ctx, stores, int(projectId), int(sprintId), 0)
+		require.Equal(t, nil, err)
+		require.Equal(t, 5, *completion.CarriedOver[0].StoryPoints)
+
+		sprints, err = getSprints(ctx, stores, int(projectId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 10, sprints[0].CommittedPoints)
+		require.Equal(t, 13, sprints[0].CompletedPoints)
+	})
+
+	testCase.Run("the re-estimates during the sprint do not change the committed points", func(t *testing.T) {
+		for name, stores := range map[string]models.Stores{
+			"gorm":   internal.NewGormStores(internal.NewTestDatabase(t, config)),
+			"memory": internal.NewMemoryStores(),
+		} {
+			ctx := context.Background()
+			projectId, sprintId := internal.CreateProjectAndSprint(stores)
+			issueId := createEstimatedIssue(stores, int(projectId), int(sprintId), 3, "To Do")
+			createEstimatedIssue(stores, int(projectId), int(sprintId), 5, "To Do")
+			_, err := startSprint(ctx, stores, int(projectId), int(sprintId))
+			require.Equal(t, nil, err, name)
+
+			points := 8
+			require.Equal(t, nil, stores.Issues.Update(ctx, models.Issue{ID: issueId, ProjectID: int(projectId), SprintID: int(sprintId), StoryPoints: &points, Status: "Done"}), name)
+			completion, err := completeSprint(ctx, stores, int(projectId), int(sprintId), 0)
+			require.Equal(t, nil, err, name)
+			require.Equal(t, 8, *completion.Completed[0].StoryPoints, name)
+
+			sprints, err := getSprints(ctx, stores, int(projectId), models.Page{})
+			require.Equal(t, nil, err, name)
+			require.Equal(t, 8, sprints[0].CommittedPoints, name)
+			require.Equal(t, 8, sprints[0].CompletedPoints, name)
+			burndown, err := getSprintBurndown(ctx, stores, int(projectId), int(sprintId), "")
+			require.Equal(t, nil, err, name)
+			require.Equal(t, sprints[0].CommittedPoints, burndown.CommittedWork, name)
+		}
+	})
+}
@@ -0,0 +1,271 @@
+package sprint
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The formats of the burndown, the JSON document, a CSV table or an SVG chart
+const (
+	BURNDOWN_FORMAT_JSON = "json"
+	BURNDOWN_FORMAT_CSV  = "csv"
+	BURNDOWN_FORMAT_SVG  = "svg"
+)
+
+var burndownContentTypes = map[string]string{
+	BURNDOWN_FORMAT_JSON: "application/json; charset=UTF-8",
+	BURNDOWN_FORMAT_CSV:  "text/csv; charset=UTF-8",
+	BURNDOWN_FORMAT_SVG:  "image/svg+xml",
+}
+
+func getSprintBurndown(ctx context.Context, stores models.Stores, projectId int, sprintId int, unit string) (models.SprintBurndownResponse, error) {
+	if unit == "" {
+		unit = models.BURNDOWN_UNIT_POINTS
+	}
+	if unit != models.BURNDOWN_UNIT_POINTS && unit != models.BURNDOWN_UNIT_ISSUES {
+		return models.SprintBurndownResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("The unit must be %s or %s, got \"%s\"", models.BURNDOWN_UNIT_POINTS, models.BURNDOWN_UNIT_ISSUES, unit),
+			ErrorCode:    400,
+		}
+	}
+	project, err := internal.GetProjectById(ctx, stores, projectId)
+	if err != nil {
+		return models.SprintBurndownResponse{}, err
+	}
+	sprint, err := internal.GetProjectSprint(ctx, stores, projectId, sprintId)
+	if err != nil {
+		return models.SprintBurndownResponse{}, err
+	}
+	if !isScheduled(sprint) {
+		return models.SprintBurndownResponse{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" has no start date or no end date", sprintId),
+			ErrorCode:    409,
+		}
+	}
+
+	changes, err := stores.Issues.ListSprintChanges(ctx, projectId, sprintId)
+	if err != nil {
+		return models.SprintBurndownResponse{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return buildBurndown(sprint, project.GetLocation(), changes, unit, time.Now()), nil
+}
+
+// burndownReplay replays the history of the issues, keeping their state at the last applied change
+type burndownReplay struct {
+	sprint  models.Sprint
+	unit    string
+	changes []models.IssueChange
+	next    int
+	states  map[uint]models.IssueChange
+}
+
+func (replay *burndownReplay) isInSprint(state models.IssueChange) bool {
+	return state.ProjectID == replay.sprint.ProjectID && state.SprintID == int(replay.sprint.ID)
+}
+
+func (replay *burndownReplay) getWork(state models.IssueChange) int {
+	if replay.unit == models.BURNDOWN_UNIT_ISSUES {
+		return 1
+	}
+	if state.StoryPoints == nil {
+		return 0
+	}
+	return *state.StoryPoints
+}
+
+// applyUntil applies the changes made until instant, and before the completion of the sprint.
+// The scope changes are returned when trackScope is set.
+func (replay *burndownReplay) applyUntil(instant time.Time, trackScope bool) []models.BurndownScopeChange {
+	scopeChanges := []models.BurndownScopeChange{}
+	for ; replay.next < len(replay.changes); replay.next++ {
+		change := replay.changes[replay.next]
+		if change.ChangedAt.After(instant) {
+			break
+		}
+		if replay.sprint.CompletedAt != nil && !change.ChangedAt.Before(*replay.sprint.CompletedAt) {
+			// the carried over issues leave the sprint at its completion
+			replay.next = len(replay.changes)
+			break
+		}
+
+		previous, existed := replay.states[change.IssueID]
+		replay.states[change.IssueID] = change
+		if !trackScope {
+			continue
+		}
+		wasInSprint := existed && replay.isInSprint(previous)
+		scopeChange := models.BurndownScopeChange{ChangedAt: change.ChangedAt, IssueID: change.IssueID}
+		switch {
+		case !wasInSprint && replay.isInSprint(change):
+			scopeChange.Kind = models.SCOPE_CHANGE_ADDED
+			scopeChange.Change = replay.getWork(change)
+		case wasInSprint && !replay.isInSprint(change):
+			scopeChange.Kind = models.SCOPE_CHANGE_REMOVED
+			scopeChange.Change = -replay.getWork(previous)
+		case wasInSprint && replay.getWork(change) != replay.getWork(previous):
+			scopeChange.Kind = models.SCOPE_CHANGE_ESTIMATED
+			scopeChange.Change = replay.getWork(change) - replay.getWork(previous)
+		default:
+			continue
+		}
+		scopeChanges = append(scopeChanges, scopeChange)
+	}
+	return scopeChanges
+}
+
+// sumWork returns the work of the open issues of the sprint and the work of its closed issues
+func (replay *burndownReplay) sumWork() (int, int) {
+	remaining, completed := 0, 0
+	for _, state := range replay.states {
+		if !replay.isInSprint(state) {
+			continue
+		}
+		if models.IsClosedIssueStatus(state.Status) {
+			completed += replay.getWork(state)
+		} else {
+			remaining += replay.getWork(state)
+		}
+	}
+	return remaining, completed
+}
+
+// buildBurndown computes the work of the sprint at the end of each of its days in location, until now.
+// The scope is committed when the sprint is started, or at the beginning of its first day when it is not.
+func buildBurndown(sprint models.Sprint, location *time.Location, changes []models.IssueChange, unit string, now time.Time) models.SprintBurndownResponse {
+	firstDay, lastDay := getSprintDays(sprint, location)
+	startOfDay := func(day time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, location)
+	}
+	committedAt := startOfDay(firstDay)
+	if sprint.StartedAt != nil {
+		committedAt = *sprint.StartedAt
+	}
+
+	replay := &burndownReplay{sprint: sprint, unit: unit, changes: changes, states: map[uint]models.IssueChange{}}
+	replay.applyUntil(committedAt, false)
+	remaining, completed := replay.sumWork()
+	committedWork := remaining + completed
+
+	response := models.SprintBurndownResponse{
+		SprintID:      sprint.ID,
+		ProjectID:     sprint.ProjectID,
+		Unit:          unit,
+		StartDate:     firstDay.Format(dateLayout),
+		EndDate:       lastDay.Format(dateLayout),
+		CommittedWork: committedWork,
+		Days:          []models.BurndownDay{},
+		ScopeChanges:  []models.BurndownScopeChange{},
+	}
+	dayCount := int(lastDay.Sub(firstDay).Hours()/24) + 1
+	for index := 0; index < dayCount; index++ {
+		day := firstDay.AddDate(0, 0, index)
+		// the ideal work decreases linearly from the committed work at the start to 0 at the end of the last day
+		ideal := float64(committedWork) * float64(dayCount-index-1) / float64(dayCount)
+		burndownDay := models.BurndownDay{Date: day.Format(dateLayout), Ideal: math.Round(ideal*100) / 100}
+
+		if !startOfDay(day).After(now) {
+			endOfDay := startOfDay(day.AddDate(0, 0, 1)).Add(-time.Nanosecond)
+			if endOfDay.After(now) {
+				endOfDay = now
+			}
+			response.ScopeChanges = append(response.ScopeChanges, replay.applyUntil(endOfDay, true)...)
+			remaining, completed := replay.sumWork()
+			scope := remaining + completed
+			burndownDay.Remaining, burndownDay.Completed, burndownDay.Scope = &remaining, &completed, &scope
+		}
+		response.Days = append(response.Days, burndownDay)
+	}
+	return response
+}
+
+// writeBurndownCSV writes a row by day, the actual series are empty for the days to come
+func writeBurndownCSV(burndown models.SprintBurndownResponse) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	writer.Write([]string{"date", "ideal", "remaining", "completed", "scope"})
+	formatWork := func(work *int) string {
+		if work == nil {
+			return ""
+		}
+		return strconv.Itoa(*work)
+	}
+	for _, day := range burndown.Days {
+		writer.Write([]string{
+			day.Date,
+			strconv.FormatFloat(day.Ideal, 'f', -1, 64),
+			formatWork(day.Remaining),
+			formatWork(day.Completed),
+			formatWork(day.Scope),
+		})
+	}
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+const (
+	chartWidth  = 640
+	chartHeight = 360
+	chartMargin = 40
+)
+
+// renderBurndownSVG draws the ideal and the remaining work, and the scope, from the start of the sprint to the end of each day
+func renderBurndownSVG(burndown models.SprintBurndownResponse) []byte {
+	maxWork := burndown.CommittedWork
+	for _, day := range burndown.Days {
+		if day.Scope != nil && *day.Scope > maxWork {
+			maxWork = *day.Scope
+		}
+	}
+	if maxWork == 0 {
+		maxWork = 1
+	}
+	plotWidth := float64(chartWidth - 2*chartMargin)
+	plotHeight := float64(chartHeight - 2*chartMargin)
+	getPoint := func(index int, work float64) string {
+		x := chartMargin + plotWidth*float64(index)/float64(len(burndown.Days))
+		y := chartMargin + plotHeight*(1-work/float64(maxWork))
+		return fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	committed := float64(burndown.CommittedWork)
+	ideal := []string{getPoint(0, committed)}
+	remaining := []string{getPoint(0, committed)}
+	scope := []string{getPoint(0, committed)}
+	for index, day := range burndown.Days {
+		ideal = append(ideal, getPoint(index+1, day.Ideal))
+		if day.Remaining != nil {
+			remaining = append(remaining, getPoint(index+1, float64(*day.Remaining)))
+			scope = append(scope, getPoint(index+1, float64(*day.Scope)))
+		}
+	}
+
+	svg := &strings.Builder{}
+	fmt.Fprintf(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(svg, `<rect width="%d" height="%d" fill="white"/>`+"\n", chartWidth, chartHeight)
+	title := fmt.Sprintf("Burndown of sprint %d, in %s", burndown.SprintID, burndown.Unit)
+	fmt.Fprintf(svg, `<text x="%d" y="%d" font-size="14">%s</text>`+"\n", chartMargin, chartMargin/2, html.EscapeString(title))
+	fmt.Fprintf(svg, `<polyline points="%d,%d %d,%d %d,%d" fill="none" stroke="black"/>`+"\n",
+		chartMargin, chartMargin, chartMargin, chartHeight-chartMargin, chartWidth-chartMargin, chartHeight-chartMargin)
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", chartMargin-4, chartMargin+4, maxWork)
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="end">0</text>`+"\n", chartMargin-4, chartHeight-chartMargin+4)
+	fmt.Fprintf(svg, `<text x="%d" y="%d">%s</text>`+"\n", chartMargin, chartHeight-chartMargin+16, burndown.StartDate)
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", chartWidth-chartMargin, chartHeight-chartMargin+16, burndown.EndDate)
+	fmt.Fprintf(svg, `<polyline class="ideal" points="%s" fill="none" stroke="gray" stroke-dasharray="4 4"/>`+"\n", strings.Join(ideal, " "))
+	fmt.Fprintf(svg, `<polyline class="scope" points="%s" fill="none" stroke="orange"/>`+"\n", strings.Join(scope, " "))
+	fmt.Fprintf(svg, `<polyline class="remaining" points="%s" fill="none" stroke="steelblue" stroke-width="2"/>`+"\n", strings.Join(remaining, " "))
+	svg.WriteString("</svg>\n")
+	return []byte(svg.String())
+}
@@ -0,0 +1,183 @@
+package sprint
+
+import (
+	"context"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildBurndown(testCase *testing.T) {
+	location, err := time.LoadLocation("Europe/Rome")
+	require.Equal(testCase, nil, err)
+	at := func(day int, hour int) time.Time {
+		return time.Date(2022, time.October, day, hour, 0, 0, 0, location)
+	}
+	points := func(value int) *int {
+		return &value
+	}
+	startedAt := at(3, 9)
+	sprint := models.Sprint{
+		ID:        7,
+		ProjectID: 1,
+		StartDate: at(3, 0),
+		EndDate:   at(6, 18),
+		StartedAt: &startedAt,
+	}
+	changes := []models.IssueChange{
+		{IssueID: 1, ProjectID: 1, SprintID: 7, Status: "To Do", StoryPoints: points(5), ChangedAt: at(1, 10)},
+		{IssueID: 2, ProjectID: 1, SprintID: 7, Status: "To Do", StoryPoints: points(3), ChangedAt: at(2, 10)},
+		{IssueID: 3, ProjectID: 1, SprintID: 0, Status: "To Do", StoryPoints: points(8), ChangedAt: at(2, 11)},
+		{IssueID: 2, ProjectID: 1, SprintID: 7, Status: "Done", StoryPoints: points(3), ChangedAt: at(3, 15)},
+		{IssueID: 3, ProjectID: 1, SprintID: 7, Status: "To Do", StoryPoints: points(8), ChangedAt: at(4, 10)},
+		{IssueID: 1, ProjectID: 1, SprintID: 7, Status: "To Do", StoryPoints: points(2), ChangedAt: at(4, 12)},
+		{IssueID: 1, ProjectID: 1, SprintID: 0, Status: "To Do", StoryPoints: points(2), ChangedAt: at(5, 9)},
+	}
+
+	testCase.Run("the points of the days until now, with the ideal work and the scope changes", func(t *testing.T) {
+		burndown := buildBurndown(sprint, location, changes, models.BURNDOWN_UNIT_POINTS, at(5, 12))
+
+		require.Equal(t, "2022-10-03", burndown.StartDate)
+		require.Equal(t, "2022-10-06", burndown.EndDate)
+		require.Equal(t, 8, burndown.CommittedWork)
+		require.Equal(t, 4, len(burndown.Days))
+		expectedDays := []struct {
+			ideal     float64
+			remaining int
+			completed int
+		}{{6, 5, 3}, {4, 10, 3}, {2, 8, 3}}
+		for index, expected := range expectedDays {
+			day := burndown.Days[index]
+			require.Equal(t, expected.ideal, day.Ideal)
+			require.Equal(t, expected.remaining, *day.Remaining)
+			require.Equal(t, expected.completed, *day.Completed)
+			require.Equal(t, expected.remaining+expected.completed, *day.Scope)
+		}
+		// the last day is to come
+		require.Equal(t, "2022-10-06", burndown.Days[3].Date)
+		require.Equal(t, float64(0), burndown.Days[3].Ideal)
+		require.Nil(t, burndown.Days[3].Remaining)
+
+		require.Equal(t, []models.BurndownScopeChange{
+			{ChangedAt: at(4, 10), IssueID: 3, Kind: models.SCOPE_CHANGE_ADDED, Change: 8},
+			{ChangedAt: at(4, 12), IssueID: 1, Kind: models.SCOPE_CHANGE_ESTIMATED, Change: -3},
+			{ChangedAt: at(5, 9), IssueID: 1, Kind: models.SCOPE_CHANGE_REMOVED, Change: -2},
+		}, burndown.ScopeChanges)
+	})
+
+	testCase.Run("the issues are counted with the issues unit", func(t *testing.T) {
+		burndown := buildBurndown(sprint, location, changes, models.BURNDOWN_UNIT_ISSUES, at(10, 12))
+
+		require.Equal(t, 2, burndown.CommittedWork)
+		require.Equal(t, 1.5, burndown.Days[0].Ideal)
+		require.Equal(t, 1, *burndown.Days[3].Remaining)
+		require.Equal(t, 1, *burndown.Days[3].Completed)
+		require.Equal(t, 2, len(burndown.ScopeChanges))
+	})
+
+	testCase.Run("the changes after the completion are ignored", func(t *testing.T) {
+		completedSprint := sprint
+		completedAt := at(4, 11)
+		completedSprint.CompletedAt = &completedAt
+
+		burndown := buildBurndown(completedSprint, location, changes, models.BURNDOWN_UNIT_POINTS, at(10, 12))
+
+		require.Equal(t, 16, *burndown.Days[3].Scope)
+		require.Equal(t, 1, len(burndown.ScopeChanges))
+	})
+
+	testCase.Run("the unstarted sprint commits the issues at the beginning of its first day", func(t *testing.T) {
+		plannedSprint := sprint
+		plannedSprint.StartedAt = nil
+
+		burndown := buildBurndown(plannedSprint, location, changes, models.BURNDOWN_UNIT_POINTS, at(2, 12))
+
+		require.Equal(t, 8, burndown.CommittedWork)
+		require.Equal(t, 4, len(burndown.Days))
+		require.Nil(t, burndown.Days[0].Remaining)
+	})
+
+	testCase.Run("the CSV table and the SVG chart", func(t *testing.T) {
+		burndown := buildBurndown(sprint, location, changes, models.BURNDOWN_UNIT_POINTS, at(5, 12))
+
+		table, err := writeBurndownCSV(burndown)
+		require.Equal(t, nil, err)
+		require.Equal(t, "date,ideal,remaining,completed,scope\n"+
+			"2022-10-03,6,5,3,8\n"+
+			"2022-10-04,4,10,3,13\n"+
+			"2022-10-05,2,8,3,11\n"+
+			"2022-10-06,0,,,\n", string(table))
+
+		chart := string(renderBurndownSVG(burndown))
+		require.True(t, strings.HasPrefix(chart, "<svg "))
+		require.Contains(t, chart, "Burndown of sprint 7, in points")
+		require.Contains(t, chart, `class="remaining"`)
+	})
+}
+
+func TestSprintBurndown(testCase *testing.T) {
+	config, err := internal.GetConfig("../../../../.env")
+	if err != nil {
+		log.Fatalf("Error reading env configuration: %s", err.Error())
+		return
+	}
+
+	testCase.Run("the burndown follows the history of the issues", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		five, three, two := 5, 3, 2
+		committedIssue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Task", Title: "Committed", StoryPoints: &five}
+		require.Equal(t, nil, stores.Issues.Create(ctx, &committedIssue))
+		removedIssue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Task", Title: "Removed", StoryPoints: &three}
+		require.Equal(t, nil, stores.Issues.Create(ctx, &removedIssue))
+		_, err := startSprint(ctx, stores, int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+
+		addedIssue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Task", Title: "Added", StoryPoints: &two}
+		require.Equal(t, nil, stores.Issues.Create(ctx, &addedIssue))
+		require.Equal(t, nil, stores.Issues.Update(ctx, models.Issue{ID: committedIssue.ID, ProjectID: int(projectId), SprintID: int(sprintId), Status: "Done"}))
+		otherSprintId := internal.CreateTestSprint(stores, "other", int(projectId))
+		require.Equal(t, nil, stores.Issues.Move(ctx, removedIssue, int(projectId), int(otherSprintId)))
+
+		burndown, err := getSprintBurndown(ctx, stores, int(projectId), int(sprintId), "")
+		require.Equal(t, nil, err)
+		require.Equal(t, models.BURNDOWN_UNIT_POINTS, burndown.Unit)
+		require.Equal(t, 8, burndown.CommittedWork)
+		require.Equal(t, 8, len(burndown.Days))
+		require.Equal(t, 2, *burndown.Days[0].Remaining)
+		require.Equal(t, 5, *burndown.Days[0].Completed)
+		require.Nil(t, burndown.Days[1].Remaining)
+		require.Equal(t, 2, len(burndown.ScopeChanges))
+		require.Equal(t, models.BurndownScopeChange{
+			ChangedAt: burndown.ScopeChanges[0].ChangedAt,
+			IssueID:   addedIssue.ID,
+			Kind:      models.SCOPE_CHANGE_ADDED,
+			Change:    2,
+		}, burndown.ScopeChanges[0])
+		require.Equal(t, models.SCOPE_CHANGE_REMOVED, burndown.ScopeChanges[1].Kind)
+		require.Equal(t, -3, burndown.ScopeChanges[1].Change)
+
+		burndown, err = getSprintBurndown(ctx, stores, int(projectId), int(sprintId), models.BURNDOWN_UNIT_ISSUES)
+		require.Equal(t, nil, err)
+		require.Equal(t, 2, burndown.CommittedWork)
+		require.Equal(t, 1, *burndown.Days[0].Remaining)
+	})
+
+	testCase.Run("getSprintBurndown return error", func(t *testing.T) {
+		ctx := context.Background()
+		stores := internal.NewGormStores(internal.NewTestDatabase(t, config))
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+
+		_, err := getSprintBurndown(ctx, stores, int(projectId), int(sprintId), "hours")
+		require.Equal(t, &models.ErrorResponse{ErrorMessage: "The unit must be points or issues, got \"hours\"", ErrorCode: 400}, err)
+
+		_, err = getSprintBurndown(ctx, stores, int(projectId), 100, "")
+		require.Equal(t, 404, err.(*models.ErrorResponse).ErrorCode)
+	})
+}
@@ -2,18 +2,17 @@ package sprint
 
 import (
 	"issue-service/app/issue-api/routes/models"
+	"net/http"
 	"strings"
-
-	"gorm.io/gorm"
 )
 
 type sprintRouter struct {
-	routes   models.Routes
-	database *gorm.DB
+	routes models.Routes
+	stores models.Stores
 }
 
-func NewRouter(db *gorm.DB) models.Router {
-	r := &sprintRouter{database: db}
+func NewRouter(stores models.Stores) models.Router {
+	r := &sprintRouter{stores: stores}
 	r.initRoutes()
 	return r
 }
@@ -30,6 +29,11 @@ func (r *sprintRouter) initRoutes() {
 			Method:      strings.ToUpper("Post"),
 			Pattern:     "/v1/projects/{projectId}/sprints",
 			HandlerFunc: createAddSprintHandler,
+			Summary:     "Create a sprint in a project",
+			RequestBody: models.CreateSprintRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The id of the new sprint", Body: models.CreateResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
 		},
 
 		models.Route{
@@ -37,13 +41,91 @@ func (r *sprintRouter) initRoutes() {
 			Method:      strings.ToUpper("Patch"),
 			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}",
 			HandlerFunc: createPatchSprintHandler,
+			Summary:     "Update the fields of a sprint",
+			RequestBody: models.PatchSprintRequest{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "The sprint is updated"},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
 		},
 
 		models.Route{
-			Name:        "GetSprint",
+			Name:        "GetCurrentSprint",
 			Method:      strings.ToUpper("Get"),
-			Pattern:     "/v1/projects/{projectId}/sprints",
-			HandlerFunc: createGetSprintsHandler,
+			Pattern:     "/v1/projects/{projectId}/sprints/current",
+			HandlerFunc: createGetCurrentSprintHandler,
+			Summary:     "Get the sprint covering a day in the timezone of the project",
+			QueryParameters: []models.QueryParameter{
+				{Name: "date", Type: "string", Description: "the day, YYYY-MM-DD, today in the timezone of the project when missing"},
+			},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The sprint covering the day", Body: models.GetSprintResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "StartSprint",
+			Method:      strings.ToUpper("Post"),
+			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/start",
+			HandlerFunc: createStartSprintHandler,
+			Summary:     "Start a sprint, its issues are recorded as committed",
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The started sprint", Body: models.GetSprintResponse{}},
+			}, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:                "CompleteSprint",
+			Method:              strings.ToUpper("Post"),
+			Pattern:             "/v1/projects/{projectId}/sprints/{sprintId}/complete",
+			HandlerFunc:         createCompleteSprintHandler,
+			Summary:             "Complete the active sprint, its unfinished issues are carried over to the next sprint or to the backlog",
+			RequestBody:         models.CompleteSprintRequest{},
+			RequestBodyOptional: true,
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The completion snapshot of the sprint", Body: models.SprintCompletionResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetSprintCompletion",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/completion",
+			HandlerFunc: createGetSprintCompletionHandler,
+			Summary:     "Get the completion snapshot of a completed sprint",
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The completion snapshot of the sprint", Body: models.SprintCompletionResponse{}},
+			}, http.StatusNotFound, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:        "GetSprintBurndown",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/burndown",
+			HandlerFunc: createGetSprintBurndownHandler,
+			Summary:     "Get the ideal and the actual work of a sprint day by day, with the changes of its scope",
+			QueryParameters: []models.QueryParameter{
+				{Name: "unit", Type: "string", Description: "points or issues, the work counted, points when missing"},
+				{Name: "format", Type: "string", Description: "json, csv or svg, json when missing"},
+			},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {
+					Description:       "The burndown of the sprint, a CSV table or an SVG chart with the format csv or svg",
+					Body:              models.SprintBurndownResponse{},
+					OtherContentTypes: []string{"text/csv", "image/svg+xml"},
+				},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusInternalServerError),
+		},
+
+		models.Route{
+			Name:            "GetSprint",
+			Method:          strings.ToUpper("Get"),
+			Pattern:         "/v1/projects/{projectId}/sprints",
+			HandlerFunc:     createGetSprintsHandler,
+			Summary:         "List the sprints of a project",
+			QueryParameters: models.PageParameters,
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusOK: {Description: "The sprints", Body: []models.GetSprintResponse{}},
+			}, http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError),
 		},
 	}
 }
@@ -2,21 +2,22 @@ package sprint
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
-	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 func getPatchSprintFromRequestBody(r *http.Request) (models.PatchSprintRequest, error) {
 	var requestBody models.PatchSprintRequest
 	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-		log.WithField("error", err.Error()).Error("Error reading request body")
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
 		return models.PatchSprintRequest{}, &models.ErrorResponse{
 			ErrorMessage: "Error reading request body",
 			ErrorCode:    400,
@@ -30,7 +31,7 @@ func getSprintFromRequestBody(r *http.Request) (models.CreateSprintRequest, erro
 	var requestBody models.CreateSprintRequest
 	err := json.NewDecoder(r.Body).Decode(&requestBody)
 	if err != nil {
-		log.WithField("error", err.Error()).Error("Error reading request body")
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
 		return models.CreateSprintRequest{}, &models.ErrorResponse{
 			ErrorMessage: "Error reading request body",
 			ErrorCode:    400,
@@ -40,7 +41,7 @@ func getSprintFromRequestBody(r *http.Request) (models.CreateSprintRequest, erro
 	return requestBody, nil
 }
 
-func createAddSprintHandler(database *gorm.DB) http.HandlerFunc {
+func createAddSprintHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 		vars := mux.Vars(r)
@@ -70,7 +71,6 @@ func createAddSprintHandler(database *gorm.DB) http.HandlerFunc {
 			Number:            requestBody.Number,
 			StartDate:         requestBody.StartDate,
 			EndDate:           requestBody.EndDate,
-			Completed:         false,
 			MaxIssuePerSprint: requestBody.MaxIssuePerSprint,
 		}
 
@@ -85,7 +85,8 @@ func createAddSprintHandler(database *gorm.DB) http.HandlerFunc {
 		}
 
 		sprintId, err := createSprint(
-			database,
+			r.Context(),
+			stores,
 			requestSprint,
 		)
 		if err != nil {
@@ -103,7 +104,7 @@ func createAddSprintHandler(database *gorm.DB) http.HandlerFunc {
 	}
 }
 
-func createPatchSprintHandler(database *gorm.DB) http.HandlerFunc {
+func createPatchSprintHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 		vars := mux.Vars(r)
@@ -151,17 +152,24 @@ func createPatchSprintHandler(database *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		if requestBody.ProjectID != 0 && requestBody.ProjectID != projectIdInt {
+			internal.LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Sprints cannot be moved to another project",
+				ErrorCode:    400,
+			}, w)
+			return
+		}
+
 		requestSprint := models.Sprint{
 			ProjectID:         projectIdInt,
 			ID:                uint(sprintUid),
 			Number:            requestBody.Number,
 			StartDate:         requestBody.StartDate,
 			EndDate:           requestBody.EndDate,
-			Completed:         requestBody.Completed,
 			MaxIssuePerSprint: requestBody.MaxIssuePerSprint,
 		}
 
-		patchError := patchSprint(database, requestSprint)
+		patchError := patchSprint(r.Context(), stores, requestSprint)
 		if patchError != nil {
 			internal.LogAndReturnErrorResponse(patchError, w)
 			return
@@ -171,7 +179,7 @@ func createPatchSprintHandler(database *gorm.DB) http.HandlerFunc {
 	}
 }
 
-func createGetSprintsHandler(database *gorm.DB) http.HandlerFunc {
+func createGetSprintsHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 		vars := mux.Vars(r)
@@ -195,7 +203,13 @@ func createGetSprintsHandler(database *gorm.DB) http.HandlerFunc {
 			return
 		}
 
-		sprints, err := getSprints(database, projectIdInt)
+		page, err := internal.GetPageFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		sprints, err := getSprints(r.Context(), stores, projectIdInt, page)
 		if err != nil {
 			internal.LogAndReturnErrorResponse(err, w)
 			return
@@ -210,3 +224,201 @@ func createGetSprintsHandler(database *gorm.DB) http.HandlerFunc {
 		w.Write(responseBody)
 	}
 }
+
+func getProjectIdAndSprintIdFromRequest(r *http.Request) (int, int, error) {
+	vars := mux.Vars(r)
+	projectId, err := strconv.Atoi(vars["projectId"])
+	if err != nil {
+		return 0, 0, &models.ErrorResponse{
+			ErrorMessage: "Error parsing projectId to int",
+			ErrorCode:    500,
+		}
+	}
+	sprintId, err := strconv.Atoi(vars["sprintId"])
+	if err != nil {
+		return 0, 0, &models.ErrorResponse{
+			ErrorMessage: "Error parsing sprintId to int",
+			ErrorCode:    500,
+		}
+	}
+	return projectId, sprintId, nil
+}
+
+func getCompleteSprintFromRequestBody(r *http.Request) (models.CompleteSprintRequest, error) {
+	var requestBody models.CompleteSprintRequest
+	err := json.NewDecoder(r.Body).Decode(&requestBody)
+	// without body the unfinished issues are carried over to the backlog
+	if err != nil && !errors.Is(err, io.EOF) {
+		internal.RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error reading request body")
+		return models.CompleteSprintRequest{}, &models.ErrorResponse{
+			ErrorMessage: "Error reading request body",
+			ErrorCode:    400,
+		}
+	}
+
+	return requestBody, nil
+}
+
+func createStartSprintHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		sprint, err := startSprint(r.Context(), stores, projectId, sprintId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(sprint)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createCompleteSprintHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		requestBody, err := getCompleteSprintFromRequestBody(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		validationErr := internal.ValidateRequest(requestBody)
+		if validationErr != nil {
+			internal.LogAndReturnErrorResponse(validationErr, w)
+			return
+		}
+
+		completion, err := completeSprint(r.Context(), stores, projectId, sprintId, requestBody.NextSprintID)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(completion)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createGetSprintCompletionHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		completion, err := getSprintCompletion(r.Context(), stores, projectId, sprintId)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(completion)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createGetCurrentSprintHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, err := strconv.Atoi(mux.Vars(r)["projectId"])
+		if err != nil {
+			errorResponse := &models.ErrorResponse{
+				ErrorMessage: "Error parsing projectId to int",
+				ErrorCode:    500,
+			}
+			internal.LogAndReturnErrorResponse(errorResponse, w)
+			return
+		}
+
+		sprint, err := getCurrentSprint(r.Context(), stores, projectId, r.URL.Query().Get("date"))
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		responseBody, err := json.Marshal(sprint)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Write(responseBody)
+	}
+}
+
+func createGetSprintBurndownHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		projectId, sprintId, err := getProjectIdAndSprintIdFromRequest(r)
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = BURNDOWN_FORMAT_JSON
+		}
+		contentType, ok := burndownContentTypes[format]
+		if !ok {
+			errorResponse := &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("The format must be json, csv or svg, got \"%s\"", format),
+				ErrorCode:    400,
+			}
+			internal.LogAndReturnErrorResponse(errorResponse, w)
+			return
+		}
+
+		burndown, err := getSprintBurndown(r.Context(), stores, projectId, sprintId, r.URL.Query().Get("unit"))
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		var responseBody []byte
+		switch format {
+		case BURNDOWN_FORMAT_CSV:
+			responseBody, err = writeBurndownCSV(burndown)
+		case BURNDOWN_FORMAT_SVG:
+			responseBody = renderBurndownSVG(burndown)
+		default:
+			responseBody, err = json.Marshal(burndown)
+		}
+		if err != nil {
+			internal.LogAndReturnErrorResponse(err, w)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Write(responseBody)
+	}
+}
@@ -1,56 +1,150 @@
 package routes
 
 import (
+	"context"
 	"encoding/json"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
 	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	log "github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
-type StatusResponse struct {
-	Status  string `json:"status"`
-	Name    string `json:"name"`
-	Version string `json:"version"`
+// StatusRouter serves the probes. The liveness probe only tells that the process answers,
+// the readiness probe runs every check and fails once the server starts draining.
+type StatusRouter struct {
+	routes       models.Routes
+	draining     int32
+	checkTimeout time.Duration
+	checks       []models.StatusCheck
 }
 
-func CreateHealthinessHandler(database *gorm.DB) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-		w.WriteHeader(http.StatusOK)
+func NewStatusRouter(checkTimeout time.Duration, checks ...models.StatusCheck) *StatusRouter {
+	r := &StatusRouter{checkTimeout: checkTimeout, checks: checks}
+	r.initRoutes()
+	return r
+}
 
-		response := StatusResponse{
-			Status:  "OK",
-			Name:    "issue-service",
-			Version: "1.0.0",
-		}
+func (r *StatusRouter) Routes() models.Routes {
+	return r.routes
+}
 
-		byteReponse, err := json.Marshal(response)
+// StartDraining makes the readiness probe fail, so that the load balancers stop sending requests
+func (r *StatusRouter) StartDraining() {
+	atomic.StoreInt32(&r.draining, 1)
+}
 
-		if err != nil {
-			log.WithField("error", err.Error()).Fatal("Error in healthiness probe")
-		}
-		w.Write(byteReponse)
+func (r *StatusRouter) IsDraining() bool {
+	return atomic.LoadInt32(&r.draining) == 1
+}
+
+func (r *StatusRouter) initRoutes() {
+	r.routes = models.Routes{
+		models.Route{
+			Name:        "Healthiness",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/-/healthz",
+			HandlerFunc: CreateHealthinessHandler,
+			Summary:     "Liveness probe",
+			Responses: map[int]models.Response{
+				http.StatusOK: {Description: "The process serves requests", Body: models.StatusResponse{}},
+			},
+		},
+
+		models.Route{
+			Name:        "Readiness",
+			Method:      strings.ToUpper("Get"),
+			Pattern:     "/-/ready",
+			HandlerFunc: r.createReadinessHandler,
+			Summary:     "Readiness probe, checking the database and the migrations",
+			Responses: map[int]models.Response{
+				http.StatusOK:                 {Description: "Every check succeeded", Body: models.StatusResponse{}},
+				http.StatusServiceUnavailable: {Description: "A check failed or the server is draining", Body: models.StatusResponse{}},
+			},
+		},
+	}
+}
+
+func newStatusResponse(status string) models.StatusResponse {
+	return models.StatusResponse{
+		Status:    status,
+		Name:      "issue-service",
+		Version:   internal.Version,
+		CommitSha: internal.CommitSha,
+		BuildTime: internal.BuildTime,
 	}
 }
 
-func CreateReadinessHandler(database *gorm.DB) http.HandlerFunc {
+func writeStatusResponse(w http.ResponseWriter, statusCode int, response models.StatusResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(statusCode)
+
+	byteReponse, err := json.Marshal(response)
+
+	if err != nil {
+		log.WithField("error", err.Error()).Error("Error in status probe")
+	}
+	w.Write(byteReponse)
+}
+
+func CreateHealthinessHandler(stores models.Stores) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-		w.WriteHeader(http.StatusOK)
+		writeStatusResponse(w, http.StatusOK, newStatusResponse(models.STATUS_OK))
+	}
+}
 
-		response := StatusResponse{
-			Status:  "OK",
-			Name:    "issue-service",
-			Version: "1.0.0",
+func (r *StatusRouter) createReadinessHandler(stores models.Stores) http.HandlerFunc {
+	return func(w http.ResponseWriter, request *http.Request) {
+		if r.IsDraining() {
+			writeStatusResponse(w, http.StatusServiceUnavailable, newStatusResponse(models.STATUS_DRAINING))
+			return
 		}
 
-		byteReponse, err := json.Marshal(response)
+		response := newStatusResponse(models.STATUS_OK)
+		response.Checks = r.runChecks(request.Context())
 
-		if err != nil {
-			log.WithField("error", err.Error()).Fatal("Error in readiness probe")
+		statusCode := http.StatusOK
+		for _, check := range response.Checks {
+			if check.Status != models.STATUS_OK {
+				response.Status = models.STATUS_FAILED
+				statusCode = http.StatusServiceUnavailable
+				internal.RequestLogger(request.Context()).WithFields(log.Fields{"check": check.Name, "error": check.Message}).Warn("Readiness check failed")
+			}
 		}
+		writeStatusResponse(w, statusCode, response)
+	}
+}
+
+// runChecks runs every check concurrently, each one bounded by checkTimeout
+func (r *StatusRouter) runChecks(ctx context.Context) []models.StatusCheckResponse {
+	results := make([]models.StatusCheckResponse, len(r.checks))
+
+	var waitGroup sync.WaitGroup
+	for index, check := range r.checks {
+		waitGroup.Add(1)
+		go func(index int, check models.StatusCheck) {
+			defer waitGroup.Done()
+			checkContext, cancel := context.WithTimeout(ctx, r.checkTimeout)
+			defer cancel()
 
-		w.Write(byteReponse)
+			start := time.Now()
+			message, err := check.Check(checkContext)
+			results[index] = models.StatusCheckResponse{
+				Name:      check.Name,
+				Status:    models.STATUS_OK,
+				Message:   message,
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				results[index].Status = models.STATUS_FAILED
+				results[index].Message = err.Error()
+			}
+		}(index, check)
 	}
+	waitGroup.Wait()
+	return results
 }
@@ -0,0 +1,62 @@
+package webserver
+
+import (
+	_ "embed"
+	"fmt"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/internal"
+	"net/http"
+	"strings"
+)
+
+//go:embed docs/index.html
+var docsPage []byte
+
+// newOpenAPIRoutes serves the OpenAPI specification of routes and of themselves, and a page rendering it,
+// the document is returned to validate the requests
+func newOpenAPIRoutes(routes models.Routes) (models.Routes, internal.OpenAPIDocument, error) {
+	var specification []byte
+
+	openAPIRoutes := models.Routes{
+		models.Route{
+			Name:    "OpenAPI",
+			Method:  strings.ToUpper("Get"),
+			Pattern: "/-/openapi.json",
+			HandlerFunc: func(stores models.Stores) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+					w.Write(specification)
+				}
+			},
+			Summary: "OpenAPI 3 specification of the service",
+			Responses: map[int]models.Response{
+				http.StatusOK: {Description: "The specification", Body: map[string]interface{}{}},
+			},
+		},
+
+		models.Route{
+			Name:    "Docs",
+			Method:  strings.ToUpper("Get"),
+			Pattern: "/-/docs",
+			HandlerFunc: func(stores models.Stores) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+					w.Write(docsPage)
+				}
+			},
+			Summary: "Documentation of the API, rendered from the specification",
+			Responses: map[int]models.Response{
+				http.StatusOK: {Description: "The documentation page", Body: "", ContentType: "text/html"},
+			},
+		},
+	}
+
+	documentedRoutes := append(append(models.Routes{}, routes...), openAPIRoutes...)
+	document := internal.NewOpenAPIDocument(documentedRoutes)
+	content, err := internal.MarshalOpenAPIDocument(document)
+	if err != nil {
+		return nil, internal.OpenAPIDocument{}, fmt.Errorf("generating the OpenAPI specification: %w", err)
+	}
+	specification = content
+	return openAPIRoutes, document, nil
+}
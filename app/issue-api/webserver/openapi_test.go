@@ -0,0 +1,48 @@
+package webserver
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateOpenAPI = flag.Bool("update", false, "rewrite api/openapi.json from the routes")
+
+const openAPIFile = "../../../api/openapi.json"
+
+func TestOpenAPISpecification(t *testing.T) {
+	t.Parallel()
+	testRouter, _ := newTestRouter(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "/-/openapi.json", nil)
+	responseRecorder := httptest.NewRecorder()
+	testRouter.ServeHTTP(responseRecorder, request)
+	require.Equal(t, http.StatusOK, responseRecorder.Code, "The response statusCode should be 200")
+	require.True(t, json.Valid(responseRecorder.Body.Bytes()), "The specification should be JSON")
+
+	if *updateOpenAPI {
+		require.NoError(t, ioutil.WriteFile(openAPIFile, responseRecorder.Body.Bytes(), 0o644))
+	}
+	expected, err := ioutil.ReadFile(openAPIFile)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), responseRecorder.Body.String(),
+		"The routes or their bodies changed: run `go test ./app/issue-api/webserver -run TestOpenAPISpecification -update` and review api/openapi.json")
+}
+
+func TestDocsPage(t *testing.T) {
+	t.Parallel()
+	testRouter, _ := newTestRouter(t)
+
+	request, _ := http.NewRequest(http.MethodGet, "/-/docs", nil)
+	responseRecorder := httptest.NewRecorder()
+	testRouter.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code, "The response statusCode should be 200")
+	require.Equal(t, "text/html; charset=UTF-8", responseRecorder.Header().Get("Content-Type"))
+	require.Contains(t, responseRecorder.Body.String(), "openapi.json")
+}
@@ -1,11 +1,13 @@
 package webserver
 
 import (
-	"issue-service/app/issue-api/cfg"
 	"issue-service/app/issue-api/routes"
+	"issue-service/app/issue-api/routes/board"
 	"issue-service/app/issue-api/routes/issue"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/app/issue-api/routes/project"
+	"issue-service/app/issue-api/routes/report"
+	"issue-service/app/issue-api/routes/sla"
 	"issue-service/app/issue-api/routes/sprint"
 	"issue-service/internal"
 	"net/http"
@@ -15,24 +17,39 @@ import (
 	"github.com/urfave/negroni"
 )
 
-func NewRouter(config cfg.EnvConfiguration) *negroni.Negroni {
+func NewRouter(
+	stores models.Stores,
+	statusRouter *routes.StatusRouter,
+	metrics *internal.Metrics,
+	accessLogger *internal.AccessLogger,
+	reportResponseProblems internal.ResponseProblemsReporter,
+) (*negroni.Negroni, error) {
 	router := mux.NewRouter().StrictSlash(true)
 	nRouter := negroni.New(negroni.NewRecovery())
 
-	// todo receive this from args
-	db, err := internal.ConnectDatabase(config)
-
+	routesToRegister := append(models.Routes{}, statusRouter.Routes()...)
+	routesToRegister = append(routesToRegister, newMetricsRoute(metrics))
+	routesToRegister = append(routesToRegister, project.NewRouter(stores).Routes()...)
+	routesToRegister = append(routesToRegister, sprint.NewRouter(stores).Routes()...)
+	routesToRegister = append(routesToRegister, issue.NewRouter(stores).Routes()...)
+	routesToRegister = append(routesToRegister, report.NewRouter(stores).Routes()...)
+	routesToRegister = append(routesToRegister, board.NewRouter(stores).Routes()...)
+	routesToRegister = append(routesToRegister, sla.NewRouter(stores).Routes()...)
+	openAPIRoutes, document, err := newOpenAPIRoutes(routesToRegister)
 	if err != nil {
-		return &negroni.Negroni{}
+		return nil, err
 	}
-
-	routesToRegister = append(routesToRegister, project.NewRouter(db).Routes()...)
-	routesToRegister = append(routesToRegister, sprint.NewRouter(db).Routes()...)
-	routesToRegister = append(routesToRegister, issue.NewRouter(db).Routes()...)
+	routesToRegister = append(routesToRegister, openAPIRoutes...)
+	requestValidator := internal.NewRequestValidator(document, reportResponseProblems)
+	idempotency := internal.NewIdempotency(stores.Idempotency)
 	for _, route := range routesToRegister {
 		var handler http.Handler
-		handler = route.HandlerFunc(db)
-		handler = internal.Logger(handler, route.Name)
+		handler = route.HandlerFunc(stores)
+		handler = idempotency.Handler(handler)
+		handler = requestValidator.Handler(handler, route)
+		handler = accessLogger.Handler(handler, route.Name)
+		handler = internal.Trace(handler, route.Name, route.Pattern)
+		handler = metrics.Instrument(handler, route.Name)
 
 		router.
 			Methods(route.Method).
@@ -40,30 +57,35 @@ func NewRouter(config cfg.EnvConfiguration) *negroni.Negroni {
 			Name(route.Name).
 			Handler(handler)
 	}
+	router.NotFoundHandler = instrumentUnmatched(http.NotFoundHandler(), "NotFound", metrics, accessLogger)
+	router.MethodNotAllowedHandler = instrumentUnmatched(methodNotAllowedHandler(), "MethodNotAllowed", metrics, accessLogger)
 
 	nRouter.UseHandler(router)
-	return nRouter
+	return nRouter, nil
 }
 
-var routesToRegister = models.Routes{
-	models.Route{
-		Name:        "Healthiness",
-		Method:      strings.ToUpper("Get"),
-		Pattern:     "/-/healthz",
-		HandlerFunc: routes.CreateHealthinessHandler,
-	},
+func newMetricsRoute(metrics *internal.Metrics) models.Route {
+	return models.Route{
+		Name:    "Metrics",
+		Method:  strings.ToUpper("Get"),
+		Pattern: "/-/metrics",
+		HandlerFunc: func(stores models.Stores) http.HandlerFunc {
+			return metrics.Handler().ServeHTTP
+		},
+		Summary: "Prometheus metrics",
+		Responses: map[int]models.Response{
+			http.StatusOK: {Description: "The metrics in the Prometheus text format", Body: ""},
+		},
+	}
+}
 
-	models.Route{
-		Name:        "Healthiness",
-		Method:      strings.ToUpper("Get"),
-		Pattern:     "/-/ready",
-		HandlerFunc: routes.CreateReadinessHandler,
-	},
+// instrumentUnmatched logs, traces and counts the requests matching no route
+func instrumentUnmatched(handler http.Handler, name string, metrics *internal.Metrics, accessLogger *internal.AccessLogger) http.Handler {
+	return metrics.Instrument(internal.Trace(accessLogger.Handler(handler, name), name, ""), name)
+}
 
-	models.Route{
-		Name:        "GetIssuesById",
-		Method:      strings.ToUpper("Get"),
-		Pattern:     "/v1/projects/{projectId}/sprints/{sprintId}/issues/{issueId}",
-		HandlerFunc: routes.CreateGetIssuesByIdHandler,
-	},
+func methodNotAllowedHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	})
 }
@@ -2,13 +2,17 @@ package webserver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"issue-service/app/issue-api/routes"
 	"issue-service/app/issue-api/routes/models"
 	"issue-service/internal"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,7 +21,47 @@ import (
 	"github.com/urfave/negroni"
 )
 
-func callCreateProjectAPI(createProject models.CreateProjectRequest, testRouter *negroni.Negroni) {
+func newTestAccessLogger() *internal.AccessLogger {
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	return internal.NewAccessLogger(logger, 100)
+}
+
+// reportTestResponseProblems fails the test when a response does not match the OpenAPI specification
+func reportTestResponseProblems(t testing.TB) internal.ResponseProblemsReporter {
+	return func(ctx context.Context, routeName string, problems []string) {
+		t.Errorf("%s response not matching the specification:\n%s", routeName, strings.Join(problems, "\n"))
+	}
+}
+
+func newTestRouter(t testing.TB) (*negroni.Negroni, models.Stores) {
+	stores := internal.NewMemoryStores()
+	router, err := NewRouter(
+		stores,
+		routes.NewStatusRouter(time.Second),
+		internal.NewMetrics(internal.NewStoreCollector(stores)),
+		newTestAccessLogger(),
+		reportTestResponseProblems(t),
+	)
+	require.NoError(t, err)
+	return router, stores
+}
+
+// newStatusTestRouter serves the API from memory with the checks of statusRouter
+func newStatusTestRouter(t testing.TB, statusRouter *routes.StatusRouter) *negroni.Negroni {
+	router, err := NewRouter(internal.NewMemoryStores(), statusRouter, internal.NewMetrics(), newTestAccessLogger(), reportTestResponseProblems(t))
+	require.NoError(t, err)
+	return router
+}
+
+func getCreatedId(responseRecorder *httptest.ResponseRecorder) int {
+	var createResponse models.CreateResponse
+	json.NewDecoder(responseRecorder.Result().Body).Decode(&createResponse)
+	id, _ := strconv.Atoi(createResponse.Id)
+	return id
+}
+
+func callCreateProjectAPI(createProject models.CreateProjectRequest, testRouter *negroni.Negroni) int {
 	requestBody, err := json.Marshal(createProject)
 
 	if err != nil {
@@ -30,13 +74,14 @@ func callCreateProjectAPI(createProject models.CreateProjectRequest, testRouter
 	responseRecorder := httptest.NewRecorder()
 
 	testRouter.ServeHTTP(responseRecorder, request)
+	return getCreatedId(responseRecorder)
 }
 
 func callCreateSprintAPI(
 	createSprint models.CreateSprintRequest,
 	projectId int,
 	testRouter *negroni.Negroni,
-) {
+) int {
 	requestBody, err := json.Marshal(createSprint)
 
 	if err != nil {
@@ -49,12 +94,12 @@ func callCreateSprintAPI(
 	responseRecorder := httptest.NewRecorder()
 
 	testRouter.ServeHTTP(responseRecorder, request)
+	return getCreatedId(responseRecorder)
 }
 
-// TODO remove this and use database.Create()
 func callCreateProjectAndSprint(
 	testRouter *negroni.Negroni,
-) {
+) (projectId int, sprintId int) {
 	sprintNumber := internal.GetRandomStringName(10)
 	inputProject := models.CreateProjectRequest{
 		Name:   sprintNumber,
@@ -66,86 +111,151 @@ func callCreateProjectAndSprint(
 		StartDate: time.Now(),
 		EndDate:   time.Now().AddDate(0, 0, 7),
 	}
-	callCreateProjectAPI(inputProject, testRouter)
-	callCreateSprintAPI(inputSprint, 1, testRouter)
+	projectId = callCreateProjectAPI(inputProject, testRouter)
+	sprintId = callCreateSprintAPI(inputSprint, projectId, testRouter)
+	return
 }
 
 // Health routes tests
+func callStatusAPI(t *testing.T, testRouter *negroni.Negroni, path string) (int, models.StatusResponse) {
+	responseRecorder := httptest.NewRecorder()
+	request, requestError := http.NewRequest(http.MethodGet, path, nil)
+	require.NoError(t, requestError, "Error creating the %s request", path)
+
+	testRouter.ServeHTTP(responseRecorder, request)
+
+	var response models.StatusResponse
+	require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&response))
+	return responseRecorder.Result().StatusCode, response
+}
+
 func TestStatusRoutes(testCase *testing.T) {
 	serviceName := "issue-service"
-	serviceVersion := "1.0.0"
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-	testRouter := NewRouter(config)
+	testCase.Parallel()
+	testRouter, _ := newTestRouter(testCase)
 
 	testCase.Run("/-/healthz - ok", func(t *testing.T) {
-		expectedResponse := fmt.Sprintf("{\"status\":\"OK\",\"name\":\"%s\",\"version\":\"%s\"}", serviceName, serviceVersion)
-		responseRecorder := httptest.NewRecorder()
-		request, requestError := http.NewRequest(http.MethodGet, "/-/healthz", nil)
-		require.NoError(t, requestError, "Error creating the /-/healthz request")
+		statusCode, response := callStatusAPI(t, testRouter, "/-/healthz")
 
-		testRouter.ServeHTTP(responseRecorder, request)
-		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusOK, statusCode, "The response statusCode should be 200")
-
-		rawBody := responseRecorder.Result().Body
-		body, readBodyError := ioutil.ReadAll(rawBody)
-		require.NoError(t, readBodyError)
-		require.Equal(t, expectedResponse, string(body), "The response body should be the expected one")
+		require.Equal(t, models.StatusResponse{
+			Status:    "OK",
+			Name:      serviceName,
+			Version:   internal.Version,
+			CommitSha: internal.CommitSha,
+			BuildTime: internal.BuildTime,
+		}, response, "The response body should be the expected one")
 	})
 
 	testCase.Run("/-/ready - ok", func(t *testing.T) {
-		expectedResponse := fmt.Sprintf("{\"status\":\"OK\",\"name\":\"%s\",\"version\":\"%s\"}", serviceName, serviceVersion)
-		responseRecorder := httptest.NewRecorder()
-		request, requestError := http.NewRequest(http.MethodGet, "/-/ready", nil)
-		require.NoError(t, requestError, "Error creating the /-/ready request")
+		statusCode, response := callStatusAPI(t, testRouter, "/-/ready")
 
-		testRouter.ServeHTTP(responseRecorder, request)
-		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusOK, statusCode, "The response statusCode should be 200")
+		require.Equal(t, "OK", response.Status)
+		require.Equal(t, serviceName, response.Name)
+		require.Equal(t, internal.Version, response.Version)
+	})
 
-		rawBody := responseRecorder.Result().Body
-		body, readBodyError := ioutil.ReadAll(rawBody)
-		require.NoError(t, readBodyError)
-		require.Equal(t, expectedResponse, string(body), "The response body should be the expected one")
+	testCase.Run("/-/ready - 503 - a check fails", func(t *testing.T) {
+		statusRouter := routes.NewStatusRouter(time.Second,
+			models.StatusCheck{Name: "database", Check: func(ctx context.Context) (string, error) {
+				return "postgres", nil
+			}},
+			models.StatusCheck{Name: "migrations", Check: func(ctx context.Context) (string, error) {
+				return "", fmt.Errorf("pending migrations: 0002_add_priority")
+			}},
+		)
+		statusCode, response := callStatusAPI(t, newStatusTestRouter(t, statusRouter), "/-/ready")
+
+		require.Equal(t, http.StatusServiceUnavailable, statusCode, "The response statusCode should be 503")
+		require.Equal(t, "Failed", response.Status)
+		require.Equal(t, 2, len(response.Checks))
+		require.Equal(t, "database", response.Checks[0].Name)
+		require.Equal(t, "OK", response.Checks[0].Status)
+		require.Equal(t, "postgres", response.Checks[0].Message)
+		require.Equal(t, "migrations", response.Checks[1].Name)
+		require.Equal(t, "Failed", response.Checks[1].Status)
+		require.Equal(t, "pending migrations: 0002_add_priority", response.Checks[1].Message)
+	})
+
+	testCase.Run("/-/ready - 503 - a check times out", func(t *testing.T) {
+		statusRouter := routes.NewStatusRouter(10*time.Millisecond,
+			models.StatusCheck{Name: "database", Check: func(ctx context.Context) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			}},
+		)
+		statusCode, response := callStatusAPI(t, newStatusTestRouter(t, statusRouter), "/-/ready")
+
+		require.Equal(t, http.StatusServiceUnavailable, statusCode, "The response statusCode should be 503")
+		require.Equal(t, context.DeadlineExceeded.Error(), response.Checks[0].Message)
+		require.GreaterOrEqual(t, response.Checks[0].LatencyMs, float64(10))
+	})
+
+	testCase.Run("/-/ready - 503 - draining", func(t *testing.T) {
+		statusRouter := routes.NewStatusRouter(time.Second)
+		drainingRouter := newStatusTestRouter(t, statusRouter)
+		statusRouter.StartDraining()
+
+		statusCode, response := callStatusAPI(t, drainingRouter, "/-/ready")
+		require.Equal(t, http.StatusServiceUnavailable, statusCode, "The response statusCode should be 503")
+		require.Equal(t, "Draining", response.Status)
+
+		statusCode, _ = callStatusAPI(t, drainingRouter, "/-/healthz")
+		require.Equal(t, http.StatusOK, statusCode, "The server is still healthy while draining")
 	})
 }
 
-// Projects tests
-func TestCreateProjectHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
+func TestMetricsRoute(t *testing.T) {
+	t.Parallel()
+	testRouter, _ := newTestRouter(t)
+	projectId, sprintId := callCreateProjectAndSprint(testRouter)
+	startRequest, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/start", projectId, sprintId), nil)
+	testRouter.ServeHTTP(httptest.NewRecorder(), startRequest)
+	unknownRequest, _ := http.NewRequest(http.MethodGet, "/v1/unknown", nil)
+	testRouter.ServeHTTP(httptest.NewRecorder(), unknownRequest)
 
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
+	request, _ := http.NewRequest(http.MethodGet, "/-/metrics", nil)
+	responseRecorder := httptest.NewRecorder()
+	testRouter.ServeHTTP(responseRecorder, request)
+
+	require.Equal(t, http.StatusOK, responseRecorder.Code, "The response statusCode should be 200")
+	body := responseRecorder.Body.String()
+	require.Contains(t, body, `issue_service_http_requests_total{code="200",route="AddProject"} 1`)
+	require.Contains(t, body, `issue_service_http_requests_total{code="404",route="NotFound"} 1`)
+	require.Contains(t, body, `issue_service_http_request_duration_seconds_count{code="200",route="AddSprint"} 1`)
+	require.Contains(t, body, fmt.Sprintf(`issue_service_active_sprints{project_id="%d"} 1`, projectId))
+}
+
+func TestRequestId(t *testing.T) {
+	t.Parallel()
+	testRouter, _ := newTestRouter(t)
+
+	for _, path := range []string{"/v1/projects", "/v1/unknown"} {
+		request, _ := http.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set(internal.REQUEST_ID_HEADER, "request-1")
+		responseRecorder := httptest.NewRecorder()
+		testRouter.ServeHTTP(responseRecorder, request)
+
+		require.Equal(t, "request-1", responseRecorder.Header().Get(internal.REQUEST_ID_HEADER), "The request id should be sent back")
 	}
+}
+
+// Projects tests
+func TestCreateProjectHandler(testCase *testing.T) {
+	testCase.Parallel()
 
 	projectName := internal.GetRandomStringName(10)
 
-	// TODO change this to CreateProjectRequest
-	inputProject := models.Project{
+	inputProject := models.CreateProjectRequest{
 		Name:   projectName,
 		Client: "client-name",
 		Type:   "project-type",
 	}
 
 	testCase.Run("/projects - 200 - project created", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-
-		expectedResponse := models.CreateResponse{
-			Id: "1",
-		}
-
-		expectedJsonReponse, _ := json.Marshal(expectedResponse)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
 
 		requestBody, err := json.Marshal(inputProject)
 
@@ -163,8 +273,15 @@ func TestCreateProjectHandler(testCase *testing.T) {
 		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusOK, statusCode, "The response statusCode should be 200")
 
-		var foundProject models.Project
-		database.First(&foundProject)
+		foundProjects, _ := stores.Projects.List(context.Background(), models.Page{})
+		require.Equal(t, 1, len(foundProjects))
+		foundProject := foundProjects[0]
+
+		expectedResponse := models.CreateResponse{
+			Id: fmt.Sprint(foundProject.ID),
+		}
+
+		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
 		rawBody := responseRecorder.Result().Body
 		body, readBodyError := ioutil.ReadAll(rawBody)
@@ -174,16 +291,17 @@ func TestCreateProjectHandler(testCase *testing.T) {
 	})
 
 	testCase.Run("/projects - 400 - request has wrong types", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
 		expectedResponse := models.ErrorResponse{
-			ErrorMessage: "Error reading request body",
+			ErrorMessage: "body.type: is required\nbody.name: must be a string, got boolean",
 			ErrorCode:    400,
 		}
 
 		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
 		type WrongProject struct {
-			Name bool
+			Name bool `json:"name"`
 		}
 
 		inputProject := WrongProject{
@@ -213,15 +331,16 @@ func TestCreateProjectHandler(testCase *testing.T) {
 	})
 
 	testCase.Run("/projects - 400 - missing name", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
 		expectedResponse := models.ErrorResponse{
-			ErrorMessage: "Validation error, field: CreateProjectRequest.Name, tag: required",
+			ErrorMessage: "body.name: is required",
 			ErrorCode:    400,
 		}
 
 		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
-		inputProject := models.Project{
+		inputProject := models.CreateProjectRequest{
 			Client: "client-name",
 			Type:   "project-type",
 		}
@@ -249,15 +368,16 @@ func TestCreateProjectHandler(testCase *testing.T) {
 	})
 
 	testCase.Run("/projects - 400 - missing name and type", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
 		expectedResponse := models.ErrorResponse{
-			ErrorMessage: "Validation error, field: CreateProjectRequest.Name, tag: required\nValidation error, field: CreateProjectRequest.Type, tag: required",
+			ErrorMessage: "body.name: is required\nbody.type: is required",
 			ErrorCode:    400,
 		}
 
 		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
-		inputProject := models.Project{
+		inputProject := models.CreateProjectRequest{
 			Client: "client-name",
 		}
 
@@ -284,8 +404,10 @@ func TestCreateProjectHandler(testCase *testing.T) {
 	})
 
 	testCase.Run("/projects - 409 - project already exists", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		database.Create(&inputProject)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		existingProject := models.Project{Name: inputProject.Name, Client: inputProject.Client, Type: inputProject.Type}
+		stores.Projects.Create(context.Background(), &existingProject)
 
 		expectedResponse := models.ErrorResponse{
 			ErrorMessage: fmt.Sprintf("Project with name \"%s\" already exists", projectName),
@@ -318,20 +440,11 @@ func TestCreateProjectHandler(testCase *testing.T) {
 }
 
 func TestGetProjectsHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
+	testCase.Parallel()
 
 	testCase.Run("/projects - 200 - returned list of projects", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
 		expectedProjectName := internal.GetRandomStringName(10)
 		expectedType := "project-type"
 		expectedClient := "project-client"
@@ -341,18 +454,18 @@ func TestGetProjectsHandler(testCase *testing.T) {
 			Client: expectedClient,
 		}
 
-		callCreateProjectAPI(inputProject, testRouter)
+		projectId := callCreateProjectAPI(inputProject, testRouter)
 
 		expectedResponse := []models.Project{
 			{
-				ID:     1,
+				ID:     uint(projectId),
 				Name:   expectedProjectName,
 				Type:   expectedType,
 				Client: expectedClient,
 			},
 		}
 
-		expectedJsonReponse, _ := json.Marshal(expectedResponse)
+		expectedJsonReponse, err := json.Marshal(expectedResponse)
 
 		if err != nil {
 			log.WithField("error", err.Error()).Error("Error marshaling json")
@@ -372,47 +485,74 @@ func TestGetProjectsHandler(testCase *testing.T) {
 
 		internal.AssertProjectsEquality(t, expectedJsonReponse, body)
 	})
+
+	testCase.Run("/projects - 200 - page of projects", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectIds := []int{}
+		for index := 0; index < 3; index++ {
+			projectIds = append(projectIds, callCreateProjectAPI(models.CreateProjectRequest{
+				Name: internal.GetRandomStringName(10),
+				Type: "project-type",
+			}, testRouter))
+		}
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, "/v1/projects?limit=1&offset=1", nil)
+		require.NoError(t, requestError, "Error creating the /projects request")
+		testRouter.ServeHTTP(responseRecorder, request)
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+
+		var foundProjects []models.Project
+		require.NoError(t, json.NewDecoder(responseRecorder.Body).Decode(&foundProjects))
+		require.Equal(t, 1, len(foundProjects))
+		require.Equal(t, uint(projectIds[1]), foundProjects[0].ID)
+	})
+
+	testCase.Run("/projects - 400 - invalid page", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+
+		for query, expectedMessage := range map[string]string{
+			"limit=ten":  "query.limit: must be an integer",
+			"limit=0":    "limit must be between 1 and 1000",
+			"limit=1001": "limit must be between 1 and 1000",
+			"offset=-1":  "offset must be a positive integer",
+		} {
+			responseRecorder := httptest.NewRecorder()
+			request, requestError := http.NewRequest(http.MethodGet, "/v1/projects?"+query, nil)
+			require.NoError(t, requestError, "Error creating the /projects request")
+			testRouter.ServeHTTP(responseRecorder, request)
+			require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode, query)
+
+			expectedJsonReponse, _ := json.Marshal(models.ErrorResponse{ErrorMessage: expectedMessage, ErrorCode: 400})
+			require.Equal(t, fmt.Sprintf("%s\n", string(expectedJsonReponse)), responseRecorder.Body.String(), query)
+		}
+	})
 }
 
 // Sprints tests
 func TestCreateSprintHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
+	testCase.Parallel()
 
 	sprintNumber := "12345"
 
-	// TODO change this to CreateSprintRequest
-	inputSprint := models.Sprint{
+	inputSprint := models.CreateSprintRequest{
 		Number:    sprintNumber,
 		StartDate: time.Now(),
 		EndDate:   time.Now().AddDate(0, 0, 7),
 	}
 
 	testCase.Run("/sprints - 200 - sprint created", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
 		inputProject := models.CreateProjectRequest{
 			Name:   internal.GetRandomStringName(10),
 			Type:   "project-type",
 			Client: "project-client",
 		}
 
-		callCreateProjectAPI(inputProject, testRouter)
-
-		expectedResponse := models.CreateResponse{
-			Id: "1",
-		}
-
-		expectedJsonReponse, _ := json.Marshal(expectedResponse)
+		projectId := callCreateProjectAPI(inputProject, testRouter)
 
 		requestBody, err := json.Marshal(inputSprint)
 		if err != nil {
@@ -422,15 +562,22 @@ func TestCreateSprintHandler(testCase *testing.T) {
 		bodyReader := bytes.NewReader(requestBody)
 
 		responseRecorder := httptest.NewRecorder()
-		request, requestError := http.NewRequest(http.MethodPost, "/v1/projects/1/sprints", bodyReader)
+		request, requestError := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints", projectId), bodyReader)
 		require.NoError(t, requestError, "Error creating the /sprints request")
 
 		testRouter.ServeHTTP(responseRecorder, request)
 		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusOK, statusCode, "The response statusCode should be 200")
 
-		var foundSprint models.Sprint
-		database.First(&foundSprint)
+		foundSprints, _ := stores.Sprints.ListByProject(context.Background(), projectId, models.Page{})
+		require.Equal(t, 1, len(foundSprints))
+		foundSprint := foundSprints[0]
+
+		expectedResponse := models.CreateResponse{
+			Id: fmt.Sprint(foundSprint.ID),
+		}
+
+		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
 		rawBody := responseRecorder.Result().Body
 		body, readBodyError := ioutil.ReadAll(rawBody)
@@ -439,46 +586,75 @@ func TestCreateSprintHandler(testCase *testing.T) {
 		require.Equal(t, sprintNumber, foundSprint.Number)
 		require.Equal(t, false, foundSprint.Completed)
 	})
+
+	testCase.Run("/sprints - 400 - dates missing or zero", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId := callCreateProjectAPI(models.CreateProjectRequest{Name: internal.GetRandomStringName(10), Type: "project-type"}, testRouter)
+
+		for _, requestBody := range []string{
+			`{"number": "1", "endDate": "2022-10-14T00:00:00Z"}`,
+			`{"number": "1", "startDate": "0001-01-01T00:00:00Z", "endDate": "2022-10-14T00:00:00Z"}`,
+		} {
+			responseRecorder := httptest.NewRecorder()
+			request, requestError := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints", projectId), strings.NewReader(requestBody))
+			require.NoError(t, requestError, "Error creating the /sprints request")
+
+			testRouter.ServeHTTP(responseRecorder, request)
+			require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode, requestBody)
+		}
+	})
+
+	testCase.Run("/sprints/current - 200 - sprint covering the day", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId := callCreateProjectAPI(models.CreateProjectRequest{Name: internal.GetRandomStringName(10), Type: "project-type", Timezone: "Europe/Rome"}, testRouter)
+		sprintId := callCreateSprintAPI(inputSprint, projectId, testRouter)
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/sprints/current", projectId), nil)
+		require.NoError(t, requestError, "Error creating the /sprints/current request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var sprint models.GetSprintResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&sprint))
+		require.Equal(t, uint(sprintId), sprint.ID)
+
+		responseRecorder = httptest.NewRecorder()
+		request, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/sprints/current?date=2000-01-01", projectId), nil)
+		testRouter.ServeHTTP(responseRecorder, request)
+		require.Equal(t, http.StatusNotFound, responseRecorder.Result().StatusCode)
+	})
 }
 
 func TestPatchSprintHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
+	testCase.Parallel()
 
 	sprintNumber := "12345"
-	projectId := 1
-	inputSprint := models.Sprint{
-		ProjectID: projectId,
-		Completed: false,
-		Number:    sprintNumber,
-		StartDate: time.Now(),
-		EndDate:   time.Now().AddDate(0, 0, 7),
-	}
 
 	testCase.Run("/sprints patch - 204 - sprint patched", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
 		inputProject := models.Project{
 			Name:   internal.GetRandomStringName(10),
 			Type:   "project-type",
 			Client: "project-client",
 		}
 
-		database.Create(&inputProject)
-		database.Create(&inputSprint)
+		stores.Projects.Create(context.Background(), &inputProject)
+		inputSprint := models.Sprint{
+			ProjectID: int(inputProject.ID),
+			Completed: false,
+			Number:    sprintNumber,
+			StartDate: time.Now(),
+			EndDate:   time.Now().AddDate(0, 0, 7),
+		}
+		stores.Sprints.Create(context.Background(), &inputSprint)
 
 		patchSprint := models.PatchSprintRequest{
-			ID:        inputSprint.ID,
-			Completed: true,
+			ID:                inputSprint.ID,
+			MaxIssuePerSprint: 10,
 		}
 		requestBody, err := json.Marshal(patchSprint)
 		if err != nil {
@@ -487,30 +663,23 @@ func TestPatchSprintHandler(testCase *testing.T) {
 
 		responseRecorder := httptest.NewRecorder()
 		bodyReader := bytes.NewReader(requestBody)
-		request, requestError := http.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/projects/1/sprints/%d", inputSprint.ID), bodyReader)
+		request, requestError := http.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/projects/%d/sprints/%d", inputProject.ID, inputSprint.ID), bodyReader)
 		require.NoError(t, requestError, "Error creating the /sprints request")
 
 		testRouter.ServeHTTP(responseRecorder, request)
 		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusNoContent, statusCode, "The response statusCode should be 204")
 
-		var foundSprint models.Sprint
-		database.First(&foundSprint)
-		require.Equal(t, true, foundSprint.Completed)
+		foundSprint, _ := stores.Sprints.Get(context.Background(), int(inputProject.ID), int(inputSprint.ID))
+		require.Equal(t, 10, foundSprint.MaxIssuePerSprint)
 	})
 
 	testCase.Run("/sprints patch - 404 - project does not exists", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
 		wrongProjectId := 99999
-		inputProject := models.Project{
-			Name:   internal.GetRandomStringName(10),
-			Type:   "project-type",
-			Client: "project-client",
-		}
-
-		database.Create(&inputProject)
+		_, sprintId := internal.CreateProjectAndSprint(stores)
 
-		database.Create(&inputSprint)
 		expectedResponse := models.ErrorResponse{
 			ErrorMessage: fmt.Sprintf("Project with id \"%d\" does not exists", wrongProjectId),
 			ErrorCode:    404,
@@ -519,8 +688,8 @@ func TestPatchSprintHandler(testCase *testing.T) {
 		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
 		patchSprint := models.PatchSprintRequest{
-			ID:        inputSprint.ID,
-			Completed: true,
+			ID:     sprintId,
+			Number: "2",
 		}
 		requestBody, err := json.Marshal(patchSprint)
 		if err != nil {
@@ -529,7 +698,7 @@ func TestPatchSprintHandler(testCase *testing.T) {
 
 		responseRecorder := httptest.NewRecorder()
 		bodyReader := bytes.NewReader(requestBody)
-		request, requestError := http.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/projects/%d/sprints/%d", wrongProjectId, inputSprint.ID), bodyReader)
+		request, requestError := http.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/projects/%d/sprints/%d", wrongProjectId, sprintId), bodyReader)
 		require.NoError(t, requestError, "Error creating the /sprints request")
 
 		testRouter.ServeHTTP(responseRecorder, request)
@@ -544,43 +713,35 @@ func TestPatchSprintHandler(testCase *testing.T) {
 }
 
 func TestGetSprintsHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
+	testCase.Parallel()
 
 	sprintNumber := "12345"
-	projectId := 1
-	inputSprint := models.Sprint{
-		ProjectID: projectId,
-		Completed: false,
-		Number:    sprintNumber,
-		StartDate: time.Now(),
-		EndDate:   time.Now().AddDate(0, 0, 7),
-	}
 
 	testCase.Run("/sprints get - 200 - sprints returned", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
 
 		inputProject := models.Project{
 			Name:   internal.GetRandomStringName(10),
 			Type:   "project-type",
 			Client: "project-client",
 		}
+		stores.Projects.Create(context.Background(), &inputProject)
+		projectId := int(inputProject.ID)
+
+		inputSprint := models.Sprint{
+			ProjectID: projectId,
+			Completed: false,
+			Number:    sprintNumber,
+			StartDate: time.Now(),
+			EndDate:   time.Now().AddDate(0, 0, 7),
+		}
 		newSprintNumber := "98765"
 		newSprint := inputSprint
 		newSprint.Number = newSprintNumber
-		database.Create(&inputProject)
-		database.Create(&inputSprint)
-		database.Create(&newSprint)
+		newSprint.StartDate, newSprint.EndDate = inputSprint.EndDate, inputSprint.EndDate.AddDate(0, 0, 7)
+		stores.Sprints.Create(context.Background(), &inputSprint)
+		stores.Sprints.Create(context.Background(), &newSprint)
 
 		expectedResponse := []models.GetSprintResponse{
 			inputSprint.GetSprintResponseFromSprint(),
@@ -602,23 +763,190 @@ func TestGetSprintsHandler(testCase *testing.T) {
 		require.NoError(t, readBodyError)
 		internal.AssertSprintsEquality(t, expectedJsonReponse, body)
 	})
+
+	testCase.Run("/sprints - 200 - sprints with their points", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		for _, requestBody := range []string{
+			`{"type": "Task", "title": "Open", "storyPoints": 5}`,
+			`{"type": "Task", "title": "Closed", "storyPoints": 3, "status": "Done"}`,
+		} {
+			request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId), strings.NewReader(requestBody))
+			responseRecorder := httptest.NewRecorder()
+			testRouter.ServeHTTP(responseRecorder, request)
+			require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		}
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/sprints", projectId), nil)
+		require.NoError(t, requestError, "Error creating the /sprints request")
+		testRouter.ServeHTTP(responseRecorder, request)
+
+		var sprints []models.GetSprintResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&sprints))
+		require.Equal(t, 8, sprints[0].CommittedPoints)
+		require.Equal(t, 3, sprints[0].CompletedPoints)
+	})
 }
 
-// Issue tests
-func TestCreateIssueHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
+// Report tests
+func TestVelocityReportHandler(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("/reports/velocity - 200", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		for _, call := range []struct {
+			path string
+			body string
+		}{
+			{fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId), `{"type": "Task", "title": "Done", "storyPoints": 5, "status": "Done"}`},
+			{fmt.Sprintf("/v1/projects/%d/sprints/%d/start", projectId, sprintId), ""},
+			{fmt.Sprintf("/v1/projects/%d/sprints/%d/complete", projectId, sprintId), "{}"},
+		} {
+			request, _ := http.NewRequest(http.MethodPost, call.path, strings.NewReader(call.body))
+			responseRecorder := httptest.NewRecorder()
+			testRouter.ServeHTTP(responseRecorder, request)
+			require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode, responseRecorder.Body.String())
+		}
 
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/reports/velocity?simulations=10", projectId), nil)
+		require.NoError(t, requestError, "Error creating the /reports/velocity request")
+		testRouter.ServeHTTP(responseRecorder, request)
+
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var report models.VelocityReportResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&report))
+		require.Equal(t, 1, len(report.Sprints))
+		require.Equal(t, 5, report.Sprints[0].Completed)
+		require.Equal(t, 5.0, report.AverageVelocity)
+		require.Equal(t, 10, report.Forecast.Simulations)
+	})
+
+	testCase.Run("/reports/velocity - 400 - invalid window", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/reports/velocity?window=0", projectId), nil)
+		require.NoError(t, requestError, "Error creating the /reports/velocity request")
+		testRouter.ServeHTTP(responseRecorder, request)
+
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+	})
+}
+
+func TestFlowReportHandler(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("/reports/flow - 200", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		request, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId),
+			strings.NewReader(`{"type": "Bug", "title": "Done", "assignee": "alice", "status": "Done"}`))
+		responseRecorder := httptest.NewRecorder()
+		testRouter.ServeHTTP(responseRecorder, request)
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+
+		responseRecorder = httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/reports/flow?assignee=alice", projectId), nil)
+		require.NoError(t, requestError, "Error creating the /reports/flow request")
+		testRouter.ServeHTTP(responseRecorder, request)
+
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var report models.FlowReportResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&report))
+		require.Equal(t, 1, len(report.Issues))
+		require.Equal(t, 0.0, report.Issues[0].LeadTimeDays)
+		require.Equal(t, []string{"Done"}, report.Statuses)
+	})
+
+	testCase.Run("/reports/flow - 400 - invalid date", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/reports/flow?from=yesterday", projectId), nil)
+		require.NoError(t, requestError, "Error creating the /reports/flow request")
+		testRouter.ServeHTTP(responseRecorder, request)
+
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+	})
+}
+
+func TestBoardHandler(testCase *testing.T) {
+	testCase.Parallel()
+
+	callBoardAPI := func(t *testing.T, testRouter *negroni.Negroni, method string, path string, body string) *httptest.ResponseRecorder {
+		request, requestError := http.NewRequest(method, path, strings.NewReader(body))
+		require.NoError(t, requestError, "Error creating the %s request", path)
+		responseRecorder := httptest.NewRecorder()
+		testRouter.ServeHTTP(responseRecorder, request)
+		return responseRecorder
 	}
 
+	testCase.Run("/board - 200", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		issueIds := []int{}
+		for _, assignee := range []string{"alice", "bob"} {
+			responseRecorder := callBoardAPI(t, testRouter, http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId),
+				fmt.Sprintf(`{"type": "Task", "title": "Board", "assignee": "%s"}`, assignee))
+			require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+			issueIds = append(issueIds, getCreatedId(responseRecorder))
+		}
+
+		responseRecorder := callBoardAPI(t, testRouter, http.MethodPut, fmt.Sprintf("/v1/projects/%d/board", projectId),
+			`{"swimlane": "assignee", "scope": "project", "columns": [{"name": "To Do", "statuses": [""]}, {"name": "Doing", "statuses": ["In Progress"], "wipLimit": 1}]}`)
+		require.Equal(t, http.StatusNoContent, responseRecorder.Result().StatusCode, responseRecorder.Body.String())
+
+		responseRecorder = callBoardAPI(t, testRouter, http.MethodPost, fmt.Sprintf("/v1/projects/%d/board/issues/%d/move", projectId, issueIds[0]), `{"column": "Doing"}`)
+		require.Equal(t, http.StatusNoContent, responseRecorder.Result().StatusCode, responseRecorder.Body.String())
+		responseRecorder = callBoardAPI(t, testRouter, http.MethodPost, fmt.Sprintf("/v1/projects/%d/board/issues/%d/move", projectId, issueIds[1]), `{"column": "Doing"}`)
+		require.Equal(t, http.StatusConflict, responseRecorder.Result().StatusCode)
+
+		responseRecorder = callBoardAPI(t, testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/board", projectId), "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var board models.BoardResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&board))
+		require.Equal(t, models.SWIMLANE_ASSIGNEE, board.Swimlane)
+		require.Equal(t, models.BOARD_SCOPE_PROJECT, board.Scope)
+		require.Equal(t, 1, board.Columns[1].Issues)
+		require.Equal(t, 2, len(board.Swimlanes))
+		require.Equal(t, "In Progress", board.Swimlanes[0].Cells[1].Issues[0].Status)
+		require.Equal(t, uint(issueIds[1]), board.Swimlanes[1].Cells[0].Issues[0].ID)
+	})
+
+	testCase.Run("/board - 404 - project without board", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := callBoardAPI(t, testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/board", projectId), "")
+		require.Equal(t, http.StatusNotFound, responseRecorder.Result().StatusCode)
+	})
+
+	testCase.Run("/board - 400 - column without statuses", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := callBoardAPI(t, testRouter, http.MethodPut, fmt.Sprintf("/v1/projects/%d/board", projectId), `{"columns": [{"name": "To Do", "statuses": []}]}`)
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+	})
+}
+
+// Issue tests
+func TestCreateIssueHandler(testCase *testing.T) {
+	testCase.Parallel()
+
 	expectedTitle := "Task title"
 	expectedDescription := "Task description"
 	inputIssue := models.CreateIssueRequest{
@@ -630,14 +958,9 @@ func TestCreateIssueHandler(testCase *testing.T) {
 	}
 
 	testCase.Run("/issues - 200 - issue created", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		callCreateProjectAndSprint(testRouter)
-
-		expectedResponse := models.CreateResponse{
-			Id: "1",
-		}
-
-		expectedJsonReponse, _ := json.Marshal(expectedResponse)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
 
 		requestBody, err := json.Marshal(inputIssue)
 		if err != nil {
@@ -647,15 +970,22 @@ func TestCreateIssueHandler(testCase *testing.T) {
 		bodyReader := bytes.NewReader(requestBody)
 
 		responseRecorder := httptest.NewRecorder()
-		request, requestError := http.NewRequest(http.MethodPost, "/v1/projects/1/sprints/1/issues", bodyReader)
+		request, requestError := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId), bodyReader)
 		require.NoError(t, requestError, "Error creating the /sprints request")
 
 		testRouter.ServeHTTP(responseRecorder, request)
 		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusOK, statusCode, "The response statusCode should be 200")
 
-		var foundIssue models.Issue
-		database.First(&foundIssue)
+		foundIssues, _ := stores.Issues.ListBySprint(context.Background(), projectId, sprintId, models.Page{})
+		require.Equal(t, 1, len(foundIssues))
+		foundIssue := foundIssues[0]
+
+		expectedResponse := models.CreateResponse{
+			Id: fmt.Sprint(foundIssue.ID),
+		}
+
+		expectedJsonReponse, _ := json.Marshal(expectedResponse)
 
 		rawBody := responseRecorder.Result().Body
 		body, readBodyError := ioutil.ReadAll(rawBody)
@@ -664,49 +994,71 @@ func TestCreateIssueHandler(testCase *testing.T) {
 		require.Equal(t, expectedTitle, foundIssue.Title)
 		require.Equal(t, expectedDescription, foundIssue.Description)
 	})
+
+	testCase.Run("/issues - 400 - request does not match the specification", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+
+		for requestBody, expectedMessage := range map[string]string{
+			`{}`: "body.title: is required\nbody.type: is required",
+			`{"type":"Task","title":"","severity":1}`:          "body.severity: is not a known field\nbody.title: must not be empty",
+			`{"type":"Task","title":"Title","status":3}`:       "body.status: must be a string, got number",
+			fmt.Sprintf(`{"type":"Task","title":"%0256d"}`, 0): "body.title: must be at most 255 characters long",
+		} {
+			responseRecorder := httptest.NewRecorder()
+			request, requestError := http.NewRequest(http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId), strings.NewReader(requestBody))
+			require.NoError(t, requestError, "Error creating the /issues request")
+
+			testRouter.ServeHTTP(responseRecorder, request)
+			require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode, requestBody)
+
+			expectedJsonReponse, _ := json.Marshal(models.ErrorResponse{ErrorMessage: expectedMessage, ErrorCode: 400})
+			require.Equal(t, fmt.Sprintf("%s\n", string(expectedJsonReponse)), responseRecorder.Body.String(), requestBody)
+		}
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(http.MethodPost, "/v1/projects/first/sprints/1/issues", strings.NewReader(`{"type":"Task","title":"Title"}`))
+		require.NoError(t, requestError, "Error creating the /issues request")
+		testRouter.ServeHTTP(responseRecorder, request)
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+		require.Contains(t, responseRecorder.Body.String(), "path.projectId: must be an integer")
+
+		foundIssues, _ := stores.Issues.ListBySprint(context.Background(), projectId, sprintId, models.Page{})
+		require.Equal(t, 0, len(foundIssues), "No issue should be created")
+	})
 }
 
 func TestGetIssuesHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
+	testCase.Parallel()
 
 	expectedTitle := "Task title"
 	expectedDescription := "Task description"
-	projectId := 1
-	sprintId := 1
-	inputIssue1 := models.Issue{
-		Type:        "Task",
-		ProjectID:   projectId,
-		SprintID:    sprintId,
-		Title:       expectedTitle,
-		Description: expectedDescription,
-		Status:      "To Do",
-		Assignee:    "Assignee",
-	}
-	inputIssue2 := models.Issue{
-		Type:        "Task 2",
-		ProjectID:   projectId,
-		SprintID:    sprintId,
-		Title:       expectedTitle,
-		Description: expectedDescription,
-		Status:      "To Do",
-		Assignee:    "Assignee",
-	}
+
 	testCase.Run("/issues get - 200 - issues returned", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		callCreateProjectAndSprint(testRouter)
-		database.Create(&inputIssue1)
-		database.Create(&inputIssue2)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		inputIssue1 := models.Issue{
+			Type:        "Task",
+			ProjectID:   projectId,
+			SprintID:    sprintId,
+			Title:       expectedTitle,
+			Description: expectedDescription,
+			Status:      "To Do",
+			Assignee:    "Assignee",
+		}
+		inputIssue2 := models.Issue{
+			Type:        "Task 2",
+			ProjectID:   projectId,
+			SprintID:    sprintId,
+			Title:       expectedTitle,
+			Description: expectedDescription,
+			Status:      "To Do",
+			Assignee:    "Assignee",
+		}
+		stores.Issues.Create(context.Background(), &inputIssue1)
+		stores.Issues.Create(context.Background(), &inputIssue2)
 
 		expectedResponse := []models.GetIssueResponse{
 			inputIssue1.GetIssueResponseFromIssue(),
@@ -736,43 +1088,31 @@ func TestGetIssuesHandler(testCase *testing.T) {
 }
 
 func TestPatchIssueHandler(testCase *testing.T) {
-	config, err := internal.GetConfig("../../../.env")
-	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
-		return
-	}
-
-	testRouter := NewRouter(config)
-	database, err := internal.ConnectDatabase(config)
-	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return
-	}
+	testCase.Parallel()
 
 	expectedTitle := "Task title"
 	expectedDescription := "Task description"
-	projectId := 1
-	sprintId := 1
-	inputIssue := models.Issue{
-		Type:        "Task",
-		ProjectID:   projectId,
-		SprintID:    sprintId,
-		Title:       expectedTitle,
-		Description: expectedDescription,
-		Status:      "To Do",
-		Assignee:    "Assignee",
-	}
 
 	testCase.Run("/issues patch - 204 - issue is patched", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		callCreateProjectAndSprint(testRouter)
-		database.Create(&inputIssue)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		inputIssue := models.Issue{
+			Type:        "Task",
+			ProjectID:   projectId,
+			SprintID:    sprintId,
+			Title:       expectedTitle,
+			Description: expectedDescription,
+			Status:      "To Do",
+			Assignee:    "Assignee",
+		}
+		stores.Issues.Create(context.Background(), &inputIssue)
 		expectedStatus := "Completed"
-		inputIssue.Status = expectedStatus
 
 		patchIssue := models.PatchIssueRequest{
 			ID:     inputIssue.ID,
 			Status: expectedStatus,
+			Epic:   "Login",
 		}
 		requestBody, err := json.Marshal(patchIssue)
 		if err != nil {
@@ -793,23 +1133,23 @@ func TestPatchIssueHandler(testCase *testing.T) {
 		statusCode := responseRecorder.Result().StatusCode
 		require.Equal(t, http.StatusNoContent, statusCode, "The response statusCode should be 204")
 
-		var issueSprint models.Issue
-		database.First(&issueSprint)
+		issueSprint, _ := stores.Issues.Get(context.Background(), projectId, sprintId, inputIssue.ID)
 		require.Equal(t, expectedStatus, issueSprint.Status)
+		require.Equal(t, "Login", issueSprint.Epic)
 		require.Equal(t, expectedTitle, issueSprint.Title)
 		require.Equal(t, expectedDescription, issueSprint.Description)
 	})
 
 	testCase.Run("/issues patch - 404 - issue does not exists", func(t *testing.T) {
-		internal.SetupAndResetDatabase(database)
-		callCreateProjectAndSprint(testRouter)
-		database.Create(&inputIssue)
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		issueId := internal.CreateTestIssue(stores, projectId, sprintId)
 		expectedStatus := "Completed"
-		inputIssue.Status = expectedStatus
 		wrongIssueId := 99999
 
 		patchIssue := models.PatchIssueRequest{
-			ID:     inputIssue.ID,
+			ID:     issueId,
 			Status: expectedStatus,
 		}
 		requestBody, err := json.Marshal(patchIssue)
@@ -832,3 +1172,409 @@ func TestPatchIssueHandler(testCase *testing.T) {
 		require.Equal(t, http.StatusNotFound, statusCode, "The response statusCode should be 404")
 	})
 }
+
+func TestRankIssueHandler(testCase *testing.T) {
+	testCase.Parallel()
+
+	callRankAPI := func(t *testing.T, testRouter *negroni.Negroni, path string, body string) int {
+		request, requestError := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		require.NoError(t, requestError, "Error creating the %s request", path)
+		responseRecorder := httptest.NewRecorder()
+		testRouter.ServeHTTP(responseRecorder, request)
+		return responseRecorder.Result().StatusCode
+	}
+
+	testCase.Run("/rank - 204", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		firstIssueId := internal.CreateTestIssue(stores, projectId, sprintId)
+		secondIssueId := internal.CreateTestIssue(stores, projectId, sprintId)
+
+		statusCode := callRankAPI(t, testRouter, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d/rank", projectId, sprintId, secondIssueId),
+			fmt.Sprintf(`{"beforeIssueId": %d}`, firstIssueId))
+		require.Equal(t, http.StatusNoContent, statusCode)
+
+		responseRecorder := httptest.NewRecorder()
+		request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId), nil)
+		testRouter.ServeHTTP(responseRecorder, request)
+		var issues []models.GetIssueResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&issues))
+		require.Equal(t, []uint{secondIssueId, firstIssueId}, []uint{issues[0].ID, issues[1].ID})
+		require.Less(t, issues[0].Rank, issues[1].Rank)
+	})
+
+	testCase.Run("/backlog/rank - 404 - neighbour in a sprint", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		sprintIssueId := internal.CreateTestIssue(stores, projectId, sprintId)
+		backlogIssueId := internal.CreateTestIssue(stores, projectId, 0)
+
+		statusCode := callRankAPI(t, testRouter, fmt.Sprintf("/v1/projects/%d/backlog/issues/%d/rank", projectId, backlogIssueId),
+			fmt.Sprintf(`{"afterIssueId": %d}`, sprintIssueId))
+		require.Equal(t, http.StatusNotFound, statusCode)
+	})
+}
+
+func TestGetIssueByIdHandler(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("/issues/{issueId} get - 200 - issue returned", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(
+			http.MethodGet,
+			fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", projectId, sprintId, issueId),
+			nil,
+		)
+		require.NoError(t, requestError, "Error creating the /issues request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		statusCode := responseRecorder.Result().StatusCode
+		require.Equal(t, http.StatusOK, statusCode, "The response statusCode should be 200")
+
+		var foundIssue models.GetIssueResponse
+		json.NewDecoder(responseRecorder.Result().Body).Decode(&foundIssue)
+		require.Equal(t, issueId, foundIssue.ID)
+		require.Equal(t, int(sprintId), foundIssue.SprintID)
+	})
+
+	testCase.Run("/issues/{issueId} get - 404 - issue belongs to another project", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherProjectId := internal.CreateTestProject(stores)
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(
+			http.MethodGet,
+			fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", otherProjectId, sprintId, issueId),
+			nil,
+		)
+		require.NoError(t, requestError, "Error creating the /issues request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		statusCode := responseRecorder.Result().StatusCode
+		require.Equal(t, http.StatusNotFound, statusCode, "The response statusCode should be 404")
+	})
+}
+
+func TestNestedOwnershipHandlers(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("/issues patch - 404 - path sprint does not own the issue", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherSprintId := internal.CreateTestSprint(stores, "other", int(projectId))
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		requestBody, _ := json.Marshal(models.PatchIssueRequest{Status: "Completed"})
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(
+			http.MethodPatch,
+			fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", projectId, otherSprintId, issueId),
+			bytes.NewReader(requestBody),
+		)
+		require.NoError(t, requestError, "Error creating the /issues request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		statusCode := responseRecorder.Result().StatusCode
+		require.Equal(t, http.StatusNotFound, statusCode, "The response statusCode should be 404")
+
+		foundIssue, _ := stores.Issues.Get(context.Background(), int(projectId), int(sprintId), issueId)
+		require.Equal(t, "To Do", foundIssue.Status)
+	})
+
+	testCase.Run("/issues patch - 400 - body tries to move the issue", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherSprintId := internal.CreateTestSprint(stores, "other", int(projectId))
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		requestBody, _ := json.Marshal(models.PatchIssueRequest{SprintID: int(otherSprintId)})
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(
+			http.MethodPatch,
+			fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", projectId, sprintId, issueId),
+			bytes.NewReader(requestBody),
+		)
+		require.NoError(t, requestError, "Error creating the /issues request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		statusCode := responseRecorder.Result().StatusCode
+		require.Equal(t, http.StatusBadRequest, statusCode, "The response statusCode should be 400")
+	})
+
+	testCase.Run("/sprints patch - 404 - sprint belongs to another project", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherProjectId := internal.CreateTestProject(stores)
+
+		requestBody, _ := json.Marshal(models.PatchSprintRequest{Number: "2"})
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(
+			http.MethodPatch,
+			fmt.Sprintf("/v1/projects/%d/sprints/%d", otherProjectId, sprintId),
+			bytes.NewReader(requestBody),
+		)
+		require.NoError(t, requestError, "Error creating the /sprints request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		statusCode := responseRecorder.Result().StatusCode
+		require.Equal(t, http.StatusNotFound, statusCode, "The response statusCode should be 404")
+
+		foundSprint, _ := stores.Sprints.Get(context.Background(), int(projectId), int(sprintId))
+		require.Equal(t, "12345", foundSprint.Number)
+	})
+
+	testCase.Run("/issues/{issueId}/move - 204 - issue moved", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := internal.CreateProjectAndSprint(stores)
+		otherSprintId := internal.CreateTestSprint(stores, "other", int(projectId))
+		issueId := internal.CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		requestBody, _ := json.Marshal(models.MoveIssueRequest{
+			ProjectID: int(projectId),
+			SprintID:  int(otherSprintId),
+		})
+		responseRecorder := httptest.NewRecorder()
+		request, requestError := http.NewRequest(
+			http.MethodPost,
+			fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d/move", projectId, sprintId, issueId),
+			bytes.NewReader(requestBody),
+		)
+		require.NoError(t, requestError, "Error creating the /move request")
+
+		testRouter.ServeHTTP(responseRecorder, request)
+		statusCode := responseRecorder.Result().StatusCode
+		require.Equal(t, http.StatusNoContent, statusCode, "The response statusCode should be 204")
+
+		_, err := stores.Issues.Get(context.Background(), int(projectId), int(otherSprintId), issueId)
+		require.Equal(t, nil, err)
+	})
+}
+
+func TestSprintLifecycleHandlers(testCase *testing.T) {
+	testCase.Parallel()
+
+	callSprintAPI := func(testRouter *negroni.Negroni, method string, path string, body string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(method, path, strings.NewReader(body))
+		responseRecorder := httptest.NewRecorder()
+		testRouter.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	testCase.Run("/sprints start and complete - 200 - unfinished issues carried over", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		nextSprintId := internal.CreateTestSprint(stores, "next", projectId)
+		issueId := internal.CreateTestIssue(stores, projectId, sprintId)
+		sprintPath := fmt.Sprintf("/v1/projects/%d/sprints/%d", projectId, sprintId)
+
+		responseRecorder := callSprintAPI(testRouter, http.MethodGet, sprintPath+"/completion", "")
+		require.Equal(t, http.StatusNotFound, responseRecorder.Result().StatusCode)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodPost, sprintPath+"/start", "{}")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var sprint models.GetSprintResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&sprint))
+		require.NotNil(t, sprint.StartedAt)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodPost, sprintPath+"/start", "{}")
+		require.Equal(t, http.StatusConflict, responseRecorder.Result().StatusCode)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodPost, sprintPath+"/complete", fmt.Sprintf(`{"nextSprintId": %d}`, nextSprintId))
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var completion models.SprintCompletionResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&completion))
+		require.Equal(t, int(nextSprintId), completion.NextSprintID)
+		require.Equal(t, 1, len(completion.Committed))
+		require.Equal(t, 1, len(completion.CarriedOver))
+
+		movedIssue, err := stores.Issues.Get(context.Background(), projectId, int(nextSprintId), issueId)
+		require.NoError(t, err)
+		require.Equal(t, "Issue title", movedIssue.Title)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, sprintPath+"/completion", "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+	})
+
+	testCase.Run("/sprints complete - 200 - without body the issues go to the backlog", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		issueId := internal.CreateTestIssue(stores, projectId, sprintId)
+		sprintPath := fmt.Sprintf("/v1/projects/%d/sprints/%d", projectId, sprintId)
+
+		responseRecorder := callSprintAPI(testRouter, http.MethodPost, sprintPath+"/start", "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		responseRecorder = callSprintAPI(testRouter, http.MethodPost, sprintPath+"/complete", "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode, responseRecorder.Body.String())
+		var completion models.SprintCompletionResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&completion))
+		require.Equal(t, 0, completion.NextSprintID)
+		require.Equal(t, uint(issueId), completion.CarriedOver[0].ID)
+
+		_, err := stores.Issues.Get(context.Background(), projectId, 0, issueId)
+		require.NoError(t, err)
+	})
+
+	testCase.Run("/sprints patch - 400 - completed is no longer patched", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := callSprintAPI(testRouter, http.MethodPatch, fmt.Sprintf("/v1/projects/%d/sprints/%d", projectId, sprintId),
+			fmt.Sprintf(`{"id": %d, "completed": true}`, sprintId))
+
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+	})
+
+	testCase.Run("/sprints - 400 - a sprint is not created completed", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := callSprintAPI(testRouter, http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints", projectId),
+			`{"number": "2", "startDate": "2022-10-01T00:00:00Z", "endDate": "2022-10-14T00:00:00Z", "completed": true}`)
+
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+		require.Contains(t, responseRecorder.Body.String(), "body.completed: is not a known field")
+	})
+
+	testCase.Run("/backlog - 200 - issue created in the backlog and moved to a sprint", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		backlogPath := fmt.Sprintf("/v1/projects/%d/backlog/issues", projectId)
+
+		responseRecorder := callSprintAPI(testRouter, http.MethodPost, backlogPath, `{"type": "Task", "title": "Backlog task"}`)
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		issueId := getCreatedId(responseRecorder)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, backlogPath, "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var issues []models.GetIssueResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&issues))
+		require.Equal(t, 1, len(issues))
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodPost, fmt.Sprintf("%s/%d/move", backlogPath, issueId),
+			fmt.Sprintf(`{"projectId": %d, "sprintId": %d}`, projectId, sprintId))
+		require.Equal(t, http.StatusNoContent, responseRecorder.Result().StatusCode)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", projectId, sprintId, issueId), "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d/move", projectId, sprintId, issueId),
+			fmt.Sprintf(`{"projectId": %d, "sprintId": 0}`, projectId))
+		require.Equal(t, http.StatusNoContent, responseRecorder.Result().StatusCode, "the sprint 0 is the backlog")
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, backlogPath, "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&issues))
+		require.Equal(t, uint(issueId), issues[0].ID)
+	})
+
+	testCase.Run("/sprints/{sprintId}/burndown - 200 - burndown in json, csv and svg", func(t *testing.T) {
+		t.Parallel()
+		testRouter, stores := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		internal.CreateTestIssue(stores, projectId, sprintId)
+		sprintPath := fmt.Sprintf("/v1/projects/%d/sprints/%d", projectId, sprintId)
+		responseRecorder := callSprintAPI(testRouter, http.MethodPost, sprintPath+"/start", "{}")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		burndownPath := sprintPath + "/burndown"
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, burndownPath+"?unit=issues", "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var burndown models.SprintBurndownResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&burndown))
+		require.Equal(t, 1, burndown.CommittedWork)
+		require.Equal(t, 1, *burndown.Days[0].Remaining)
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, burndownPath+"?format=csv", "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		require.Equal(t, "text/csv; charset=UTF-8", responseRecorder.Result().Header.Get("Content-Type"))
+		require.True(t, strings.HasPrefix(responseRecorder.Body.String(), "date,ideal,remaining,completed,scope\n"))
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, burndownPath+"?format=svg", "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		require.Equal(t, "image/svg+xml", responseRecorder.Result().Header.Get("Content-Type"))
+
+		responseRecorder = callSprintAPI(testRouter, http.MethodGet, burndownPath+"?format=png", "")
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+	})
+}
+
+func TestSlaHandler(testCase *testing.T) {
+	testCase.Parallel()
+
+	callSlaAPI := func(t *testing.T, testRouter *negroni.Negroni, method string, path string, body string) *httptest.ResponseRecorder {
+		request, requestError := http.NewRequest(method, path, strings.NewReader(body))
+		require.NoError(t, requestError, "Error creating the %s request", path)
+		responseRecorder := httptest.NewRecorder()
+		testRouter.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	testCase.Run("/sla - 200", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, sprintId := callCreateProjectAndSprint(testRouter)
+		responseRecorder := callSlaAPI(t, testRouter, http.MethodPost, fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId),
+			`{"type": "Bug", "title": "Urgent", "priority": "P0"}`)
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		issueId := getCreatedId(responseRecorder)
+
+		responseRecorder = callSlaAPI(t, testRouter, http.MethodPut, fmt.Sprintf("/v1/projects/%d/sla", projectId),
+			`{"pausedStatuses": ["Blocked"], "targets": [{"priority": "P0", "responseMinutes": 15, "resolutionMinutes": 240}]}`)
+		require.Equal(t, http.StatusNoContent, responseRecorder.Result().StatusCode, responseRecorder.Body.String())
+
+		responseRecorder = callSlaAPI(t, testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/sla", projectId), "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var policy models.SlaPolicyResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&policy))
+		require.Equal(t, []string{"Blocked"}, policy.PausedStatuses)
+		require.Equal(t, 240, *policy.Targets[0].ResolutionMinutes)
+
+		responseRecorder = callSlaAPI(t, testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/sla/issues?within=30", projectId), "")
+		require.Equal(t, http.StatusOK, responseRecorder.Result().StatusCode)
+		var slaIssues models.SlaIssuesResponse
+		require.NoError(t, json.NewDecoder(responseRecorder.Result().Body).Decode(&slaIssues))
+		require.Equal(t, 30, slaIssues.WithinMinutes)
+		require.Equal(t, 1, len(slaIssues.Issues))
+		require.Equal(t, uint(issueId), slaIssues.Issues[0].IssueID)
+		require.Equal(t, "P0", slaIssues.Issues[0].Priority)
+		require.Equal(t, models.SLA_STATE_RUNNING, slaIssues.Issues[0].Response.State)
+	})
+
+	testCase.Run("/sla - 404 - project without SLA policy", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := callSlaAPI(t, testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/sla/issues", projectId), "")
+		require.Equal(t, http.StatusNotFound, responseRecorder.Result().StatusCode)
+	})
+
+	testCase.Run("/sla - 400 - invalid priority and within", func(t *testing.T) {
+		t.Parallel()
+		testRouter, _ := newTestRouter(t)
+		projectId, _ := callCreateProjectAndSprint(testRouter)
+
+		responseRecorder := callSlaAPI(t, testRouter, http.MethodPut, fmt.Sprintf("/v1/projects/%d/sla", projectId), `{"targets": [{"priority": "P9", "responseMinutes": 15}]}`)
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+		responseRecorder = callSlaAPI(t, testRouter, http.MethodGet, fmt.Sprintf("/v1/projects/%d/sla/issues?within=-1", projectId), "")
+		require.Equal(t, http.StatusBadRequest, responseRecorder.Result().StatusCode)
+	})
+}
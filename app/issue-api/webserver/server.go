@@ -0,0 +1,65 @@
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"issue-service/app/issue-api/cfg"
+	"issue-service/app/issue-api/routes"
+	"net"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// NewServer returns an http.Server listening on HTTP_PORT with the timeouts of the configuration
+func NewServer(config cfg.EnvConfiguration, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%s", config.HTTP_PORT),
+		Handler:           handler,
+		ReadTimeout:       config.HTTP_READ_TIMEOUT,
+		ReadHeaderTimeout: config.HTTP_READ_HEADER_TIMEOUT,
+		WriteTimeout:      config.HTTP_WRITE_TIMEOUT,
+		IdleTimeout:       config.HTTP_IDLE_TIMEOUT,
+	}
+}
+
+// RunServer serves on listener until ctx is done, then drains the server:
+// the readiness probe fails for shutdownDelay, so that the load balancers stop sending requests,
+// and the requests in flight are given shutdownTimeout to complete
+func RunServer(
+	ctx context.Context,
+	server *http.Server,
+	listener net.Listener,
+	statusRouter *routes.StatusRouter,
+	shutdownDelay time.Duration,
+	shutdownTimeout time.Duration,
+) error {
+	serveError := make(chan error, 1)
+	go func() {
+		serveError <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveError:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info(fmt.Sprintf("Shutting down, the server stops accepting connections in %s", shutdownDelay))
+	statusRouter.StartDraining()
+	time.Sleep(shutdownDelay)
+
+	shutdownContext, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownContext); err != nil {
+		return fmt.Errorf("draining the requests in flight: %w", err)
+	}
+
+	if err := <-serveError; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Info("Server stopped")
+	return nil
+}
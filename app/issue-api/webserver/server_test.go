@@ -0,0 +1,91 @@
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"issue-service/app/issue-api/cfg"
+	"issue-service/app/issue-api/routes"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewServer(t *testing.T) {
+	t.Parallel()
+	server := NewServer(cfg.EnvConfiguration{
+		HTTP_PORT:          "8080",
+		HTTP_READ_TIMEOUT:  time.Second,
+		HTTP_WRITE_TIMEOUT: 2 * time.Second,
+		HTTP_IDLE_TIMEOUT:  3 * time.Second,
+	}, http.NotFoundHandler())
+
+	require.Equal(t, ":8080", server.Addr)
+	require.Equal(t, time.Second, server.ReadTimeout)
+	require.Equal(t, 2*time.Second, server.WriteTimeout)
+	require.Equal(t, 3*time.Second, server.IdleTimeout)
+}
+
+func TestRunServer(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("drains the requests in flight", func(t *testing.T) {
+		statusRouter := routes.NewStatusRouter(time.Second)
+		requestStarted := make(chan struct{})
+		releaseRequest := make(chan struct{})
+		mux := http.NewServeMux()
+		mux.Handle("/-/", newStatusTestRouter(t, statusRouter))
+		mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			close(requestStarted)
+			<-releaseRequest
+			w.Write([]byte("done"))
+		})
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		baseUrl := fmt.Sprintf("http://%s", listener.Addr().String())
+		server := NewServer(cfg.EnvConfiguration{}, mux)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		runError := make(chan error, 1)
+		go func() {
+			runError <- RunServer(ctx, server, listener, statusRouter, 200*time.Millisecond, 5*time.Second)
+		}()
+
+		slowResponse := make(chan string, 1)
+		go func() {
+			response, err := http.Get(baseUrl + "/slow")
+			if err != nil {
+				slowResponse <- err.Error()
+				return
+			}
+			defer response.Body.Close()
+			body, _ := ioutil.ReadAll(response.Body)
+			slowResponse <- string(body)
+		}()
+		<-requestStarted
+		cancel()
+
+		require.Eventually(t, statusRouter.IsDraining, time.Second, 10*time.Millisecond)
+		readiness, err := http.Get(baseUrl + "/-/ready")
+		require.NoError(t, err)
+		readiness.Body.Close()
+		require.Equal(t, http.StatusServiceUnavailable, readiness.StatusCode)
+
+		close(releaseRequest)
+		require.Equal(t, "done", <-slowResponse)
+		require.NoError(t, <-runError)
+	})
+
+	testCase.Run("returns the listener errors", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		listener.Close()
+
+		err = RunServer(context.Background(), NewServer(cfg.EnvConfiguration{}, http.NotFoundHandler()), listener, routes.NewStatusRouter(time.Second), 0, time.Second)
+		require.Error(t, err)
+	})
+}
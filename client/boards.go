@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// SaveBoard creates the board of a project, or replaces its columns and its swimlanes
+func (client *Client) SaveBoard(ctx context.Context, projectId int, board models.SaveBoardRequest) error {
+	return client.do(ctx, request{
+		method:     http.MethodPut,
+		path:       fmt.Sprintf("/v1/projects/%d/board", projectId),
+		body:       board,
+		idempotent: true,
+	}, nil)
+}
+
+// GetBoard returns the board of a project with its issues, ErrNotFound when the project has no board
+func (client *Client) GetBoard(ctx context.Context, projectId int) (models.BoardResponse, error) {
+	var board models.BoardResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/board", projectId),
+		idempotent: true,
+	}, &board)
+	return board, err
+}
+
+// MoveBoardIssue moves an issue to a column of the board, ErrConflict when the column reached its WIP limit
+func (client *Client) MoveBoardIssue(ctx context.Context, projectId int, issueId int, move models.MoveBoardIssueRequest) error {
+	return client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/board/issues/%d/move", projectId, issueId),
+		body:   move,
+	}, nil)
+}
@@ -0,0 +1,253 @@
+// Package client calls the issue API over HTTP.
+//
+// Every method takes a context, the requests failing on a transient error are retried
+// when repeating them cannot apply a change twice, and the API errors are returned as *Error.
+// The POST requests carry an Idempotency-Key header, the same for all the retries of a call,
+// so that the server applies them once even when a response was lost.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_TIMEOUT     = 30 * time.Second
+	DEFAULT_MAX_RETRIES = 3
+	DEFAULT_RETRY_DELAY = 100 * time.Millisecond
+	// MAX_RETRY_DELAY bounds the backoff and the Retry-After header of the server
+	MAX_RETRY_DELAY = 5 * time.Second
+)
+
+// Client is safe for concurrent use
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	maxRetries int
+	retryDelay time.Duration
+	userAgent  string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with httpClient, by default a client with a DEFAULT_TIMEOUT timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = httpClient
+	}
+}
+
+// WithRetries retries a failed request at most maxRetries times, waiting delay before the first retry and doubling it after
+func WithRetries(maxRetries int, delay time.Duration) Option {
+	return func(client *Client) {
+		client.maxRetries = maxRetries
+		client.retryDelay = delay
+	}
+}
+
+// WithUserAgent sets the User-Agent header, so that the access log of the API tells the callers apart
+func WithUserAgent(userAgent string) Option {
+	return func(client *Client) {
+		client.userAgent = userAgent
+	}
+}
+
+// NewClient calls the API served at baseURL, for example http://issue-service:8080
+func NewClient(baseURL string, options ...Option) (*Client, error) {
+	parsedURL, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL %q: %w", baseURL, err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: the scheme must be http or https", baseURL)
+	}
+
+	client := &Client{
+		baseURL:    parsedURL,
+		httpClient: &http.Client{Timeout: DEFAULT_TIMEOUT},
+		maxRetries: DEFAULT_MAX_RETRIES,
+		retryDelay: DEFAULT_RETRY_DELAY,
+		userAgent:  "issue-service-client",
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client, nil
+}
+
+// request describes a call, idempotent is true when repeating it cannot apply a change twice.
+// The body of a response answered with decodedErrorStatus is decoded like a success, and returned with its *Error.
+// idempotencyKey is set by do on the POST requests that are not idempotent.
+type request struct {
+	method             string
+	path               string
+	query              url.Values
+	body               interface{}
+	idempotent         bool
+	decodedErrorStatus int
+	idempotencyKey     string
+}
+
+// do sends request, retrying the transient failures, and decodes the response body into result when it is not nil
+func (client *Client) do(ctx context.Context, request request, result interface{}) error {
+	var body []byte
+	if request.body != nil {
+		encoded, err := json.Marshal(request.body)
+		if err != nil {
+			return fmt.Errorf("encoding the request body: %w", err)
+		}
+		body = encoded
+	}
+	if request.method == http.MethodPost && !request.idempotent {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return fmt.Errorf("generating the idempotency key: %w", err)
+		}
+		request.idempotencyKey = key
+	}
+
+	delay := client.retryDelay
+	for attempt := 0; ; attempt++ {
+		response, err := client.send(ctx, request, body)
+		retry, retryAfter := client.shouldRetry(request, response, err)
+		if !retry || attempt >= client.maxRetries {
+			if err != nil {
+				return err
+			}
+			return readResponse(response, request.decodedErrorStatus, result)
+		}
+		if response != nil {
+			io.Copy(ioutil.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if wait > MAX_RETRY_DELAY {
+			wait = MAX_RETRY_DELAY
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+func (client *Client) send(ctx context.Context, request request, body []byte) (*http.Response, error) {
+	requestURL := *client.baseURL
+	requestURL.Path += request.path
+	requestURL.RawQuery = request.query.Encode()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	httpRequest, err := http.NewRequestWithContext(ctx, request.method, requestURL.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+	httpRequest.Header.Set("Accept", "application/json")
+	httpRequest.Header.Set("User-Agent", client.userAgent)
+	if request.idempotencyKey != "" {
+		httpRequest.Header.Set("Idempotency-Key", request.idempotencyKey)
+	}
+	return client.httpClient.Do(httpRequest)
+}
+
+// newIdempotencyKey returns 128 random bits in hexadecimal
+func newIdempotencyKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// shouldRetry retries the idempotent requests, and the requests with an idempotency key, on the network errors
+// and the gateway errors. The other requests are retried only when the server surely did not process them:
+// the connection was refused, or the server answered 429 or 503.
+// The server answers 409 with a Retry-After header while it processes the first request of an idempotency key.
+func (client *Client) shouldRetry(request request, response *http.Response, err error) (bool, time.Duration) {
+	retryable := request.idempotent || request.idempotencyKey != ""
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false, 0
+		}
+		var opError *net.OpError
+		if errors.As(err, &opError) && opError.Op == "dial" {
+			return true, 0
+		}
+		return retryable, 0
+	}
+
+	switch response.StatusCode {
+	case request.decodedErrorStatus:
+		return false, 0
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true, getRetryAfter(response)
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return retryable, 0
+	case http.StatusConflict:
+		return request.idempotencyKey != "" && response.Header.Get("Retry-After") != "", getRetryAfter(response)
+	}
+	return false, 0
+}
+
+// getRetryAfter reads the Retry-After header given in seconds, 0 when it is missing
+func getRetryAfter(response *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(response.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// readResponse decodes the body into result, a *[]byte receives the raw body
+func readResponse(response *http.Response, decodedErrorStatus int, result interface{}) error {
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return fmt.Errorf("reading the response body: %w", err)
+	}
+
+	var apiError error
+	if response.StatusCode >= 300 {
+		if response.StatusCode != decodedErrorStatus {
+			return newError(response.StatusCode, body)
+		}
+		apiError = &Error{StatusCode: response.StatusCode, Message: http.StatusText(response.StatusCode)}
+	}
+	if result == nil {
+		return apiError
+	}
+	if raw, ok := result.(*[]byte); ok {
+		*raw = body
+		return apiError
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("decoding the response body: %w", err)
+	}
+	return apiError
+}
@@ -0,0 +1,458 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"issue-service/app/issue-api/routes"
+	"issue-service/app/issue-api/routes/models"
+	"issue-service/app/issue-api/webserver"
+	"issue-service/internal"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler records the method, the path and the Idempotency-Key of every request,
+// and answers the first failures itself. The lost responses are served, then replaced by a 502.
+type recordingHandler struct {
+	inner           http.Handler
+	mutex           sync.Mutex
+	requests        []string
+	idempotencyKeys []string
+	failures        []int
+	lostResponses   int
+}
+
+func (handler *recordingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler.mutex.Lock()
+	handler.requests = append(handler.requests, fmt.Sprintf("%s %s", r.Method, r.URL.Path))
+	handler.idempotencyKeys = append(handler.idempotencyKeys, r.Header.Get("Idempotency-Key"))
+	var failure int
+	if len(handler.failures) > 0 {
+		failure, handler.failures = handler.failures[0], handler.failures[1:]
+	}
+	lost := handler.lostResponses > 0
+	if lost {
+		handler.lostResponses--
+	}
+	handler.mutex.Unlock()
+
+	if failure != 0 {
+		w.WriteHeader(failure)
+		return
+	}
+	if lost {
+		handler.inner.ServeHTTP(httptest.NewRecorder(), r)
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+	handler.inner.ServeHTTP(w, r)
+}
+
+func (handler *recordingHandler) failNext(statusCodes ...int) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	handler.failures = statusCodes
+	handler.requests = nil
+	handler.idempotencyKeys = nil
+}
+
+func (handler *recordingHandler) loseNext(count int) {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	handler.lostResponses = count
+	handler.requests = nil
+	handler.idempotencyKeys = nil
+}
+
+func (handler *recordingHandler) getIdempotencyKeys() []string {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	return append([]string{}, handler.idempotencyKeys...)
+}
+
+func (handler *recordingHandler) getRequests() []string {
+	handler.mutex.Lock()
+	defer handler.mutex.Unlock()
+	return append([]string{}, handler.requests...)
+}
+
+func newTestServer(t *testing.T, statusRouter *routes.StatusRouter) (*Client, *recordingHandler) {
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	stores := internal.NewMemoryStores()
+	router, err := webserver.NewRouter(
+		stores,
+		statusRouter,
+		internal.NewMetrics(),
+		internal.NewAccessLogger(logger, 100),
+		func(ctx context.Context, routeName string, problems []string) {
+			t.Errorf("%s response not matching the specification:\n%s", routeName, strings.Join(problems, "\n"))
+		},
+	)
+	require.NoError(t, err)
+	handler := &recordingHandler{inner: router}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	apiClient, err := NewClient(server.URL, WithRetries(2, time.Millisecond))
+	require.NoError(t, err)
+	return apiClient, handler
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	apiClient, handler := newTestServer(t, routes.NewStatusRouter(time.Second))
+
+	health, err := apiClient.Health(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.STATUS_OK, health.Status)
+	ready, err := apiClient.Ready(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models.STATUS_OK, ready.Status)
+
+	projectId, err := apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "client", Type: "scrum"})
+	require.NoError(t, err)
+	projects, err := apiClient.ListProjects(ctx, models.Page{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(projects))
+	require.Equal(t, uint(projectId), projects[0].ID)
+
+	firstDay := time.Date(2022, 10, 3, 0, 0, 0, 0, time.UTC)
+	sprintId, err := apiClient.CreateSprint(ctx, projectId, models.CreateSprintRequest{Number: "1", StartDate: firstDay, EndDate: firstDay.AddDate(0, 0, 11)})
+	require.NoError(t, err)
+	require.NoError(t, apiClient.PatchSprint(ctx, projectId, sprintId, models.PatchSprintRequest{MaxIssuePerSprint: 10}))
+	sprints, err := apiClient.Sprints(projectId, 0).All(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(sprints))
+	require.Equal(t, 10, sprints[0].MaxIssuePerSprint)
+
+	issueIds := []int{}
+	for index := 0; index < 5; index++ {
+		issueId, err := apiClient.CreateIssue(ctx, projectId, sprintId, models.CreateIssueRequest{
+			Type:  "Task",
+			Title: fmt.Sprintf("Issue %d", index),
+		})
+		require.NoError(t, err)
+		issueIds = append(issueIds, issueId)
+	}
+	firstPage, err := apiClient.ListIssues(ctx, projectId, sprintId, models.Page{Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(firstPage))
+
+	handler.failNext()
+	issues := apiClient.Issues(projectId, sprintId, 2)
+	iteratedIds := []int{}
+	for issues.Next(ctx) {
+		iteratedIds = append(iteratedIds, int(issues.Value().ID))
+	}
+	require.NoError(t, issues.Err())
+	require.Equal(t, issueIds, iteratedIds)
+	require.Equal(t, 3, len(handler.getRequests()), "The iterator should fetch 3 pages of 2 issues")
+
+	require.NoError(t, apiClient.PatchIssue(ctx, projectId, sprintId, issueIds[0], models.PatchIssueRequest{Status: "Done"}))
+	issue, err := apiClient.GetIssue(ctx, projectId, sprintId, issueIds[0])
+	require.NoError(t, err)
+	require.Equal(t, "Done", issue.Status)
+
+	otherSprintId, err := apiClient.CreateSprint(ctx, projectId, models.CreateSprintRequest{Number: "2", StartDate: firstDay.AddDate(0, 0, 14), EndDate: firstDay.AddDate(0, 0, 25)})
+	require.NoError(t, err)
+	_, err = apiClient.CreateSprint(ctx, projectId, models.CreateSprintRequest{Number: "3", StartDate: firstDay.AddDate(0, 0, 7), EndDate: firstDay.AddDate(0, 0, 18)})
+	require.ErrorIs(t, err, ErrConflict)
+	current, err := apiClient.GetCurrentSprint(ctx, projectId, firstDay.AddDate(0, 0, 15))
+	require.NoError(t, err)
+	require.Equal(t, uint(otherSprintId), current.ID)
+	_, err = apiClient.GetCurrentSprint(ctx, projectId, firstDay.AddDate(0, 0, 12))
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, apiClient.MoveIssue(ctx, projectId, sprintId, issueIds[0], models.MoveIssueRequest{ProjectID: projectId, SprintID: otherSprintId}))
+	issue, err = apiClient.GetIssue(ctx, projectId, otherSprintId, issueIds[0])
+	require.NoError(t, err)
+	require.Equal(t, otherSprintId, issue.SprintID)
+
+	require.NoError(t, apiClient.PatchIssue(ctx, projectId, sprintId, issueIds[1], models.PatchIssueRequest{Status: "Done"}))
+	started, err := apiClient.StartSprint(ctx, projectId, sprintId)
+	require.NoError(t, err)
+	require.NotNil(t, started.StartedAt)
+	_, err = apiClient.StartSprint(ctx, projectId, otherSprintId)
+	require.ErrorIs(t, err, ErrConflict)
+	addedIssueId, err := apiClient.CreateIssue(ctx, projectId, sprintId, models.CreateIssueRequest{Type: "Bug", Title: "Added"})
+	require.NoError(t, err)
+
+	completion, err := apiClient.CompleteSprint(ctx, projectId, sprintId, 0)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(completion.Committed))
+	require.Equal(t, uint(addedIssueId), completion.Added[0].ID)
+	require.Equal(t, uint(issueIds[1]), completion.Completed[0].ID)
+	require.Equal(t, 4, len(completion.CarriedOver))
+	storedCompletion, err := apiClient.GetSprintCompletion(ctx, projectId, sprintId)
+	require.NoError(t, err)
+	require.Equal(t, completion.CarriedOver, storedCompletion.CarriedOver)
+	burndown, err := apiClient.GetSprintBurndown(ctx, projectId, sprintId, "issues")
+	require.NoError(t, err)
+	require.Equal(t, 4, burndown.CommittedWork)
+	chart, err := apiClient.GetSprintBurndownChart(ctx, projectId, sprintId, "issues", "csv")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(chart), "date,ideal,remaining,completed,scope\n"))
+	velocity, err := apiClient.GetVelocityReport(ctx, projectId, VelocityOptions{Unit: "issues", Simulations: 100})
+	require.NoError(t, err)
+	require.Equal(t, 1, velocity.Sprints[0].Completed)
+	require.Equal(t, 100, velocity.Forecast.Simulations)
+	flow, err := apiClient.GetFlowReport(ctx, projectId, FlowOptions{Type: "Task"})
+	require.NoError(t, err)
+	require.Equal(t, "Task", flow.Type)
+	require.Equal(t, 30, len(flow.CumulativeFlow))
+
+	wipLimit := 1
+	require.NoError(t, apiClient.SaveBoard(ctx, projectId, models.SaveBoardRequest{
+		Swimlane: models.SWIMLANE_TYPE,
+		Scope:    models.BOARD_SCOPE_PROJECT,
+		Columns: []models.BoardColumn{
+			{Name: "To Do", Statuses: []string{"", "To Do"}},
+			{Name: "In Progress", Statuses: []string{"In Progress"}, WipLimit: &wipLimit},
+			{Name: "Done", Statuses: []string{"Done"}},
+		},
+	}))
+	require.NoError(t, apiClient.MoveBoardIssue(ctx, projectId, issueIds[0], models.MoveBoardIssueRequest{Column: "In Progress"}))
+	err = apiClient.MoveBoardIssue(ctx, projectId, addedIssueId, models.MoveBoardIssueRequest{Column: "In Progress"})
+	require.ErrorIs(t, err, ErrConflict)
+	require.NoError(t, apiClient.MoveBoardIssue(ctx, projectId, addedIssueId, models.MoveBoardIssueRequest{Column: "Done", BeforeIssueID: uint(issueIds[1])}))
+	board, err := apiClient.GetBoard(ctx, projectId)
+	require.NoError(t, err)
+	require.Equal(t, 1, board.Columns[1].Issues)
+	require.Equal(t, "Bug", board.Swimlanes[0].Name)
+	require.Equal(t, uint(addedIssueId), board.Swimlanes[0].Cells[2].Issues[0].ID)
+
+	backlogIssueId, err := apiClient.CreateBacklogIssue(ctx, projectId, models.CreateIssueRequest{Type: "Task", Title: "Later"})
+	require.NoError(t, err)
+	backlog, err := apiClient.BacklogIssues(projectId, 2).All(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(backlog))
+	require.Equal(t, uint(backlogIssueId), backlog[4].ID)
+	require.NoError(t, apiClient.RankBacklogIssue(ctx, projectId, backlogIssueId, models.RankIssueRequest{BeforeIssueID: backlog[0].ID}))
+	backlog, err = apiClient.ListBacklogIssues(ctx, projectId, models.Page{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, uint(backlogIssueId), backlog[0].ID)
+	require.NoError(t, apiClient.MoveBacklogIssue(ctx, projectId, backlogIssueId, models.MoveIssueRequest{ProjectID: projectId, SprintID: otherSprintId}))
+	_, err = apiClient.GetIssue(ctx, projectId, otherSprintId, backlogIssueId)
+	require.NoError(t, err)
+	require.NoError(t, apiClient.RankIssue(ctx, projectId, otherSprintId, backlogIssueId, models.RankIssueRequest{AfterIssueID: uint(issueIds[0])}))
+	otherSprintIssues, err := apiClient.ListIssues(ctx, projectId, otherSprintId, models.Page{})
+	require.NoError(t, err)
+	require.Equal(t, []uint{uint(issueIds[0]), uint(backlogIssueId)}, []uint{otherSprintIssues[0].ID, otherSprintIssues[1].ID})
+
+	_, err = apiClient.GetSlaPolicy(ctx, projectId)
+	require.ErrorIs(t, err, ErrNotFound)
+	responseMinutes := 30
+	require.NoError(t, apiClient.SaveSlaPolicy(ctx, projectId, models.SaveSlaPolicyRequest{
+		PausedStatuses: []string{"Blocked"},
+		Targets:        []models.SlaTarget{{Priority: "P1", ResponseMinutes: &responseMinutes}},
+	}))
+	policy, err := apiClient.GetSlaPolicy(ctx, projectId)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Blocked"}, policy.PausedStatuses)
+	urgentIssueId, err := apiClient.CreateBacklogIssue(ctx, projectId, models.CreateIssueRequest{Type: "Bug", Title: "Urgent", Priority: "P1"})
+	require.NoError(t, err)
+	slaIssues, err := apiClient.GetSlaIssues(ctx, projectId, 60)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(slaIssues.Issues))
+	require.Equal(t, uint(urgentIssueId), slaIssues.Issues[0].IssueID)
+	require.Equal(t, models.SLA_STATE_RUNNING, slaIssues.Issues[0].Response.State)
+
+	specification, err := apiClient.Specification(ctx)
+	require.NoError(t, err)
+	var document internal.OpenAPIDocument
+	require.NoError(t, json.Unmarshal(specification, &document))
+	require.Equal(t, internal.OPENAPI_VERSION, document.OpenAPI)
+}
+
+func TestClientCoversEveryOperation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	apiClient, handler := newTestServer(t, routes.NewStatusRouter(time.Second))
+
+	projectId, _ := apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "coverage", Type: "scrum"})
+	sprintId, _ := apiClient.CreateSprint(ctx, projectId, models.CreateSprintRequest{Number: "1", StartDate: time.Now(), EndDate: time.Now().AddDate(0, 0, 7)})
+	issueId, _ := apiClient.CreateIssue(ctx, projectId, sprintId, models.CreateIssueRequest{Type: "Task", Title: "Title"})
+	apiClient.Health(ctx)
+	apiClient.Ready(ctx)
+	apiClient.ListProjects(ctx, models.Page{})
+	apiClient.PatchSprint(ctx, projectId, sprintId, models.PatchSprintRequest{Number: "1"})
+	apiClient.ListSprints(ctx, projectId, models.Page{})
+	apiClient.GetCurrentSprint(ctx, projectId, time.Time{})
+	apiClient.ListIssues(ctx, projectId, sprintId, models.Page{})
+	apiClient.GetIssue(ctx, projectId, sprintId, issueId)
+	apiClient.PatchIssue(ctx, projectId, sprintId, issueId, models.PatchIssueRequest{Title: "Title"})
+	apiClient.MoveIssue(ctx, projectId, sprintId, issueId, models.MoveIssueRequest{ProjectID: projectId, SprintID: sprintId})
+	apiClient.RankIssue(ctx, projectId, sprintId, issueId, models.RankIssueRequest{})
+	apiClient.StartSprint(ctx, projectId, sprintId)
+	apiClient.CompleteSprint(ctx, projectId, sprintId, 0)
+	apiClient.GetSprintCompletion(ctx, projectId, sprintId)
+	apiClient.GetSprintBurndown(ctx, projectId, sprintId, "")
+	apiClient.GetVelocityReport(ctx, projectId, VelocityOptions{})
+	apiClient.GetFlowReport(ctx, projectId, FlowOptions{})
+	apiClient.SaveBoard(ctx, projectId, models.SaveBoardRequest{})
+	apiClient.GetBoard(ctx, projectId)
+	apiClient.MoveBoardIssue(ctx, projectId, issueId, models.MoveBoardIssueRequest{Column: "Done"})
+	backlogIssueId, _ := apiClient.CreateBacklogIssue(ctx, projectId, models.CreateIssueRequest{Type: "Task", Title: "Title"})
+	apiClient.ListBacklogIssues(ctx, projectId, models.Page{})
+	apiClient.RankBacklogIssue(ctx, projectId, backlogIssueId, models.RankIssueRequest{})
+	apiClient.MoveBacklogIssue(ctx, projectId, backlogIssueId, models.MoveIssueRequest{ProjectID: projectId, SprintID: sprintId})
+	apiClient.SaveSlaPolicy(ctx, projectId, models.SaveSlaPolicyRequest{})
+	apiClient.GetSlaPolicy(ctx, projectId)
+	apiClient.GetSlaIssues(ctx, projectId, 0)
+	specification, err := apiClient.Specification(ctx)
+	require.NoError(t, err)
+
+	var document internal.OpenAPIDocument
+	require.NoError(t, json.Unmarshal(specification, &document))
+	requests := handler.getRequests()
+	// the metrics are scraped by Prometheus and the documentation is read in a browser
+	notCovered := map[string]bool{"Metrics": true, "Docs": true}
+	for path, pathItem := range document.Paths {
+		pattern := regexp.MustCompile("^" + regexp.MustCompile(`{[^}]+}`).ReplaceAllString(path, "[^/]+") + "$")
+		for method, operation := range pathItem {
+			if notCovered[operation.OperationID] {
+				continue
+			}
+			called := false
+			for _, request := range requests {
+				requestMethod, requestPath, _ := strings.Cut(request, " ")
+				called = called || (strings.EqualFold(requestMethod, method) && pattern.MatchString(requestPath))
+			}
+			require.True(t, called, "The client should call %s %s", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	apiClient, _ := newTestServer(t, routes.NewStatusRouter(time.Second))
+
+	projectId, err := apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "errors", Type: "scrum"})
+	require.NoError(t, err)
+
+	_, err = apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "errors", Type: "scrum"})
+	require.ErrorIs(t, err, ErrConflict)
+	var apiError *Error
+	require.True(t, errors.As(err, &apiError))
+	require.Equal(t, http.StatusConflict, apiError.StatusCode)
+	require.Equal(t, `Project with name "errors" already exists`, apiError.Message)
+
+	_, err = apiClient.CreateIssue(ctx, projectId, 1, models.CreateIssueRequest{})
+	require.ErrorIs(t, err, ErrBadRequest)
+	require.EqualError(t, err, "issue API error 400: body.title: is required\nbody.type: is required")
+
+	_, err = apiClient.ListSprints(ctx, projectId+1, models.Page{})
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NotErrorIs(t, err, ErrServerError)
+
+	statusRouter := routes.NewStatusRouter(time.Second)
+	drainingClient, handler := newTestServer(t, statusRouter)
+	statusRouter.StartDraining()
+	handler.failNext()
+	status, err := drainingClient.Ready(ctx)
+	require.ErrorIs(t, err, ErrServerError)
+	require.Equal(t, models.STATUS_DRAINING, status.Status)
+	require.Equal(t, 1, len(handler.getRequests()), "A failed readiness should not be retried")
+}
+
+func TestClientRetries(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	apiClient, handler := newTestServer(t, routes.NewStatusRouter(time.Second))
+
+	testCases := []struct {
+		name             string
+		failures         []int
+		call             func() error
+		expectedRequests int
+		expectedError    error
+	}{
+		{
+			name:             "a list is retried on gateway errors",
+			failures:         []int{http.StatusBadGateway, http.StatusGatewayTimeout},
+			call:             func() error { _, err := apiClient.ListProjects(ctx, models.Page{}); return err },
+			expectedRequests: 3,
+		},
+		{
+			name:             "the retries stop after the maximum",
+			failures:         []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			call:             func() error { _, err := apiClient.ListProjects(ctx, models.Page{}); return err },
+			expectedRequests: 3,
+			expectedError:    ErrServerError,
+		},
+		{
+			name:     "a creation is retried when the server did not process it",
+			failures: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+			call: func() error {
+				_, err := apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "retried", Type: "scrum"})
+				return err
+			},
+			expectedRequests: 3,
+		},
+		{
+			name:     "a creation is retried with its idempotency key when the server may have processed it",
+			failures: []int{http.StatusBadGateway},
+			call: func() error {
+				_, err := apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "retried-with-key", Type: "scrum"})
+				return err
+			},
+			expectedRequests: 2,
+		},
+		{
+			name:             "a client error is not retried",
+			failures:         []int{http.StatusBadRequest},
+			call:             func() error { _, err := apiClient.ListProjects(ctx, models.Page{}); return err },
+			expectedRequests: 1,
+			expectedError:    ErrBadRequest,
+		},
+	}
+	for _, testCase := range testCases {
+		handler.failNext(testCase.failures...)
+		err := testCase.call()
+		if testCase.expectedError == nil {
+			require.NoError(t, err, testCase.name)
+		} else {
+			require.ErrorIs(t, err, testCase.expectedError, testCase.name)
+		}
+		require.Equal(t, testCase.expectedRequests, len(handler.getRequests()), testCase.name)
+	}
+
+	handler.loseNext(1)
+	projectId, err := apiClient.CreateProject(ctx, models.CreateProjectRequest{Name: "lost-response", Type: "scrum"})
+	require.NoError(t, err, "The retry should get the response of the creation")
+	keys := handler.getIdempotencyKeys()
+	require.Equal(t, 2, len(keys))
+	require.NotEqual(t, "", keys[0])
+	require.Equal(t, keys[0], keys[1], "The retries should send the same idempotency key")
+	projects, err := apiClient.ListProjects(ctx, models.Page{})
+	require.NoError(t, err)
+	created := 0
+	for _, project := range projects {
+		if project.Name == "lost-response" {
+			require.Equal(t, uint(projectId), project.ID)
+			created++
+		}
+	}
+	require.Equal(t, 1, created, "The creation should be applied once")
+
+	canceledContext, cancel := context.WithCancel(ctx)
+	slowClient, err := NewClient(apiClient.baseURL.String(), WithRetries(3, time.Hour))
+	require.NoError(t, err)
+	handler.failNext(http.StatusServiceUnavailable)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	_, err = slowClient.ListProjects(canceledContext, models.Page{})
+	require.ErrorIs(t, err, context.Canceled, "The context should interrupt the wait before a retry")
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// The sentinel errors match the *Error of their status codes with errors.Is
+var (
+	ErrBadRequest  = errors.New("bad request")
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrServerError = errors.New("server error")
+)
+
+// Error is an error answered by the API, Message is the ErrorMessage of its models.ErrorResponse
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func newError(statusCode int, body []byte) *Error {
+	var errorResponse models.ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.ErrorMessage != "" {
+		return &Error{StatusCode: statusCode, Message: errorResponse.ErrorMessage}
+	}
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &Error{StatusCode: statusCode, Message: message}
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("issue API error %d: %s", err.StatusCode, err.Message)
+}
+
+func (err *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return err.StatusCode == http.StatusBadRequest
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return err.StatusCode == http.StatusConflict
+	case ErrServerError:
+		return err.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
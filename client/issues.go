@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// CreateIssue returns the id of the new issue, ErrNotFound when the project or the sprint does not exist
+func (client *Client) CreateIssue(ctx context.Context, projectId int, sprintId int, issue models.CreateIssueRequest) (int, error) {
+	var response models.CreateResponse
+	err := client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId),
+		body:   issue,
+	}, &response)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(response)
+}
+
+// ListIssues returns page of the issues of a sprint, ordered by id
+func (client *Client) ListIssues(ctx context.Context, projectId int, sprintId int, page models.Page) ([]models.GetIssueResponse, error) {
+	issues := []models.GetIssueResponse{}
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/issues", projectId, sprintId),
+		query:      pageQuery(page),
+		idempotent: true,
+	}, &issues)
+	return issues, err
+}
+
+// Issues iterates over every issue of a sprint, fetching pageSize issues at a time
+func (client *Client) Issues(projectId int, sprintId int, pageSize int) *Iterator[models.GetIssueResponse] {
+	return newIterator(pageSize, func(ctx context.Context, page models.Page) ([]models.GetIssueResponse, error) {
+		return client.ListIssues(ctx, projectId, sprintId, page)
+	})
+}
+
+func (client *Client) GetIssue(ctx context.Context, projectId int, sprintId int, issueId int) (models.GetIssueResponse, error) {
+	var issue models.GetIssueResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", projectId, sprintId, issueId),
+		idempotent: true,
+	}, &issue)
+	return issue, err
+}
+
+// PatchIssue writes the non-zero fields of issue, the issue cannot be moved by a patch
+func (client *Client) PatchIssue(ctx context.Context, projectId int, sprintId int, issueId int, issue models.PatchIssueRequest) error {
+	return client.do(ctx, request{
+		method:     http.MethodPatch,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d", projectId, sprintId, issueId),
+		body:       issue,
+		idempotent: true,
+	}, nil)
+}
+
+// MoveIssue moves an issue to the project and the sprint of target.
+// It is not idempotent, once moved the issue is not found in its former sprint.
+func (client *Client) MoveIssue(ctx context.Context, projectId int, sprintId int, issueId int, target models.MoveIssueRequest) error {
+	return client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d/move", projectId, sprintId, issueId),
+		body:   target,
+	}, nil)
+}
+
+// RankIssue places an issue of a sprint right after or right before another issue of the sprint.
+// It is idempotent, repeating it leaves the issue at the same place.
+func (client *Client) RankIssue(ctx context.Context, projectId int, sprintId int, issueId int, rank models.RankIssueRequest) error {
+	return client.do(ctx, request{
+		method:     http.MethodPost,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/issues/%d/rank", projectId, sprintId, issueId),
+		body:       rank,
+		idempotent: true,
+	}, nil)
+}
+
+// CreateBacklogIssue returns the id of the new issue of the backlog, ErrNotFound when the project does not exist
+func (client *Client) CreateBacklogIssue(ctx context.Context, projectId int, issue models.CreateIssueRequest) (int, error) {
+	var response models.CreateResponse
+	err := client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/backlog/issues", projectId),
+		body:   issue,
+	}, &response)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(response)
+}
+
+// ListBacklogIssues returns page of the issues of the backlog of a project, ordered by id
+func (client *Client) ListBacklogIssues(ctx context.Context, projectId int, page models.Page) ([]models.GetIssueResponse, error) {
+	issues := []models.GetIssueResponse{}
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/backlog/issues", projectId),
+		query:      pageQuery(page),
+		idempotent: true,
+	}, &issues)
+	return issues, err
+}
+
+// BacklogIssues iterates over every issue of the backlog of a project, fetching pageSize issues at a time
+func (client *Client) BacklogIssues(projectId int, pageSize int) *Iterator[models.GetIssueResponse] {
+	return newIterator(pageSize, func(ctx context.Context, page models.Page) ([]models.GetIssueResponse, error) {
+		return client.ListBacklogIssues(ctx, projectId, page)
+	})
+}
+
+// MoveBacklogIssue moves an issue of the backlog to the project and the sprint of target, it is not idempotent
+func (client *Client) MoveBacklogIssue(ctx context.Context, projectId int, issueId int, target models.MoveIssueRequest) error {
+	return client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/backlog/issues/%d/move", projectId, issueId),
+		body:   target,
+	}, nil)
+}
+
+// RankBacklogIssue places an issue of the backlog right after or right before another issue of the backlog, it is idempotent
+func (client *Client) RankBacklogIssue(ctx context.Context, projectId int, issueId int, rank models.RankIssueRequest) error {
+	return client.do(ctx, request{
+		method:     http.MethodPost,
+		path:       fmt.Sprintf("/v1/projects/%d/backlog/issues/%d/rank", projectId, issueId),
+		body:       rank,
+		idempotent: true,
+	}, nil)
+}
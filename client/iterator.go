@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// DEFAULT_PAGE_SIZE is the limit of the pages fetched by the iterators when none is given
+const DEFAULT_PAGE_SIZE = 100
+
+// Iterator walks a list page by page, a page is fetched when the previous one is consumed:
+//
+//	projects := apiClient.Projects(0)
+//	for projects.Next(ctx) {
+//		project := projects.Value()
+//	}
+//	err := projects.Err()
+type Iterator[T any] struct {
+	fetch    func(ctx context.Context, page models.Page) ([]T, error)
+	page     models.Page
+	items    []T
+	index    int
+	current  T
+	lastPage bool
+	err      error
+}
+
+func newIterator[T any](pageSize int, fetch func(ctx context.Context, page models.Page) ([]T, error)) *Iterator[T] {
+	if pageSize <= 0 || pageSize > models.MAX_PAGE_LIMIT {
+		pageSize = DEFAULT_PAGE_SIZE
+	}
+	return &Iterator[T]{fetch: fetch, page: models.Page{Limit: pageSize}}
+}
+
+// Next advances to the next item, it returns false at the end of the list or on the first error
+func (iterator *Iterator[T]) Next(ctx context.Context) bool {
+	if iterator.err != nil {
+		return false
+	}
+	if iterator.index >= len(iterator.items) {
+		if iterator.lastPage {
+			return false
+		}
+		items, err := iterator.fetch(ctx, iterator.page)
+		if err != nil {
+			iterator.err = err
+			return false
+		}
+		// a short page is the last one
+		iterator.lastPage = len(items) < iterator.page.Limit
+		iterator.page.Offset += len(items)
+		iterator.items = items
+		iterator.index = 0
+		if len(items) == 0 {
+			return false
+		}
+	}
+	iterator.current = iterator.items[iterator.index]
+	iterator.index++
+	return true
+}
+
+// Value is the item reached by the last call to Next
+func (iterator *Iterator[T]) Value() T {
+	return iterator.current
+}
+
+// Err is the error that stopped the iteration, nil at the end of the list
+func (iterator *Iterator[T]) Err() error {
+	return iterator.err
+}
+
+// All consumes the iterator and returns its items
+func (iterator *Iterator[T]) All(ctx context.Context) ([]T, error) {
+	items := []T{}
+	for iterator.Next(ctx) {
+		items = append(items, iterator.Value())
+	}
+	return items, iterator.Err()
+}
+
+func pageQuery(page models.Page) url.Values {
+	query := url.Values{}
+	if page.Limit > 0 {
+		query.Set("limit", strconv.Itoa(page.Limit))
+	}
+	if page.Offset > 0 {
+		query.Set("offset", strconv.Itoa(page.Offset))
+	}
+	return query
+}
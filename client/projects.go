@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// CreateProject returns the id of the new project, ErrConflict when the name is taken
+func (client *Client) CreateProject(ctx context.Context, project models.CreateProjectRequest) (int, error) {
+	var response models.CreateResponse
+	err := client.do(ctx, request{method: http.MethodPost, path: "/v1/projects", body: project}, &response)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(response)
+}
+
+// ListProjects returns page of the projects, ordered by id
+func (client *Client) ListProjects(ctx context.Context, page models.Page) ([]models.Project, error) {
+	projects := []models.Project{}
+	err := client.do(ctx, request{method: http.MethodGet, path: "/v1/projects", query: pageQuery(page), idempotent: true}, &projects)
+	return projects, err
+}
+
+// Projects iterates over every project, fetching pageSize projects at a time
+func (client *Client) Projects(pageSize int) *Iterator[models.Project] {
+	return newIterator(pageSize, client.ListProjects)
+}
+
+func parseCreatedId(response models.CreateResponse) (int, error) {
+	id, err := strconv.Atoi(response.Id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q in the response: %w", response.Id, err)
+	}
+	return id, nil
+}
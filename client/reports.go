@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// VelocityOptions are the query parameters of the velocity report, the zero values select the defaults of the server
+type VelocityOptions struct {
+	Unit        string
+	Sprints     int
+	Window      int
+	Simulations int
+}
+
+// GetVelocityReport returns the velocity of the latest completed sprints of a project, and the forecast of its open issues
+func (client *Client) GetVelocityReport(ctx context.Context, projectId int, options VelocityOptions) (models.VelocityReportResponse, error) {
+	query := url.Values{}
+	if options.Unit != "" {
+		query.Set("unit", options.Unit)
+	}
+	for name, value := range map[string]int{"sprints": options.Sprints, "window": options.Window, "simulations": options.Simulations} {
+		if value != 0 {
+			query.Set(name, strconv.Itoa(value))
+		}
+	}
+	var report models.VelocityReportResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/reports/velocity", projectId),
+		query:      query,
+		idempotent: true,
+	}, &report)
+	return report, err
+}
+
+// FlowOptions are the query parameters of the flow report, the empty fields select the defaults of the server
+type FlowOptions struct {
+	From     string
+	To       string
+	Type     string
+	Assignee string
+}
+
+// GetFlowReport returns the lead and cycle times of the issues of a project completed from From to To, and its cumulative flow
+func (client *Client) GetFlowReport(ctx context.Context, projectId int, options FlowOptions) (models.FlowReportResponse, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{"from": options.From, "to": options.To, "type": options.Type, "assignee": options.Assignee} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	var report models.FlowReportResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/reports/flow", projectId),
+		query:      query,
+		idempotent: true,
+	}, &report)
+	return report, err
+}
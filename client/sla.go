@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// SaveSlaPolicy creates the SLA policy of a project, or replaces its paused statuses and its targets
+func (client *Client) SaveSlaPolicy(ctx context.Context, projectId int, policy models.SaveSlaPolicyRequest) error {
+	return client.do(ctx, request{
+		method:     http.MethodPut,
+		path:       fmt.Sprintf("/v1/projects/%d/sla", projectId),
+		body:       policy,
+		idempotent: true,
+	}, nil)
+}
+
+// GetSlaPolicy returns the SLA policy of a project, ErrNotFound when the project has no policy
+func (client *Client) GetSlaPolicy(ctx context.Context, projectId int) (models.SlaPolicyResponse, error) {
+	var policy models.SlaPolicyResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sla", projectId),
+		idempotent: true,
+	}, &policy)
+	return policy, err
+}
+
+// GetSlaIssues returns the issues of a project whose SLA is breached or due within withinMinutes,
+// 0 selects the default of the server
+func (client *Client) GetSlaIssues(ctx context.Context, projectId int, withinMinutes int) (models.SlaIssuesResponse, error) {
+	query := url.Values{}
+	if withinMinutes != 0 {
+		query.Set("within", strconv.Itoa(withinMinutes))
+	}
+	var slaIssues models.SlaIssuesResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sla/issues", projectId),
+		query:      query,
+		idempotent: true,
+	}, &slaIssues)
+	return slaIssues, err
+}
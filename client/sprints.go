@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// CreateSprint returns the id of the new sprint, ErrNotFound when the project does not exist
+func (client *Client) CreateSprint(ctx context.Context, projectId int, sprint models.CreateSprintRequest) (int, error) {
+	var response models.CreateResponse
+	err := client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/sprints", projectId),
+		body:   sprint,
+	}, &response)
+	if err != nil {
+		return 0, err
+	}
+	return parseCreatedId(response)
+}
+
+// PatchSprint writes the non-zero fields of sprint, its ID and ProjectID are set from the arguments
+func (client *Client) PatchSprint(ctx context.Context, projectId int, sprintId int, sprint models.PatchSprintRequest) error {
+	sprint.ID = uint(sprintId)
+	sprint.ProjectID = projectId
+	return client.do(ctx, request{
+		method:     http.MethodPatch,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d", projectId, sprintId),
+		body:       sprint,
+		idempotent: true,
+	}, nil)
+}
+
+// ListSprints returns page of the sprints of a project, ordered by id
+func (client *Client) ListSprints(ctx context.Context, projectId int, page models.Page) ([]models.GetSprintResponse, error) {
+	sprints := []models.GetSprintResponse{}
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints", projectId),
+		query:      pageQuery(page),
+		idempotent: true,
+	}, &sprints)
+	return sprints, err
+}
+
+// Sprints iterates over every sprint of a project, fetching pageSize sprints at a time
+func (client *Client) Sprints(projectId int, pageSize int) *Iterator[models.GetSprintResponse] {
+	return newIterator(pageSize, func(ctx context.Context, page models.Page) ([]models.GetSprintResponse, error) {
+		return client.ListSprints(ctx, projectId, page)
+	})
+}
+
+// GetCurrentSprint returns the sprint covering the calendar day of day, today in the timezone of the project when day is zero.
+// It returns ErrNotFound when no sprint covers the day.
+func (client *Client) GetCurrentSprint(ctx context.Context, projectId int, day time.Time) (models.GetSprintResponse, error) {
+	query := url.Values{}
+	if !day.IsZero() {
+		query.Set("date", day.Format("2006-01-02"))
+	}
+	var sprint models.GetSprintResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/current", projectId),
+		query:      query,
+		idempotent: true,
+	}, &sprint)
+	return sprint, err
+}
+
+// StartSprint starts a sprint, ErrConflict when it was already started or another sprint of the project is active
+func (client *Client) StartSprint(ctx context.Context, projectId int, sprintId int) (models.GetSprintResponse, error) {
+	var sprint models.GetSprintResponse
+	err := client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/sprints/%d/start", projectId, sprintId),
+	}, &sprint)
+	return sprint, err
+}
+
+// CompleteSprint completes the active sprint, its unfinished issues go to nextSprintId, or to the backlog when it is 0.
+// It returns ErrConflict when the sprint is not active.
+func (client *Client) CompleteSprint(ctx context.Context, projectId int, sprintId int, nextSprintId int) (models.SprintCompletionResponse, error) {
+	var completion models.SprintCompletionResponse
+	err := client.do(ctx, request{
+		method: http.MethodPost,
+		path:   fmt.Sprintf("/v1/projects/%d/sprints/%d/complete", projectId, sprintId),
+		body:   models.CompleteSprintRequest{NextSprintID: nextSprintId},
+	}, &completion)
+	return completion, err
+}
+
+// GetSprintCompletion returns the completion snapshot of a sprint, ErrNotFound when it is not completed
+func (client *Client) GetSprintCompletion(ctx context.Context, projectId int, sprintId int) (models.SprintCompletionResponse, error) {
+	var completion models.SprintCompletionResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/completion", projectId, sprintId),
+		idempotent: true,
+	}, &completion)
+	return completion, err
+}
+
+// GetSprintBurndown returns the work of a sprint day by day, unit is points or issues, points when empty
+func (client *Client) GetSprintBurndown(ctx context.Context, projectId int, sprintId int, unit string) (models.SprintBurndownResponse, error) {
+	var burndown models.SprintBurndownResponse
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/burndown", projectId, sprintId),
+		query:      getBurndownQuery(unit, ""),
+		idempotent: true,
+	}, &burndown)
+	return burndown, err
+}
+
+// GetSprintBurndownChart returns the burndown of a sprint as a CSV table or an SVG chart, format is csv or svg
+func (client *Client) GetSprintBurndownChart(ctx context.Context, projectId int, sprintId int, unit string, format string) ([]byte, error) {
+	var chart []byte
+	err := client.do(ctx, request{
+		method:     http.MethodGet,
+		path:       fmt.Sprintf("/v1/projects/%d/sprints/%d/burndown", projectId, sprintId),
+		query:      getBurndownQuery(unit, format),
+		idempotent: true,
+	}, &chart)
+	return chart, err
+}
+
+func getBurndownQuery(unit string, format string) url.Values {
+	query := url.Values{}
+	if unit != "" {
+		query.Set("unit", unit)
+	}
+	if format != "" {
+		query.Set("format", format)
+	}
+	return query
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+// Health calls the liveness probe
+func (client *Client) Health(ctx context.Context) (models.StatusResponse, error) {
+	var status models.StatusResponse
+	err := client.do(ctx, request{method: http.MethodGet, path: "/-/healthz", idempotent: true}, &status)
+	return status, err
+}
+
+// Ready calls the readiness probe. When a check fails or the server is draining,
+// the status is returned with an *Error of status code 503, without retrying.
+func (client *Client) Ready(ctx context.Context) (models.StatusResponse, error) {
+	var status models.StatusResponse
+	err := client.do(ctx, request{
+		method:             http.MethodGet,
+		path:               "/-/ready",
+		idempotent:         true,
+		decodedErrorStatus: http.StatusServiceUnavailable,
+	}, &status)
+	return status, err
+}
+
+// Specification returns the OpenAPI document of the server
+func (client *Client) Specification(ctx context.Context) ([]byte, error) {
+	var specification []byte
+	err := client.do(ctx, request{method: http.MethodGet, path: "/-/openapi.json", idempotent: true}, &specification)
+	return specification, err
+}
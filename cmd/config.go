@@ -0,0 +1,24 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"issue-service/app/issue-api/cfg"
+	"issue-service/internal"
+)
+
+const configUsage = "usage: config print"
+
+func runConfigCommand(config cfg.EnvConfiguration, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(configUsage)
+	}
+
+	switch args[0] {
+	case "print":
+		return internal.PrintConfig(config, out)
+	default:
+		return fmt.Errorf("unknown config command \"%s\", %s", args[0], configUsage)
+	}
+}
@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"issue-service/app/issue-api/routes"
+	"issue-service/app/issue-api/routes/sla"
 	"issue-service/app/issue-api/webserver"
 	"issue-service/internal"
-	"net/http"
+	"net"
 	"os"
+	"os/signal"
+	"syscall"
+	// the image has no zoneinfo, the timezones of the projects are embedded
+	_ "time/tzdata"
 
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	log "github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 )
 
 func getLogLevel(logLevel string) log.Level {
@@ -37,20 +47,130 @@ func initLogging(logLevel string) {
 	log.SetOutput(os.Stdout)
 
 	log.SetLevel(getLogLevel(logLevel))
+
+	log.AddHook(internal.TraceHook{})
 }
 
 func main() {
-	pwd, _ := os.Getwd()
-	config, err := internal.GetConfig(pwd + "/.env")
+	config, arguments, err := internal.LoadConfig(os.Args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("Error reading env configuration: %s", err.Error())
+		log.Fatalf("Error reading configuration: %s", err.Error())
 		return
 	}
 
 	initLogging(config.LOG_LEVEL)
-	router := webserver.NewRouter(config)
+
+	if len(arguments) > 0 && arguments[0] == "config" {
+		if err := runConfigCommand(config, arguments[1:], os.Stdout); err != nil {
+			log.Fatalf("Error printing configuration: %s", err.Error())
+		}
+		return
+	}
+
+	var configError *internal.ConfigError
+	if err := internal.ValidateConfig(config); errors.As(err, &configError) {
+		log.WithField("problems", configError.Problems).Fatal("Invalid configuration")
+		return
+	}
+
+	database, err := internal.ConnectDatabase(config)
+	if err != nil {
+		log.Fatalf("Error connecting to database: %s", err.Error())
+		return
+	}
+
+	if len(arguments) > 0 {
+		switch arguments[0] {
+		case "migrate":
+			if err := runMigrateCommand(database, arguments[1:], os.Stdout); err != nil {
+				log.Fatalf("Error running migrations: %s", err.Error())
+			}
+		default:
+			log.Fatalf("Unknown command \"%s\", available commands: config, migrate", arguments[0])
+		}
+		internal.CloseDatabase(database)
+		return
+	}
+
+	if config.DATABASE_MIGRATE_ON_STARTUP {
+		if _, err := internal.MigrateUp(database); err != nil {
+			log.Fatalf("Error running migrations: %s", err.Error())
+			return
+		}
+	}
+
+	tracerProvider, err := internal.NewTracerProvider(context.Background(), config)
+	if err != nil {
+		log.Fatalf("Error initializing tracing: %s", err.Error())
+		return
+	}
+	internal.SetGlobalTracing(tracerProvider)
+
+	statusRouter := routes.NewStatusRouter(config.HTTP_READINESS_TIMEOUT, internal.GetDatabaseStatusChecks(database)...)
+	stores := internal.NewGormStores(database)
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		log.Fatalf("Error connecting to database: %s", err.Error())
+		return
+	}
+	slaChecker := sla.NewChecker(stores, config.SLA_CHECK_INTERVAL)
+	metrics := internal.NewMetrics(
+		collectors.NewDBStatsCollector(sqlDatabase, config.DATABASE_NAME),
+		internal.NewStoreCollector(stores),
+		slaChecker.Collector(),
+	)
+	var reportResponseProblems internal.ResponseProblemsReporter
+	if config.HTTP_VALIDATE_RESPONSES {
+		reportResponseProblems = internal.LogResponseProblems
+	}
+	router, err := webserver.NewRouter(
+		stores,
+		statusRouter,
+		metrics,
+		internal.NewAccessLogger(log.StandardLogger(), config.LOG_ACCESS_SAMPLE_PERCENT),
+		reportResponseProblems,
+	)
+	if err != nil {
+		log.Fatalf("Error creating the router: %s", err.Error())
+		return
+	}
+	server := webserver.NewServer(config, router)
+
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		log.Fatalf("Error listening on port %s: %s", config.HTTP_PORT, err.Error())
+		return
+	}
 
 	log.Info(fmt.Sprintf("Server starting on port: %s", config.HTTP_PORT))
 
-	log.Info(http.ListenAndServe(fmt.Sprintf(":%s", config.HTTP_PORT), router))
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	slaCheckerDone := make(chan struct{})
+	go func() {
+		defer close(slaCheckerDone)
+		slaChecker.Run(ctx)
+	}()
+
+	err = webserver.RunServer(ctx, server, listener, statusRouter, config.HTTP_SHUTDOWN_DELAY, config.HTTP_SHUTDOWN_TIMEOUT)
+	if err != nil {
+		log.Error(fmt.Sprintf("Error running the server: %s", err.Error()))
+	}
+	// the checker stops before the database connections are closed
+	stop()
+	<-slaCheckerDone
+
+	if err := internal.CloseDatabase(database); err != nil {
+		log.Error(fmt.Sprintf("Error closing the database connections: %s", err.Error()))
+	}
+
+	flushContext, cancel := context.WithTimeout(context.Background(), config.HTTP_SHUTDOWN_TIMEOUT)
+	defer cancel()
+	if err := tracerProvider.Shutdown(flushContext); err != nil {
+		log.Error(fmt.Sprintf("Error flushing the spans: %s", err.Error()))
+	}
 }
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"issue-service/internal"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = "usage: migrate up | down [steps] | status"
+
+func runMigrateCommand(database *gorm.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := internal.MigrateUp(database)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d migration(s)\n", len(applied))
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsedSteps, err := strconv.Atoi(args[1])
+			if err != nil || parsedSteps < 1 {
+				return fmt.Errorf("steps must be a positive number, got \"%s\"", args[1])
+			}
+			steps = parsedSteps
+		}
+		reverted, err := internal.MigrateDown(database, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Reverted %d migration(s)\n", len(reverted))
+		return nil
+	case "status":
+		statuses, err := internal.GetMigrationStatus(database)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(statuses, out)
+	default:
+		return fmt.Errorf("unknown migrate command \"%s\", %s", args[0], migrateUsage)
+	}
+}
+
+func printMigrationStatus(statuses []internal.MigrationStatus, out io.Writer) error {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.Applied() {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(writer, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"issue-service/app/issue-api/routes/models"
+
+	"github.com/spf13/pflag"
+)
+
+const issuesUsage = `usage: yait issues list [--project id] [--sprint id]
+       yait issues create [--project id] [--sprint id] --type type --title title [--description text | --edit] [--status status] [--assignee assignee] [--priority P0-P4] [--epic epic] [--points points | --size size]
+       yait issues view KEY
+       yait issues move KEY --to-sprint id [--to-project id]
+       yait issues assign KEY ASSIGNEE
+       yait issues transition KEY STATUS
+KEY is project-sprint-issue, such as 3-7-42, or the issue id with the --project and --sprint flags`
+
+// issueKey identifies an issue in the commands, the API addresses an issue by its project and its sprint
+type issueKey struct {
+	project int
+	sprint  int
+	issue   int
+}
+
+func (key issueKey) String() string {
+	return fmt.Sprintf("%d-%d-%d", key.project, key.sprint, key.issue)
+}
+
+func getIssueKey(issue models.GetIssueResponse) issueKey {
+	return issueKey{project: issue.ProjectID, sprint: issue.SprintID, issue: int(issue.ID)}
+}
+
+func runIssuesCommand(subcommand string, args []string, terminal *terminal) error {
+	switch subcommand {
+	case "list":
+		return listIssues(args, terminal)
+	case "create":
+		return createIssue(args, terminal)
+	case "view":
+		return runIssueCommand("view", args, 0, terminal, nil)
+	case "move":
+		return moveIssue(args, terminal)
+	case "assign":
+		return runIssueCommand("assign", args, 1, terminal, func(session *session, key issueKey, assignee string) error {
+			patch := models.PatchIssueRequest{Assignee: assignee}
+			return session.client.PatchIssue(context.Background(), key.project, key.sprint, key.issue, patch)
+		})
+	case "transition":
+		return runIssueCommand("transition", args, 1, terminal, func(session *session, key issueKey, status string) error {
+			patch := models.PatchIssueRequest{Status: status}
+			return session.client.PatchIssue(context.Background(), key.project, key.sprint, key.issue, patch)
+		})
+	default:
+		return fmt.Errorf("unknown issues command \"%s\", %s", subcommand, issuesUsage)
+	}
+}
+
+// addLocationFlags adds the --project and --sprint flags, their defaults are the project and the sprint of the profile
+func addLocationFlags(flags *pflag.FlagSet) (*int, *int) {
+	project := flags.Int("project", 0, "project of the issues, by default the project of the profile")
+	sprint := flags.Int("sprint", 0, "sprint of the issues, by default the sprint of the profile")
+	return project, sprint
+}
+
+// parseIssueKey reads a project-sprint-issue key, or an issue id completed by the flags and the profile
+func (session *session) parseIssueKey(key string, project int, sprint int) (issueKey, error) {
+	invalidKey := fmt.Errorf("invalid issue key \"%s\", %s", key, "expected project-sprint-issue, such as 3-7-42, or an issue id")
+	parts := strings.Split(key, "-")
+	ids := make([]int, len(parts))
+	for index, part := range parts {
+		id, err := strconv.Atoi(part)
+		if err != nil || id <= 0 {
+			return issueKey{}, invalidKey
+		}
+		ids[index] = id
+	}
+
+	switch len(ids) {
+	case 3:
+		return issueKey{project: ids[0], sprint: ids[1], issue: ids[2]}, nil
+	case 1:
+		projectId, err := session.getProject(project)
+		if err != nil {
+			return issueKey{}, err
+		}
+		sprintId, err := session.getSprint(sprint)
+		if err != nil {
+			return issueKey{}, err
+		}
+		return issueKey{project: projectId, sprint: sprintId, issue: ids[0]}, nil
+	default:
+		return issueKey{}, invalidKey
+	}
+}
+
+func listIssues(args []string, terminal *terminal) error {
+	flags := newFlagSet("issues list", terminal)
+	options := addGlobalFlags(flags)
+	project, sprint := addLocationFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+	sprintId, err := session.getSprint(*sprint)
+	if err != nil {
+		return err
+	}
+
+	issues, err := session.client.Issues(projectId, sprintId, 0).All(context.Background())
+	if err != nil {
+		return err
+	}
+	return session.print(issues, func(writer *tabwriter.Writer) {
+		fmt.Fprintln(writer, "KEY\tTYPE\tSTATUS\tASSIGNEE\tTITLE")
+		for _, issue := range issues {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", getIssueKey(issue), issue.Type,
+				formatText(issue.Status), formatText(issue.Assignee), issue.Title)
+		}
+	})
+}
+
+func createIssue(args []string, terminal *terminal) error {
+	flags := newFlagSet("issues create", terminal)
+	options := addGlobalFlags(flags)
+	project, sprint := addLocationFlags(flags)
+	var issue models.CreateIssueRequest
+	flags.StringVar(&issue.Type, "type", "", "type of the issue, such as Bug or Story")
+	flags.StringVar(&issue.Title, "title", "", "title of the issue")
+	flags.StringVar(&issue.Description, "description", "", "description of the issue, - reads it from the standard input")
+	edit := flags.BoolP("edit", "e", false, "write the description in the editor of the profile, $VISUAL or $EDITOR")
+	flags.StringVar(&issue.Status, "status", "", "status of the issue")
+	flags.StringVar(&issue.Assignee, "assignee", "", "assignee of the issue")
+	flags.StringVar(&issue.Priority, "priority", "", "priority of the issue, from P0 the highest to P4")
+	flags.StringVar(&issue.Epic, "epic", "", "epic of the issue")
+	points := flags.Int("points", 0, "story points of the issue, on the estimation scale of the project")
+	flags.StringVar(&issue.Size, "size", "", "t-shirt size of the issue, in the projects estimated with sizes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if issue.Type == "" || issue.Title == "" {
+		return errors.New(issuesUsage)
+	}
+	if flags.Changed("points") {
+		issue.StoryPoints = points
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+	sprintId, err := session.getSprint(*sprint)
+	if err != nil {
+		return err
+	}
+
+	if issue.Description == "-" {
+		description, err := ioutil.ReadAll(session.in)
+		if err != nil {
+			return fmt.Errorf("reading the description: %w", err)
+		}
+		issue.Description = strings.TrimSpace(string(description))
+	}
+	if *edit {
+		if issue.Description, err = session.edit(session.profile.Editor, issue.Description); err != nil {
+			return err
+		}
+	}
+
+	id, err := session.client.CreateIssue(context.Background(), projectId, sprintId, issue)
+	if err != nil {
+		return err
+	}
+	key := issueKey{project: projectId, sprint: sprintId, issue: id}
+	return session.print(map[string]interface{}{"id": id, "key": key.String()}, func(writer *tabwriter.Writer) {
+		fmt.Fprintf(writer, "Created issue %s\n", key)
+	})
+}
+
+func moveIssue(args []string, terminal *terminal) error {
+	flags := newFlagSet("issues move", terminal)
+	options := addGlobalFlags(flags)
+	project, sprint := addLocationFlags(flags)
+	targetProject := flags.Int("to-project", 0, "project receiving the issue, by default the project of the issue")
+	targetSprint := flags.Int("to-sprint", 0, "sprint receiving the issue")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 || *targetSprint <= 0 {
+		return errors.New(issuesUsage)
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	key, err := session.parseIssueKey(flags.Arg(0), *project, *sprint)
+	if err != nil {
+		return err
+	}
+
+	target := models.MoveIssueRequest{ProjectID: *targetProject, SprintID: *targetSprint}
+	if target.ProjectID == 0 {
+		target.ProjectID = key.project
+	}
+	if err := session.client.MoveIssue(context.Background(), key.project, key.sprint, key.issue, target); err != nil {
+		return err
+	}
+	return session.printIssue(issueKey{project: target.ProjectID, sprint: target.SprintID, issue: key.issue})
+}
+
+// runIssueCommand runs a command taking a key and argumentCount arguments, then prints the issue.
+// A nil change only prints the issue.
+func runIssueCommand(name string, args []string, argumentCount int, terminal *terminal,
+	change func(session *session, key issueKey, argument string) error) error {
+	flags := newFlagSet("issues "+name, terminal)
+	options := addGlobalFlags(flags)
+	project, sprint := addLocationFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1+argumentCount {
+		return errors.New(issuesUsage)
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	key, err := session.parseIssueKey(flags.Arg(0), *project, *sprint)
+	if err != nil {
+		return err
+	}
+
+	if change != nil {
+		if err := change(session, key, flags.Arg(1)); err != nil {
+			return err
+		}
+	}
+	return session.printIssue(key)
+}
+
+func (session *session) printIssue(key issueKey) error {
+	issue, err := session.client.GetIssue(context.Background(), key.project, key.sprint, key.issue)
+	if err != nil {
+		return err
+	}
+	return session.print(issue, func(writer *tabwriter.Writer) {
+		fmt.Fprintf(writer, "KEY\t%s\n", key)
+		fmt.Fprintf(writer, "TYPE\t%s\n", issue.Type)
+		fmt.Fprintf(writer, "TITLE\t%s\n", issue.Title)
+		fmt.Fprintf(writer, "STATUS\t%s\n", formatText(issue.Status))
+		fmt.Fprintf(writer, "ASSIGNEE\t%s\n", formatText(issue.Assignee))
+		if issue.StoryPoints != nil {
+			fmt.Fprintf(writer, "POINTS\t%d\n", *issue.StoryPoints)
+		}
+		fmt.Fprintf(writer, "CREATED\t%s\n", issue.CreatedAt.Format(time.RFC3339))
+		fmt.Fprintf(writer, "UPDATED\t%s\n", issue.UpdatedAt.Format(time.RFC3339))
+		if issue.Description != "" {
+			// the description is not aligned with the fields
+			writer.Flush()
+			fmt.Fprintf(session.out, "\n%s\n", issue.Description)
+		}
+	})
+}
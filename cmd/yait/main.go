@@ -0,0 +1,86 @@
+// Command yait works with the projects, the sprints and the issues of the issue API from the terminal.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const usage = `usage: yait [--config file] [--profile name] [--server url] [--output table|json] <command> <subcommand>
+
+commands:
+  projects list | create
+  sprints list | create | current | start | close | burndown
+  issues list | create | view | move | assign | transition
+
+Run "yait <command> <subcommand> --help" for the flags of a subcommand.`
+
+func main() {
+	err := run(os.Args[1:], &terminal{in: os.Stdin, out: os.Stdout, errOut: os.Stderr, edit: editInEditor})
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "yait: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+// terminal holds the streams of a command, and the editor writing the descriptions
+type terminal struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+	edit   func(editor string, initial string) (string, error)
+}
+
+// run executes the command of args, the global flags are accepted before the command too
+func run(args []string, terminal *terminal) error {
+	globalFlags, args := splitGlobalFlags(args)
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	if args[0] == "help" {
+		fmt.Fprintln(terminal.out, usage)
+		return nil
+	}
+	if len(args) == 1 || strings.HasPrefix(args[1], "-") {
+		return fmt.Errorf("missing subcommand for \"%s\", %s", args[0], usage)
+	}
+
+	command, subcommand, args := args[0], args[1], append(globalFlags, args[2:]...)
+	switch command {
+	case "projects":
+		return runProjectsCommand(subcommand, args, terminal)
+	case "sprints":
+		return runSprintsCommand(subcommand, args, terminal)
+	case "issues":
+		return runIssuesCommand(subcommand, args, terminal)
+	default:
+		return fmt.Errorf("unknown command \"%s\", %s", command, usage)
+	}
+}
+
+// splitGlobalFlags moves the flags given before the command after it
+func splitGlobalFlags(args []string) ([]string, []string) {
+	globalFlags := []string{}
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		if args[0] == "-h" || args[0] == "--help" {
+			return globalFlags, []string{"help"}
+		}
+		// every global flag takes a value
+		takesValue := args[0] == "-o" || strings.HasPrefix(args[0], "--") && !strings.Contains(args[0], "=")
+		globalFlags = append(globalFlags, args[0])
+		args = args[1:]
+		if takesValue && len(args) > 0 {
+			globalFlags = append(globalFlags, args[0])
+			args = args[1:]
+		}
+	}
+	return globalFlags, args
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const descriptionTemplate = "# Write the description of the issue above, the lines starting with # are ignored."
+
+func newFlagSet(name string, terminal *terminal) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("yait "+name, pflag.ContinueOnError)
+	flags.SetOutput(terminal.errOut)
+	return flags
+}
+
+// print writes value as indented JSON, or calls printTable
+func (session *session) print(value interface{}, printTable func(writer *tabwriter.Writer)) error {
+	if session.profile.Output == "json" {
+		return printJSON(session.out, value)
+	}
+	writer := tabwriter.NewWriter(session.out, 0, 0, 2, ' ', 0)
+	printTable(writer)
+	return writer.Flush()
+}
+
+func printJSON(out io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return "-"
+	}
+	return date.Format("2006-01-02")
+}
+
+func formatText(text string) string {
+	if text == "" {
+		return "-"
+	}
+	return text
+}
+
+// parseDate reads a YYYY-MM-DD date, or a RFC 3339 date-time
+func parseDate(name string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s must be a YYYY-MM-DD date, got \"%s\"", name, value)
+	}
+	return date, nil
+}
+
+// getEditor returns the editor of the profile, or of the VISUAL and EDITOR environment variables
+func getEditor(editor string) string {
+	for _, candidate := range []string{editor, os.Getenv("VISUAL"), os.Getenv("EDITOR")} {
+		if candidate != "" {
+			return candidate
+		}
+	}
+	return "vi"
+}
+
+// editInEditor opens initial in editor and returns the saved text without the comment lines
+func editInEditor(editor string, initial string) (string, error) {
+	file, err := ioutil.TempFile("", "yait-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(initial + "\n\n" + descriptionTemplate + "\n")
+	file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	// the editor may be given with its arguments, such as "code --wait"
+	command := strings.Fields(getEditor(editor))
+	cmd := exec.Command(command[0], append(command[1:], file.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("running the editor %s: %w", command[0], err)
+	}
+
+	content, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return stripComments(string(content)), nil
+}
+
+func stripComments(content string) string {
+	lines := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
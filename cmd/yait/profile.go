@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"issue-service/client"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DEFAULT_SERVER is called when neither the flags nor the profile give a server
+	DEFAULT_SERVER = "http://localhost:8080"
+	// DEFAULT_PROFILE is used when neither the flags, YAIT_PROFILE nor the file choose a profile
+	DEFAULT_PROFILE = "default"
+)
+
+// profile holds the settings of a server, the project and the sprint are the defaults of the commands
+type profile struct {
+	Server  string `mapstructure:"server"`
+	Output  string `mapstructure:"output"`
+	Editor  string `mapstructure:"editor"`
+	Project int    `mapstructure:"project"`
+	Sprint  int    `mapstructure:"sprint"`
+}
+
+// profileFile is the yaml or json file of the profiles:
+//
+//	default_profile: work
+//	profiles:
+//	  work:
+//	    server: https://issues.example.com
+//	    project: 3
+type profileFile struct {
+	DefaultProfile string             `mapstructure:"default_profile"`
+	Profiles       map[string]profile `mapstructure:"profiles"`
+}
+
+// globalOptions are the flags of every subcommand
+type globalOptions struct {
+	config  string
+	profile string
+	server  string
+	output  string
+}
+
+func addGlobalFlags(flags *pflag.FlagSet) *globalOptions {
+	options := &globalOptions{}
+	flags.StringVar(&options.config, "config", "", "profile file, by default $YAIT_CONFIG or yait/config.yaml in the user configuration directory")
+	flags.StringVar(&options.profile, "profile", "", "profile of the file, by default $YAIT_PROFILE or the default_profile of the file")
+	flags.StringVar(&options.server, "server", "", "URL of the issue API, overriding the profile")
+	flags.StringVarP(&options.output, "output", "o", "", "output format, table or json")
+	return options
+}
+
+func getDefaultConfigFile() string {
+	if configFile := os.Getenv("YAIT_CONFIG"); configFile != "" {
+		return configFile
+	}
+	configDirectory, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDirectory, "yait", "config.yaml")
+}
+
+// loadProfile reads the profile chosen by options and applies the flags overriding it.
+// A missing file is only an error when it is given explicitly.
+func loadProfile(options *globalOptions) (profile, error) {
+	configFile, configFileRequired := options.config, options.config != ""
+	if configFile == "" {
+		configFile = getDefaultConfigFile()
+	}
+
+	file := profileFile{}
+	if _, err := os.Stat(configFile); err == nil || configFileRequired {
+		settings := viper.New()
+		settings.SetConfigFile(configFile)
+		if err := settings.ReadInConfig(); err != nil {
+			return profile{}, err
+		}
+		if err := settings.Unmarshal(&file); err != nil {
+			return profile{}, fmt.Errorf("invalid profile file %s: %w", configFile, err)
+		}
+	}
+
+	name, nameRequired := options.profile, true
+	if name == "" {
+		name = os.Getenv("YAIT_PROFILE")
+	}
+	if name == "" {
+		name, nameRequired = file.DefaultProfile, file.DefaultProfile != ""
+	}
+	if name == "" {
+		name = DEFAULT_PROFILE
+	}
+	selected, ok := file.Profiles[name]
+	if !ok && nameRequired {
+		return profile{}, fmt.Errorf("profile \"%s\" not found in %s", name, configFile)
+	}
+
+	if options.server != "" {
+		selected.Server = options.server
+	}
+	if selected.Server == "" {
+		selected.Server = DEFAULT_SERVER
+	}
+	if options.output != "" {
+		selected.Output = options.output
+	}
+	if selected.Output == "" {
+		selected.Output = "table"
+	}
+	if selected.Output != "table" && selected.Output != "json" {
+		return profile{}, fmt.Errorf("output must be table or json, got \"%s\"", selected.Output)
+	}
+	return selected, nil
+}
+
+// session is a subcommand connected to the API of its profile
+type session struct {
+	*terminal
+	profile profile
+	client  *client.Client
+}
+
+func newSession(options *globalOptions, terminal *terminal) (*session, error) {
+	selected, err := loadProfile(options)
+	if err != nil {
+		return nil, err
+	}
+	apiClient, err := client.NewClient(selected.Server, client.WithUserAgent("yait"))
+	if err != nil {
+		return nil, err
+	}
+	return &session{terminal: terminal, profile: selected, client: apiClient}, nil
+}
+
+// getProject returns the project of the flag, or of the profile
+func (session *session) getProject(project int) (int, error) {
+	if project == 0 {
+		project = session.profile.Project
+	}
+	if project <= 0 {
+		return 0, errors.New("--project is required when the profile has no project")
+	}
+	return project, nil
+}
+
+// getSprint returns the sprint of the flag, or of the profile
+func (session *session) getSprint(sprint int) (int, error) {
+	if sprint == 0 {
+		sprint = session.profile.Sprint
+	}
+	if sprint <= 0 {
+		return 0, errors.New("--sprint is required when the profile has no sprint")
+	}
+	return sprint, nil
+}
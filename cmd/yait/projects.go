@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"issue-service/app/issue-api/routes/models"
+)
+
+const projectsUsage = "usage: yait projects list | create --name name --type type [--client client] [--timezone zone] [--sprint-min-days days] [--sprint-max-days days] [--estimation-scale fibonacci|tshirt|hours]"
+
+func runProjectsCommand(subcommand string, args []string, terminal *terminal) error {
+	switch subcommand {
+	case "list":
+		return listProjects(args, terminal)
+	case "create":
+		return createProject(args, terminal)
+	default:
+		return fmt.Errorf("unknown projects command \"%s\", %s", subcommand, projectsUsage)
+	}
+}
+
+func listProjects(args []string, terminal *terminal) error {
+	flags := newFlagSet("projects list", terminal)
+	options := addGlobalFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+
+	projects, err := session.client.Projects(0).All(context.Background())
+	if err != nil {
+		return err
+	}
+	return session.print(projects, func(writer *tabwriter.Writer) {
+		fmt.Fprintln(writer, "ID\tNAME\tTYPE\tCLIENT")
+		for _, project := range projects {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", project.ID, project.Name, project.Type, formatText(project.Client))
+		}
+	})
+}
+
+func createProject(args []string, terminal *terminal) error {
+	flags := newFlagSet("projects create", terminal)
+	options := addGlobalFlags(flags)
+	var project models.CreateProjectRequest
+	flags.StringVar(&project.Name, "name", "", "name of the project, unique")
+	flags.StringVar(&project.Type, "type", "", "type of the project")
+	flags.StringVar(&project.Client, "client", "", "client of the project")
+	flags.StringVar(&project.Timezone, "timezone", "", "IANA timezone the days of the sprints are counted in, UTC by default")
+	flags.IntVar(&project.SprintMinDays, "sprint-min-days", 0, "minimum length of the sprints in days, 0 for no limit")
+	flags.IntVar(&project.SprintMaxDays, "sprint-max-days", 0, "maximum length of the sprints in days, 0 for no limit")
+	flags.StringVar(&project.EstimationScale, "estimation-scale", "", "scale of the story points, fibonacci, tshirt or hours, fibonacci by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if project.Name == "" || project.Type == "" {
+		return errors.New(projectsUsage)
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+
+	id, err := session.client.CreateProject(context.Background(), project)
+	if err != nil {
+		return err
+	}
+	return session.print(map[string]int{"id": id}, func(writer *tabwriter.Writer) {
+		fmt.Fprintf(writer, "Created project %d\n", id)
+	})
+}
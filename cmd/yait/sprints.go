@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"issue-service/app/issue-api/routes/models"
+
+	"github.com/spf13/pflag"
+)
+
+const sprintsUsage = `usage: yait sprints list [--project id]
+       yait sprints create [--project id] --number number --start date --end date [--max-issues count]
+       yait sprints current [--project id] [--date date]
+       yait sprints start [--project id] SPRINT
+       yait sprints close [--project id] [--to-sprint id] SPRINT
+       yait sprints burndown [--project id] [--unit points|issues] [--format csv|svg] SPRINT
+The unfinished issues of a closed sprint go to the --to-sprint sprint, or to the backlog`
+
+func runSprintsCommand(subcommand string, args []string, terminal *terminal) error {
+	switch subcommand {
+	case "list":
+		return listSprints(args, terminal)
+	case "create":
+		return createSprint(args, terminal)
+	case "current":
+		return currentSprint(args, terminal)
+	case "start":
+		return startSprint(args, terminal)
+	case "close":
+		return closeSprint(args, terminal)
+	case "burndown":
+		return burndownSprint(args, terminal)
+	default:
+		return fmt.Errorf("unknown sprints command \"%s\", %s", subcommand, sprintsUsage)
+	}
+}
+
+func listSprints(args []string, terminal *terminal) error {
+	flags := newFlagSet("sprints list", terminal)
+	options := addGlobalFlags(flags)
+	project := flags.Int("project", 0, "project of the sprints, by default the project of the profile")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+
+	sprints, err := session.client.Sprints(projectId, 0).All(context.Background())
+	if err != nil {
+		return err
+	}
+	// POINTS are the completed points out of the committed ones
+	return session.print(sprints, func(writer *tabwriter.Writer) {
+		fmt.Fprintln(writer, "ID\tNUMBER\tSTART\tEND\tCOMPLETED\tMAX ISSUES\tPOINTS")
+		for _, sprint := range sprints {
+			maxIssues := "-"
+			if sprint.MaxIssuePerSprint > 0 {
+				maxIssues = strconv.Itoa(sprint.MaxIssuePerSprint)
+			}
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%t\t%s\t%d/%d\n", sprint.ID, sprint.Number,
+				formatDate(sprint.StartDate), formatDate(sprint.EndDate), sprint.Completed, maxIssues,
+				sprint.CompletedPoints, sprint.CommittedPoints)
+		}
+	})
+}
+
+func createSprint(args []string, terminal *terminal) error {
+	flags := newFlagSet("sprints create", terminal)
+	options := addGlobalFlags(flags)
+	project := flags.Int("project", 0, "project of the sprint, by default the project of the profile")
+	var sprint models.CreateSprintRequest
+	flags.StringVar(&sprint.Number, "number", "", "number of the sprint, such as 2022-14")
+	start := flags.String("start", "", "start date, YYYY-MM-DD")
+	end := flags.String("end", "", "end date, YYYY-MM-DD")
+	flags.IntVar(&sprint.MaxIssuePerSprint, "max-issues", 0, "maximum number of issues, 0 for no limit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if sprint.Number == "" || *start == "" || *end == "" {
+		return errors.New(sprintsUsage)
+	}
+	var err error
+	if sprint.StartDate, err = parseDate("start", *start); err != nil {
+		return err
+	}
+	if sprint.EndDate, err = parseDate("end", *end); err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+
+	id, err := session.client.CreateSprint(context.Background(), projectId, sprint)
+	if err != nil {
+		return err
+	}
+	return session.print(map[string]int{"id": id}, func(writer *tabwriter.Writer) {
+		fmt.Fprintf(writer, "Created sprint %d in project %d\n", id, projectId)
+	})
+}
+
+func currentSprint(args []string, terminal *terminal) error {
+	flags := newFlagSet("sprints current", terminal)
+	options := addGlobalFlags(flags)
+	project := flags.Int("project", 0, "project of the sprint, by default the project of the profile")
+	date := flags.String("date", "", "day covered by the sprint, YYYY-MM-DD, today in the timezone of the project by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	day, err := parseDate("date", *date)
+	if err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+
+	sprint, err := session.client.GetCurrentSprint(context.Background(), projectId, day)
+	if err != nil {
+		return err
+	}
+	return session.print(sprint, func(writer *tabwriter.Writer) {
+		fmt.Fprintf(writer, "ID\t%d\n", sprint.ID)
+		fmt.Fprintf(writer, "NUMBER\t%s\n", sprint.Number)
+		fmt.Fprintf(writer, "START\t%s\n", formatDate(sprint.StartDate))
+		fmt.Fprintf(writer, "END\t%s\n", formatDate(sprint.EndDate))
+	})
+}
+
+// parseSprintArgument parses the flags of a subcommand taking a sprint
+func parseSprintArgument(flags *pflag.FlagSet, args []string) (int, error) {
+	if err := flags.Parse(args); err != nil {
+		return 0, err
+	}
+	if flags.NArg() != 1 {
+		return 0, errors.New(sprintsUsage)
+	}
+	sprintId, err := strconv.Atoi(flags.Arg(0))
+	if err != nil || sprintId <= 0 {
+		return 0, fmt.Errorf("the sprint must be a positive number, got \"%s\"", flags.Arg(0))
+	}
+	return sprintId, nil
+}
+
+func startSprint(args []string, terminal *terminal) error {
+	flags := newFlagSet("sprints start", terminal)
+	options := addGlobalFlags(flags)
+	project := flags.Int("project", 0, "project of the sprint, by default the project of the profile")
+	sprintId, err := parseSprintArgument(flags, args)
+	if err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+
+	sprint, err := session.client.StartSprint(context.Background(), projectId, sprintId)
+	if err != nil {
+		return err
+	}
+	return session.print(sprint, func(writer *tabwriter.Writer) {
+		fmt.Fprintf(writer, "Started sprint %d of project %d\n", sprintId, projectId)
+	})
+}
+
+func closeSprint(args []string, terminal *terminal) error {
+	flags := newFlagSet("sprints close", terminal)
+	options := addGlobalFlags(flags)
+	project := flags.Int("project", 0, "project of the sprint, by default the project of the profile")
+	nextSprint := flags.Int("to-sprint", 0, "sprint receiving the unfinished issues, by default the backlog")
+	sprintId, err := parseSprintArgument(flags, args)
+	if err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+
+	completion, err := session.client.CompleteSprint(context.Background(), projectId, sprintId, *nextSprint)
+	if err != nil {
+		return err
+	}
+	return session.print(completion, func(writer *tabwriter.Writer) {
+		destination := "the backlog"
+		if completion.NextSprintID != 0 {
+			destination = fmt.Sprintf("sprint %d", completion.NextSprintID)
+		}
+		fmt.Fprintf(writer, "Closed sprint %d of project %d\n", sprintId, projectId)
+		fmt.Fprintf(writer, "COMMITTED\t%d\n", len(completion.Committed))
+		fmt.Fprintf(writer, "ADDED\t%d\n", len(completion.Added))
+		fmt.Fprintf(writer, "COMPLETED\t%d\n", len(completion.Completed))
+		fmt.Fprintf(writer, "CARRIED OVER\t%d, to %s\n", len(completion.CarriedOver), destination)
+		fmt.Fprintf(writer, "REMOVED\t%d\n", len(completion.Removed))
+	})
+}
+
+func burndownSprint(args []string, terminal *terminal) error {
+	flags := newFlagSet("sprints burndown", terminal)
+	options := addGlobalFlags(flags)
+	project := flags.Int("project", 0, "project of the sprint, by default the project of the profile")
+	unit := flags.String("unit", "", "work counted, points or issues, points by default")
+	format := flags.String("format", "", "csv or svg to write the burndown as a CSV table or an SVG chart")
+	sprintId, err := parseSprintArgument(flags, args)
+	if err != nil {
+		return err
+	}
+	session, err := newSession(options, terminal)
+	if err != nil {
+		return err
+	}
+	projectId, err := session.getProject(*project)
+	if err != nil {
+		return err
+	}
+
+	if *format != "" {
+		chart, err := session.client.GetSprintBurndownChart(context.Background(), projectId, sprintId, *unit, *format)
+		if err != nil {
+			return err
+		}
+		_, err = session.out.Write(chart)
+		return err
+	}
+	burndown, err := session.client.GetSprintBurndown(context.Background(), projectId, sprintId, *unit)
+	if err != nil {
+		return err
+	}
+	return session.print(burndown, func(writer *tabwriter.Writer) {
+		formatWork := func(work *int) string {
+			if work == nil {
+				return "-"
+			}
+			return strconv.Itoa(*work)
+		}
+		fmt.Fprintln(writer, "DATE\tIDEAL\tREMAINING\tCOMPLETED\tSCOPE")
+		for _, day := range burndown.Days {
+			fmt.Fprintf(writer, "%s\t%g\t%s\t%s\t%s\n", day.Date, day.Ideal,
+				formatWork(day.Remaining), formatWork(day.Completed), formatWork(day.Scope))
+		}
+	})
+}
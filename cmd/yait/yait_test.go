@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"issue-service/app/issue-api/routes"
+	"issue-service/app/issue-api/webserver"
+	"issue-service/internal"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestProfile serves the API from memory and writes a profile file calling it
+func newTestProfile(t *testing.T) string {
+	logger := log.New()
+	logger.SetOutput(ioutil.Discard)
+	stores := internal.NewMemoryStores()
+	router, err := webserver.NewRouter(
+		stores,
+		routes.NewStatusRouter(time.Second),
+		internal.NewMetrics(),
+		internal.NewAccessLogger(logger, 100),
+		func(ctx context.Context, routeName string, problems []string) {
+			t.Errorf("%s response not matching the specification:\n%s", routeName, strings.Join(problems, "\n"))
+		},
+	)
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := fmt.Sprintf("default_profile: test\nprofiles:\n  test:\n    server: %s\n    editor: fake-editor\n", server.URL)
+	require.NoError(t, os.WriteFile(configFile, []byte(content), 0600))
+	return configFile
+}
+
+func runCommand(t *testing.T, configFile string, stdin string, args ...string) (string, error) {
+	out := &bytes.Buffer{}
+	terminal := &terminal{
+		in:     strings.NewReader(stdin),
+		out:    out,
+		errOut: ioutil.Discard,
+		edit: func(editor string, initial string) (string, error) {
+			require.Equal(t, "fake-editor", editor)
+			return initial + "\nwritten in the editor", nil
+		},
+	}
+	err := run(append(args, "--config", configFile), terminal)
+	return out.String(), err
+}
+
+func TestRun(t *testing.T) {
+	configFile := newTestProfile(t)
+	run := func(args ...string) string {
+		out, err := runCommand(t, configFile, "", args...)
+		require.NoError(t, err)
+		return out
+	}
+
+	require.Equal(t, "Created project 1\n", run("projects", "create", "--name", "tracker", "--type", "internal"))
+	require.Equal(t, "ID  NAME     TYPE      CLIENT\n1   tracker  internal  -\n", run("projects", "list"))
+
+	require.Equal(t, "Created sprint 1 in project 1\n", run("sprints", "create", "--project", "1", "--number", "1", "--start", "2022-10-03", "--end", "2022-10-14"))
+	require.Equal(t, "Created sprint 2 in project 1\n", run("-o", "table", "sprints", "create", "--project", "1", "--number", "2",
+		"--start", "2022-10-17", "--end", "2022-10-28"))
+	require.Equal(t, `ID  NUMBER  START       END         COMPLETED  MAX ISSUES  POINTS
+1   1       2022-10-03  2022-10-14  false      -           0/0
+2   2       2022-10-17  2022-10-28  false      -           0/0
+`, run("sprints", "list", "--project", "1"))
+	require.Equal(t, "ID      2\nNUMBER  2\nSTART   2022-10-17\nEND     2022-10-28\n", run("sprints", "current", "--project", "1", "--date", "2022-10-20"))
+
+	out, err := runCommand(t, configFile, "Steps to reproduce\n", "issues", "create", "--project", "1", "--sprint", "1",
+		"--type", "Bug", "--title", "Crash on login", "--description", "-", "--edit")
+	require.NoError(t, err)
+	require.Equal(t, "Created issue 1-1-1\n", out)
+
+	require.Equal(t, "KEY    TYPE  STATUS  ASSIGNEE  TITLE\n1-1-1  Bug   -       -         Crash on login\n",
+		run("issues", "list", "--project", "1", "--sprint", "1"))
+
+	out = run("issues", "assign", "1-1-1", "alice")
+	require.Contains(t, out, "ASSIGNEE  alice\n")
+	require.True(t, strings.HasSuffix(out, "\nSteps to reproduce\nwritten in the editor\n"), out)
+
+	out = run("--output", "json", "issues", "transition", "1", "--project", "1", "--sprint", "1", "Done")
+	var issue map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &issue))
+	require.Equal(t, "Done", issue["status"])
+	require.Equal(t, "alice", issue["assignee"])
+
+	out = run("issues", "move", "1-1-1", "--to-sprint", "2")
+	require.Contains(t, out, "KEY       1-2-1\n")
+
+	out = run("issues", "view", "1-2-1", "-o", "json")
+	require.NoError(t, json.Unmarshal([]byte(out), &issue))
+	require.Equal(t, float64(2), issue["sprintId"])
+
+	require.Equal(t, "Created issue 1-1-2\n", run("issues", "create", "--project", "1", "--sprint", "1", "--type", "Task", "--title", "Slow search", "--points", "5"))
+	require.Contains(t, run("issues", "view", "1-1-2"), "POINTS    5\n")
+	require.Equal(t, "Started sprint 1 of project 1\n", run("sprints", "start", "--project", "1", "1"))
+	require.True(t, strings.HasPrefix(run("sprints", "burndown", "--project", "1", "1"), "DATE        IDEAL  REMAINING  COMPLETED  SCOPE\n2022-10-03  4.58   5          0          5\n"))
+	require.True(t, strings.HasPrefix(run("sprints", "burndown", "--project", "1", "--format", "svg", "1"), "<svg "))
+	require.Equal(t, `Closed sprint 1 of project 1
+COMMITTED     1
+ADDED         0
+COMPLETED     0
+CARRIED OVER  1, to sprint 2
+REMOVED       0
+`, run("sprints", "close", "--project", "1", "--to-sprint", "2", "1"))
+	require.Contains(t, run("sprints", "list", "--project", "1"), "1   1       2022-10-03  2022-10-14  true       -           0/5")
+	require.Contains(t, run("issues", "list", "--project", "1", "--sprint", "2"), "1-2-2  Task  -       -         Slow search\n")
+}
+
+func TestRunErrors(t *testing.T) {
+	configFile := newTestProfile(t)
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{"unknown command", []string{"users", "list"}, "unknown command \"users\""},
+		{"unknown subcommand", []string{"issues", "delete"}, "unknown issues command \"delete\""},
+		{"missing subcommand", []string{"issues"}, "missing subcommand for \"issues\""},
+		{"missing project", []string{"sprints", "list"}, "--project is required when the profile has no project"},
+		{"invalid key", []string{"issues", "view", "1-2"}, "invalid issue key \"1-2\""},
+		{"unknown profile", []string{"projects", "list", "--profile", "production"}, "profile \"production\" not found"},
+		{"invalid output", []string{"projects", "list", "-o", "yaml"}, "output must be table or json, got \"yaml\""},
+		{"invalid date", []string{"sprints", "create", "--number", "1", "--start", "monday", "--end", "2022-10-14"}, "--start must be a YYYY-MM-DD date"},
+		{"API error", []string{"issues", "view", "1-1-1"}, "issue API error 404"},
+		{"missing end date", []string{"sprints", "create", "--project", "1", "--number", "1", "--start", "2022-10-03"}, "usage: yait sprints"},
+		{"invalid sprint", []string{"sprints", "close", "--project", "1", "first"}, "the sprint must be a positive number, got \"first\""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := runCommand(t, configFile, "", testCase.args...)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), testCase.expectedError)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package internal
+
+// The build information is set by the Dockerfile with
+// -ldflags "-X issue-service/internal.Version=... -X issue-service/internal.CommitSha=... -X issue-service/internal.BuildTime=..."
+var (
+	Version   = "dev"
+	CommitSha = "unknown"
+	BuildTime = "unknown"
+)
@@ -4,15 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"issue-service/app/issue-api/cfg"
 	models "issue-service/app/issue-api/routes/models"
 	"math/rand"
 	"net/http"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/spf13/viper"
 )
 
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -25,24 +24,6 @@ func GetRandomStringName(length int) string {
 	return string(b)
 }
 
-func GetConfig(path string) (cfg.EnvConfiguration, error) {
-	viper.SetConfigFile(path)
-
-	if err := viper.ReadInConfig(); err != nil {
-
-		return cfg.EnvConfiguration{}, err
-	}
-
-	var config cfg.EnvConfiguration
-
-	if err := viper.Unmarshal(&config); err != nil {
-		log.Fatal("Error reading env configuration")
-		return cfg.EnvConfiguration{}, err
-	}
-
-	return config, nil
-}
-
 func LogAndReturnErrorResponse(err error, w http.ResponseWriter) {
 	var errorResponse *models.ErrorResponse
 	errors.As(err, &errorResponse)
@@ -76,6 +57,33 @@ func ValidateRequest(inputRequest interface{}) error {
 	return nil
 }
 
+// GetPageFromRequest reads the limit and offset query parameters, a missing limit selects every item
+func GetPageFromRequest(r *http.Request) (models.Page, error) {
+	var page models.Page
+	query := r.URL.Query()
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > models.MAX_PAGE_LIMIT {
+			return models.Page{}, &models.ErrorResponse{
+				ErrorMessage: fmt.Sprintf("limit must be between 1 and %d", models.MAX_PAGE_LIMIT),
+				ErrorCode:    400,
+			}
+		}
+		page.Limit = value
+	}
+	if offset := query.Get("offset"); offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			return models.Page{}, &models.ErrorResponse{
+				ErrorMessage: "offset must be a positive integer",
+				ErrorCode:    400,
+			}
+		}
+		page.Offset = value
+	}
+	return page, nil
+}
+
 func GetCreateResponseBody(id uint) ([]byte, error) {
 	response := models.CreateResponse{Id: fmt.Sprint(id)}
 
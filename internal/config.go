@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"issue-service/app/issue-api/cfg"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// DEFAULT_CONFIG_FILE is read when it exists and no --config flag is given
+const DEFAULT_CONFIG_FILE = ".env"
+
+const redactedValue = "********"
+
+var logLevels = []string{"trace", "debug", "info", "warning", "error", "fatal", "panic"}
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// ConfigError lists every missing or invalid setting
+type ConfigError struct {
+	Problems []string
+}
+
+func (err *ConfigError) Error() string {
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(err.Problems, "; "))
+}
+
+func getFlagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+func newConfigFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("issue-service", pflag.ContinueOnError)
+	flags.String("config", DEFAULT_CONFIG_FILE, "optional configuration file, .env, yaml or json")
+	for _, setting := range cfg.Settings {
+		switch defaultValue := setting.Default.(type) {
+		case bool:
+			flags.Bool(getFlagName(setting.Key), defaultValue, setting.Description)
+		case int:
+			flags.Int(getFlagName(setting.Key), defaultValue, setting.Description)
+		case time.Duration:
+			flags.Duration(getFlagName(setting.Key), defaultValue, setting.Description)
+		default:
+			flags.String(getFlagName(setting.Key), fmt.Sprint(defaultValue), setting.Description)
+		}
+	}
+	return flags
+}
+
+// LoadConfig builds the configuration from, by decreasing priority, the command-line flags,
+// the environment variables, the configuration file and the defaults of cfg.Settings.
+// It returns the arguments left after the flags.
+func LoadConfig(arguments []string) (cfg.EnvConfiguration, []string, error) {
+	flags := newConfigFlagSet()
+	if err := flags.Parse(arguments); err != nil {
+		return cfg.EnvConfiguration{}, nil, err
+	}
+
+	configFile, _ := flags.GetString("config")
+	config, err := readConfig(flags, configFile, flags.Changed("config"))
+	return config, flags.Args(), err
+}
+
+// GetConfig reads the configuration with the file at path, the file is optional
+func GetConfig(path string) (cfg.EnvConfiguration, error) {
+	return readConfig(newConfigFlagSet(), path, false)
+}
+
+func readConfig(flags *pflag.FlagSet, configFile string, configFileRequired bool) (cfg.EnvConfiguration, error) {
+	settings := viper.New()
+	for _, setting := range cfg.Settings {
+		settings.SetDefault(setting.Key, setting.Default)
+		if err := settings.BindPFlag(setting.Key, flags.Lookup(getFlagName(setting.Key))); err != nil {
+			return cfg.EnvConfiguration{}, err
+		}
+	}
+	settings.AutomaticEnv()
+
+	if _, err := os.Stat(configFile); err == nil || configFileRequired {
+		settings.SetConfigFile(configFile)
+		if err := settings.ReadInConfig(); err != nil {
+			return cfg.EnvConfiguration{}, err
+		}
+	}
+
+	var config cfg.EnvConfiguration
+	if err := settings.Unmarshal(&config); err != nil {
+		return cfg.EnvConfiguration{}, err
+	}
+	return config, nil
+}
+
+// ValidateConfig returns a *ConfigError listing every problem, not only the first one
+func ValidateConfig(config cfg.EnvConfiguration) error {
+	problems := []string{}
+
+	if port, err := strconv.Atoi(config.HTTP_PORT); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Sprintf("HTTP_PORT must be a port number, got \"%s\"", config.HTTP_PORT))
+	}
+	if !isOneOf(config.LOG_LEVEL, logLevels) {
+		problems = append(problems, fmt.Sprintf("LOG_LEVEL must be one of %s, got \"%s\"", strings.Join(logLevels, ", "), config.LOG_LEVEL))
+	}
+	if config.LOG_ACCESS_SAMPLE_PERCENT < 0 || config.LOG_ACCESS_SAMPLE_PERCENT > 100 {
+		problems = append(problems, fmt.Sprintf("LOG_ACCESS_SAMPLE_PERCENT must be between 0 and 100, got %d", config.LOG_ACCESS_SAMPLE_PERCENT))
+	}
+
+	switch config.DATABASE_DRIVER {
+	case DRIVER_POSTGRES:
+		required := []struct{ key, value string }{
+			{"DATABASE_HOST", config.DATABASE_HOST},
+			{"DATABASE_NAME", config.DATABASE_NAME},
+			{"DATABASE_USERNAME", config.DATABASE_USERNAME},
+		}
+		for _, setting := range required {
+			if setting.value == "" {
+				problems = append(problems, fmt.Sprintf("%s is required by the postgres driver", setting.key))
+			}
+		}
+		if port, err := strconv.Atoi(config.DATABASE_PORT); err != nil || port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("DATABASE_PORT must be a port number, got \"%s\"", config.DATABASE_PORT))
+		}
+		if !isOneOf(config.DATABASE_SSLMODE, sslModes) {
+			problems = append(problems, fmt.Sprintf("DATABASE_SSLMODE must be one of %s, got \"%s\"", strings.Join(sslModes, ", "), config.DATABASE_SSLMODE))
+		}
+	case DRIVER_SQLITE:
+		if config.DATABASE_NAME == "" {
+			problems = append(problems, "DATABASE_NAME is required by the sqlite driver")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("DATABASE_DRIVER must be postgres or sqlite, got \"%s\"", config.DATABASE_DRIVER))
+	}
+
+	if !isOneOf(config.TRACING_EXPORTER, tracingExporters) {
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be one of %s, got \"%s\"", strings.Join(tracingExporters, ", "), config.TRACING_EXPORTER))
+	}
+	if config.TRACING_EXPORTER == TRACING_EXPORTER_OTLP && config.TRACING_OTLP_ENDPOINT == "" {
+		problems = append(problems, "TRACING_OTLP_ENDPOINT is required by the otlp exporter")
+	}
+
+	if config.DATABASE_CONNECT_ATTEMPTS < 1 {
+		problems = append(problems, fmt.Sprintf("DATABASE_CONNECT_ATTEMPTS must be at least 1, got %d", config.DATABASE_CONNECT_ATTEMPTS))
+	}
+	nonNegative := []struct {
+		key   string
+		value int64
+	}{
+		{"HTTP_READ_TIMEOUT", int64(config.HTTP_READ_TIMEOUT)},
+		{"HTTP_READ_HEADER_TIMEOUT", int64(config.HTTP_READ_HEADER_TIMEOUT)},
+		{"HTTP_WRITE_TIMEOUT", int64(config.HTTP_WRITE_TIMEOUT)},
+		{"HTTP_IDLE_TIMEOUT", int64(config.HTTP_IDLE_TIMEOUT)},
+		{"HTTP_SHUTDOWN_DELAY", int64(config.HTTP_SHUTDOWN_DELAY)},
+		{"HTTP_SHUTDOWN_TIMEOUT", int64(config.HTTP_SHUTDOWN_TIMEOUT)},
+		{"HTTP_READINESS_TIMEOUT", int64(config.HTTP_READINESS_TIMEOUT)},
+		{"DATABASE_MAX_OPEN_CONNS", int64(config.DATABASE_MAX_OPEN_CONNS)},
+		{"DATABASE_MAX_IDLE_CONNS", int64(config.DATABASE_MAX_IDLE_CONNS)},
+		{"DATABASE_CONNECT_TIMEOUT", int64(config.DATABASE_CONNECT_TIMEOUT)},
+		{"DATABASE_CONN_MAX_LIFETIME", int64(config.DATABASE_CONN_MAX_LIFETIME)},
+		{"DATABASE_STATEMENT_TIMEOUT", int64(config.DATABASE_STATEMENT_TIMEOUT)},
+		{"SLA_CHECK_INTERVAL", int64(config.SLA_CHECK_INTERVAL)},
+	}
+	for _, setting := range nonNegative {
+		if setting.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s must not be negative", setting.key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+func isOneOf(value string, allowed []string) bool {
+	for _, allowedValue := range allowed {
+		if value == allowedValue {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintConfig writes the effective configuration in the .env format, secrets are redacted
+func PrintConfig(config cfg.EnvConfiguration, out io.Writer) error {
+	value := reflect.ValueOf(config)
+	for _, setting := range cfg.Settings {
+		field := value.FieldByName(setting.Key)
+		if !field.IsValid() {
+			return errors.New("unknown setting " + setting.Key)
+		}
+
+		printed := fmt.Sprint(field.Interface())
+		if setting.Secret && printed != "" {
+			printed = redactedValue
+		}
+		if _, err := fmt.Fprintf(out, "%s=%s\n", setting.Key, printed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"bytes"
+	"errors"
+	"issue-service/app/issue-api/cfg"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig(testCase *testing.T) {
+	testCase.Run("a configuration file given by flag must exist", func(t *testing.T) {
+		_, _, err := LoadConfig([]string{"--config", filepath.Join(t.TempDir(), "missing.env")})
+		require.Error(t, err)
+	})
+
+	testCase.Run("defaults without a configuration file", func(t *testing.T) {
+		config, arguments, err := LoadConfig([]string{"migrate", "up"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"migrate", "up"}, arguments)
+		require.Equal(t, "8080", config.HTTP_PORT)
+		require.Equal(t, DRIVER_POSTGRES, config.DATABASE_DRIVER)
+		require.Equal(t, false, config.DATABASE_MIGRATE_ON_STARTUP)
+	})
+
+	testCase.Run("flags override environment variables, which override the file", func(t *testing.T) {
+		path := writeConfigFile(t, "test.env", "HTTP_PORT=1000\nLOG_LEVEL=debug\nDATABASE_HOST=file-host\n")
+		t.Setenv("HTTP_PORT", "2000")
+		t.Setenv("DATABASE_MIGRATE_ON_STARTUP", "true")
+
+		config, _, err := LoadConfig([]string{"--config", path, "--http-port", "3000"})
+		require.NoError(t, err)
+		require.Equal(t, "3000", config.HTTP_PORT)
+		require.Equal(t, "debug", config.LOG_LEVEL)
+		require.Equal(t, "file-host", config.DATABASE_HOST)
+		require.Equal(t, true, config.DATABASE_MIGRATE_ON_STARTUP)
+	})
+
+	testCase.Run("yaml configuration file", func(t *testing.T) {
+		path := writeConfigFile(t, "config.yaml", "DATABASE_DRIVER: sqlite\nDATABASE_NAME: issue.db\n")
+
+		config, _, err := LoadConfig([]string{"--config", path})
+		require.NoError(t, err)
+		require.Equal(t, DRIVER_SQLITE, config.DATABASE_DRIVER)
+		require.Equal(t, "issue.db", config.DATABASE_NAME)
+	})
+
+	testCase.Run("GetConfig does not require the file", func(t *testing.T) {
+		config, err := GetConfig(filepath.Join(t.TempDir(), ".env"))
+		require.NoError(t, err)
+		require.Equal(t, "info", config.LOG_LEVEL)
+	})
+}
+
+func TestValidateConfig(testCase *testing.T) {
+	testCase.Run("the defaults are valid", func(t *testing.T) {
+		config, err := GetConfig(filepath.Join(t.TempDir(), ".env"))
+		require.NoError(t, err)
+		require.Equal(t, nil, ValidateConfig(config))
+	})
+
+	testCase.Run("every problem is listed", func(t *testing.T) {
+		err := ValidateConfig(cfg.EnvConfiguration{
+			HTTP_PORT:                 "http",
+			LOG_LEVEL:                 "verbose",
+			LOG_ACCESS_SAMPLE_PERCENT: 101,
+			DATABASE_DRIVER:           DRIVER_POSTGRES,
+			DATABASE_NAME:             "issue",
+			DATABASE_PORT:             "5432",
+			DATABASE_SSLMODE:          "always",
+			DATABASE_CONNECT_ATTEMPTS: 1,
+			DATABASE_MAX_IDLE_CONNS:   -1,
+			TRACING_EXPORTER:          TRACING_EXPORTER_OTLP,
+		})
+
+		var configError *ConfigError
+		require.True(t, errors.As(err, &configError))
+		require.Equal(t, []string{
+			"HTTP_PORT must be a port number, got \"http\"",
+			"LOG_LEVEL must be one of trace, debug, info, warning, error, fatal, panic, got \"verbose\"",
+			"LOG_ACCESS_SAMPLE_PERCENT must be between 0 and 100, got 101",
+			"DATABASE_HOST is required by the postgres driver",
+			"DATABASE_USERNAME is required by the postgres driver",
+			"DATABASE_SSLMODE must be one of disable, allow, prefer, require, verify-ca, verify-full, got \"always\"",
+			"TRACING_OTLP_ENDPOINT is required by the otlp exporter",
+			"DATABASE_MAX_IDLE_CONNS must not be negative",
+		}, configError.Problems)
+	})
+
+	testCase.Run("unknown driver", func(t *testing.T) {
+		err := ValidateConfig(cfg.EnvConfiguration{HTTP_PORT: "8080", LOG_LEVEL: "info", DATABASE_DRIVER: "mysql", DATABASE_CONNECT_ATTEMPTS: 1, TRACING_EXPORTER: TRACING_EXPORTER_NONE})
+		require.EqualError(t, err, "invalid configuration: DATABASE_DRIVER must be postgres or sqlite, got \"mysql\"")
+	})
+}
+
+func TestPrintConfig(t *testing.T) {
+	var out bytes.Buffer
+	err := PrintConfig(cfg.EnvConfiguration{
+		HTTP_PORT:         "8080",
+		DATABASE_DRIVER:   DRIVER_POSTGRES,
+		DATABASE_PASSWORD: "veryStrongPSW123",
+	}, &out)
+
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "HTTP_PORT=8080\n")
+	require.Contains(t, out.String(), "DATABASE_PASSWORD=********\n")
+	require.Contains(t, out.String(), "DATABASE_MIGRATE_ON_STARTUP=false\n")
+	require.NotContains(t, out.String(), "veryStrongPSW123")
+}
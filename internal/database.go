@@ -1,71 +1,384 @@
 package internal
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"math"
 	"strings"
+	"time"
 
 	"issue-service/app/issue-api/cfg"
 	models "issue-service/app/issue-api/routes/models"
 
+	sqliteDriver "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"github.com/jackc/pgconn"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	sqliteCodes "modernc.org/sqlite/lib"
 )
 
-var DATABASE_ERROR = map[string]string{
-	"DUPLICATE_KEY_ERROR": "duplicate key value violates unique constraint",
-	"FOREIGN_KEY_ERROR":   "violates foreign key constraint",
+const (
+	DRIVER_POSTGRES = "postgres"
+	DRIVER_SQLITE   = "sqlite"
+)
+
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	postgresUniqueViolation     = "23505"
+	postgresForeignKeyViolation = "23503"
+)
+
+var (
+	ErrNotFound     = errors.New("record not found")
+	ErrDuplicateKey = errors.New("duplicate key")
+	ErrForeignKey   = errors.New("foreign key violation")
+	ErrWipLimit     = errors.New("work in progress limit reached")
+	ErrOverlap      = errors.New("sprint dates overlap")
+)
+
+// quoteConnectionValue quotes a value of a libpq keyword/value connection string
+func quoteConnectionValue(value string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	return fmt.Sprintf("'%s'", escaped)
 }
 
 func getConnectionString(config cfg.EnvConfiguration) string {
-	return fmt.Sprintf("host=%s user=%s password=%s port=5432 dbname=%s",
-		config.DATABASE_HOST,
-		config.DATABASE_USERNAME,
-		config.DATABASE_PASSWORD,
-		config.DATABASE_NAME,
-	)
+	parameters := []string{
+		"host=" + quoteConnectionValue(config.DATABASE_HOST),
+		"user=" + quoteConnectionValue(config.DATABASE_USERNAME),
+		"password=" + quoteConnectionValue(config.DATABASE_PASSWORD),
+		"port=" + quoteConnectionValue(config.DATABASE_PORT),
+		"dbname=" + quoteConnectionValue(config.DATABASE_NAME),
+	}
+
+	optionalParameters := []struct{ name, value string }{
+		{"sslmode", config.DATABASE_SSLMODE},
+		{"sslrootcert", config.DATABASE_SSLROOTCERT},
+		{"sslcert", config.DATABASE_SSLCERT},
+		{"sslkey", config.DATABASE_SSLKEY},
+	}
+	for _, parameter := range optionalParameters {
+		if parameter.value != "" {
+			parameters = append(parameters, parameter.name+"="+quoteConnectionValue(parameter.value))
+		}
+	}
+
+	// connect_timeout is in seconds, a timeout below one second would disable it
+	if config.DATABASE_CONNECT_TIMEOUT > 0 {
+		seconds := int(math.Ceil(config.DATABASE_CONNECT_TIMEOUT.Seconds()))
+		parameters = append(parameters, fmt.Sprintf("connect_timeout=%d", seconds))
+	}
+	if config.DATABASE_STATEMENT_TIMEOUT > 0 {
+		parameters = append(parameters, fmt.Sprintf("statement_timeout=%d", config.DATABASE_STATEMENT_TIMEOUT.Milliseconds()))
+	}
+	return strings.Join(parameters, " ")
+}
+
+// getSqliteConnectionString uses DATABASE_NAME as the path of the database file.
+// Transactions take the write lock when they begin, so that they wait on busy_timeout
+// instead of failing when two writers race.
+func getSqliteConnectionString(config cfg.EnvConfiguration) string {
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_txlock=immediate", config.DATABASE_NAME)
+}
+
+func getDatabaseDriver(config cfg.EnvConfiguration) string {
+	if config.DATABASE_DRIVER == "" {
+		return DRIVER_POSTGRES
+	}
+	return config.DATABASE_DRIVER
+}
+
+func getDialector(config cfg.EnvConfiguration) (gorm.Dialector, error) {
+	switch getDatabaseDriver(config) {
+	case DRIVER_POSTGRES:
+		return postgres.Open(getConnectionString(config)), nil
+	case DRIVER_SQLITE:
+		return sqlite.Open(getSqliteConnectionString(config)), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver \"%s\"", config.DATABASE_DRIVER)
+	}
 }
 
+// connectRetryDelay is the wait before the second connection attempt, it doubles up to maxConnectRetryDelay
+var connectRetryDelay = 500 * time.Millisecond
+
+const maxConnectRetryDelay = 10 * time.Second
+
+// ConnectDatabase opens the database, retrying up to DATABASE_CONNECT_ATTEMPTS times
+// with an exponential backoff, and applies the connection pool settings
 func ConnectDatabase(config cfg.EnvConfiguration) (*gorm.DB, error) {
-	dsn := getConnectionString(config)
+	dialector, err := getDialector(config)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := config.DATABASE_CONNECT_ATTEMPTS
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var database *gorm.DB
+	delay := connectRetryDelay
+	for attempt := 1; ; attempt++ {
+		database, err = gorm.Open(dialector, &gorm.Config{})
+		if err == nil {
+			break
+		}
+		CloseDatabase(database)
+		if attempt == attempts {
+			return nil, fmt.Errorf("connecting to database after %d attempt(s): %w", attempts, err)
+		}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+		log.WithField("attempt", attempt).Warn(fmt.Sprintf("Error connecting to database, retrying in %s: %s", delay, err.Error()))
+		time.Sleep(delay)
+		delay *= 2
+		if delay > maxConnectRetryDelay {
+			delay = maxConnectRetryDelay
+		}
+	}
 
+	sqlDatabase, err := database.DB()
 	if err != nil {
-		log.Fatalf("Error connecting to database %s", err.Error())
-		return &gorm.DB{}, err
+		return nil, err
+	}
+	sqlDatabase.SetMaxOpenConns(config.DATABASE_MAX_OPEN_CONNS)
+	if config.DATABASE_MAX_IDLE_CONNS > 0 {
+		sqlDatabase.SetMaxIdleConns(config.DATABASE_MAX_IDLE_CONNS)
+	}
+	sqlDatabase.SetConnMaxLifetime(config.DATABASE_CONN_MAX_LIFETIME)
+
+	if err := database.Use(&TracingPlugin{}); err != nil {
+		return nil, err
 	}
-	return db, nil
+	return database, nil
 }
 
-func dropTables(database *gorm.DB) {
-	database.Migrator().DropTable(&models.Project{})
-	database.Migrator().DropTable(&models.Sprint{})
-	database.Migrator().DropTable(&models.Issue{})
+// CloseDatabase closes every connection of the pool
+func CloseDatabase(database *gorm.DB) error {
+	if database == nil || database.ConnPool == nil {
+		return nil
+	}
+	sqlDatabase, err := database.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDatabase.Close()
 }
 
-func automigrateDatabaseSchema(database *gorm.DB) {
-	database.AutoMigrate(&models.Project{})
-	database.AutoMigrate(&models.Sprint{})
-	database.AutoMigrate(&models.Issue{})
+func IsDuplicateKeyError(databaseError error) bool {
+	return errors.Is(databaseError, ErrDuplicateKey)
 }
 
-func resetSequenceId(database *gorm.DB) {
-	database.Exec("ALTER SEQUENCE projects_id_seq RESTART WITH 1")
-	database.Exec("ALTER SEQUENCE sprints_id_seq RESTART WITH 1")
-	database.Exec("ALTER SEQUENCE issues_id_seq RESTART WITH 1")
+func IsForeignKeyError(databaseError error) bool {
+	return errors.Is(databaseError, ErrForeignKey)
 }
 
-func SetupAndResetDatabase(database *gorm.DB) {
-	dropTables(database)
-	automigrateDatabaseSchema(database)
-	resetSequenceId(database)
+// classifyDatabaseError maps the driver specific error codes to the store sentinel errors,
+// it returns nil when the error is not a constraint violation
+func classifyDatabaseError(driver string, databaseError error) error {
+	switch driver {
+	case DRIVER_POSTGRES:
+		var postgresError *pgconn.PgError
+		if errors.As(databaseError, &postgresError) {
+			switch postgresError.Code {
+			case postgresUniqueViolation:
+				return ErrDuplicateKey
+			case postgresForeignKeyViolation:
+				return ErrForeignKey
+			}
+		}
+	case DRIVER_SQLITE:
+		var sqliteError *sqliteDriver.Error
+		if errors.As(databaseError, &sqliteError) {
+			switch sqliteError.Code() {
+			case sqliteCodes.SQLITE_CONSTRAINT_UNIQUE, sqliteCodes.SQLITE_CONSTRAINT_PRIMARYKEY:
+				return ErrDuplicateKey
+			case sqliteCodes.SQLITE_CONSTRAINT_FOREIGNKEY:
+				return ErrForeignKey
+			}
+			// with INSERT ... RETURNING the deferred foreign key check surfaces as a generic SQLITE_ERROR
+			if sqliteError.Code() == sqliteCodes.SQLITE_ERROR && strings.Contains(sqliteError.Error(), "FOREIGN KEY constraint failed") {
+				return ErrForeignKey
+			}
+		}
+	}
+	return nil
 }
 
-func IsDuplicateKeyError(databaseError error) bool {
-	return strings.Contains(databaseError.Error(), DATABASE_ERROR["DUPLICATE_KEY_ERROR"])
+// translateDatabaseError wraps the error of a query result with the store sentinel errors
+func translateDatabaseError(result *gorm.DB) error {
+	if result.Error == nil {
+		return nil
+	}
+
+	if sentinel := classifyDatabaseError(result.Dialector.Name(), result.Error); sentinel != nil {
+		return fmt.Errorf("%w: %s", sentinel, result.Error.Error())
+	}
+	return result.Error
 }
 
-func IsForeignKeyError(databaseError error) bool {
-	return strings.Contains(databaseError.Error(), DATABASE_ERROR["FOREIGN_KEY_ERROR"])
+func GetProjectById(ctx context.Context, stores models.Stores, projectId int) (models.Project, error) {
+	project, err := stores.Projects.Get(ctx, projectId)
+	if errors.Is(err, ErrNotFound) {
+		return models.Project{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Project with id \"%d\" does not exists", projectId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return models.Project{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return project, nil
+}
+
+// GetProjectSprint returns the sprint only if it belongs to the given project
+func GetProjectSprint(ctx context.Context, stores models.Stores, projectId int, sprintId int) (models.Sprint, error) {
+	if _, err := GetProjectById(ctx, stores, projectId); err != nil {
+		return models.Sprint{}, err
+	}
+
+	sprint, err := stores.Sprints.Get(ctx, projectId, sprintId)
+	if errors.Is(err, ErrNotFound) {
+		return models.Sprint{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Sprint with id \"%d\" does not exists", sprintId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return models.Sprint{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return sprint, nil
+}
+
+// GetSprintIssue returns the issue only if it belongs to the given project and sprint
+func GetSprintIssue(ctx context.Context, stores models.Stores, projectId int, sprintId int, issueId uint) (models.Issue, error) {
+	if _, err := GetProjectSprint(ctx, stores, projectId, sprintId); err != nil {
+		return models.Issue{}, err
+	}
+
+	issue, err := stores.Issues.Get(ctx, projectId, sprintId, issueId)
+	if errors.Is(err, ErrNotFound) {
+		return models.Issue{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" does not exists", issueId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return models.Issue{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return issue, nil
+}
+
+// GetBacklogIssue returns the issue only if it is in the backlog of the given project
+func GetBacklogIssue(ctx context.Context, stores models.Stores, projectId int, issueId uint) (models.Issue, error) {
+	if _, err := GetProjectById(ctx, stores, projectId); err != nil {
+		return models.Issue{}, err
+	}
+
+	issue, err := stores.Issues.Get(ctx, projectId, 0, issueId)
+	if errors.Is(err, ErrNotFound) {
+		return models.Issue{}, &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" is not in the backlog", issueId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return models.Issue{}, &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	return issue, nil
+}
+
+// GetDatabaseStatusChecks returns the readiness checks of the database:
+// the connection answers a ping and no migration is pending
+func GetDatabaseStatusChecks(database *gorm.DB) []models.StatusCheck {
+	return []models.StatusCheck{
+		{
+			Name: "database",
+			Check: func(ctx context.Context) (string, error) {
+				sqlDatabase, err := database.DB()
+				if err != nil {
+					return "", err
+				}
+				if err := sqlDatabase.PingContext(ctx); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s, %d open connection(s)", database.Dialector.Name(), sqlDatabase.Stats().OpenConnections), nil
+			},
+		},
+		{
+			Name: "migrations",
+			Check: func(ctx context.Context) (string, error) {
+				statuses, err := GetMigrationStatus(database.WithContext(ctx))
+				if err != nil {
+					return "", err
+				}
+
+				pending := []string{}
+				for _, status := range statuses {
+					if !status.Applied() {
+						pending = append(pending, fmt.Sprintf("%04d_%s", status.Version, status.Name))
+					}
+				}
+				if len(pending) > 0 {
+					return "", fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+				}
+				return fmt.Sprintf("%d applied", len(statuses)), nil
+			},
+		},
+	}
+}
+
+// GetMoveRank returns the rank placing the issue right after afterIssueId, or right before beforeIssueId, among the issues
+// of the project. It returns an empty rank, the issue keeping its rank, when both are 0.
+func GetMoveRank(ctx context.Context, stores models.Stores, projectId int, issueId uint, afterIssueId uint, beforeIssueId uint) (string, error) {
+	if afterIssueId == 0 && beforeIssueId == 0 {
+		return "", nil
+	}
+	if afterIssueId != 0 && beforeIssueId != 0 {
+		return "", &models.ErrorResponse{
+			ErrorMessage: "Only one of afterIssueId and beforeIssueId can be set",
+			ErrorCode:    400,
+		}
+	}
+	if afterIssueId == issueId || beforeIssueId == issueId {
+		return "", &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" cannot be moved next to itself", issueId),
+			ErrorCode:    400,
+		}
+	}
+
+	// only the neighbour and the rank next to it are read, the issues created concurrently can share a rank
+	// and the new rank is strictly between two ranks
+	neighbourRank, adjacentRank, err := stores.Issues.GetAdjacentRanks(ctx, projectId, afterIssueId+beforeIssueId, afterIssueId != 0, issueId)
+	if errors.Is(err, ErrNotFound) {
+		return "", &models.ErrorResponse{
+			ErrorMessage: fmt.Sprintf("Issue with id \"%d\" does not exists", afterIssueId+beforeIssueId),
+			ErrorCode:    404,
+		}
+	}
+	if err != nil {
+		return "", &models.ErrorResponse{
+			ErrorMessage: err.Error(),
+			ErrorCode:    500,
+		}
+	}
+	if afterIssueId != 0 {
+		return RankBetween(neighbourRank, adjacentRank), nil
+	}
+	return RankBetween(adjacentRank, neighbourRank), nil
 }
@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"issue-service/app/issue-api/cfg"
+	models "issue-service/app/issue-api/routes/models"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyDatabaseError(testCase *testing.T) {
+	testCase.Run("postgres error codes", func(t *testing.T) {
+		require.Equal(t, ErrDuplicateKey, classifyDatabaseError(DRIVER_POSTGRES, &pgconn.PgError{Code: "23505"}))
+		require.Equal(t, ErrForeignKey, classifyDatabaseError(DRIVER_POSTGRES, fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "23503"})))
+		require.Equal(t, nil, classifyDatabaseError(DRIVER_POSTGRES, &pgconn.PgError{Code: "42P01"}))
+	})
+
+	testCase.Run("errors of another driver are not classified", func(t *testing.T) {
+		require.Equal(t, nil, classifyDatabaseError(DRIVER_SQLITE, &pgconn.PgError{Code: "23505"}))
+		require.Equal(t, nil, classifyDatabaseError(DRIVER_POSTGRES, fmt.Errorf("duplicate key value violates unique constraint")))
+	})
+
+	testCase.Run("sqlite constraint violations", func(t *testing.T) {
+		database := connectTestSqliteDatabase(t)
+		_, err := MigrateUp(database)
+		require.NoError(t, err)
+
+		project := models.Project{Name: "project"}
+		require.NoError(t, translateDatabaseError(database.Create(&project)))
+
+		duplicateProject := models.Project{Name: "project"}
+		err = translateDatabaseError(database.Create(&duplicateProject))
+		require.True(t, IsDuplicateKeyError(err), fmt.Sprint(err))
+
+		orphanSprint := models.Sprint{Number: "1", ProjectID: 99999}
+		err = translateDatabaseError(database.Create(&orphanSprint))
+		require.True(t, IsForeignKeyError(err), fmt.Sprint(err))
+	})
+}
+
+func TestGetConnectionString(testCase *testing.T) {
+	testCase.Run("every postgres setting is applied", func(t *testing.T) {
+		connectionString := getConnectionString(cfg.EnvConfiguration{
+			DATABASE_HOST:              "db.example.com",
+			DATABASE_USERNAME:          "issue",
+			DATABASE_PASSWORD:          "it's secret",
+			DATABASE_PORT:              "6432",
+			DATABASE_NAME:              "issue",
+			DATABASE_SSLMODE:           "verify-full",
+			DATABASE_SSLROOTCERT:       "/certs/ca.pem",
+			DATABASE_CONNECT_TIMEOUT:   1500 * time.Millisecond,
+			DATABASE_STATEMENT_TIMEOUT: 30 * time.Second,
+		})
+
+		require.Equal(t, "host='db.example.com' user='issue' password='it\\'s secret' port='6432' dbname='issue' "+
+			"sslmode='verify-full' sslrootcert='/certs/ca.pem' connect_timeout=2 statement_timeout=30000", connectionString)
+	})
+
+	testCase.Run("the connection string is understood by the driver", func(t *testing.T) {
+		connectionString := getConnectionString(cfg.EnvConfiguration{
+			DATABASE_HOST:     "localhost",
+			DATABASE_PASSWORD: `back\slash 'quoted'`,
+			DATABASE_PORT:     "5432",
+			DATABASE_SSLMODE:  "disable",
+		})
+
+		config, err := pgconn.ParseConfig(connectionString)
+		require.NoError(t, err)
+		require.Equal(t, `back\slash 'quoted'`, config.Password)
+		require.Equal(t, uint16(5432), config.Port)
+		require.Nil(t, config.TLSConfig)
+	})
+}
+
+func TestConnectDatabase(testCase *testing.T) {
+	testCase.Run("applies the connection pool settings", func(t *testing.T) {
+		database, err := ConnectDatabase(cfg.EnvConfiguration{
+			DATABASE_DRIVER:         DRIVER_SQLITE,
+			DATABASE_NAME:           filepath.Join(t.TempDir(), "issue.db"),
+			DATABASE_MAX_OPEN_CONNS: 3,
+		})
+		require.NoError(t, err)
+		defer CloseDatabase(database)
+
+		sqlDatabase, err := database.DB()
+		require.NoError(t, err)
+		require.Equal(t, 3, sqlDatabase.Stats().MaxOpenConnections)
+	})
+
+	testCase.Run("retries before giving up", func(t *testing.T) {
+		defer func(delay time.Duration) { connectRetryDelay = delay }(connectRetryDelay)
+		connectRetryDelay = time.Millisecond
+
+		_, err := ConnectDatabase(cfg.EnvConfiguration{
+			DATABASE_DRIVER:           DRIVER_SQLITE,
+			DATABASE_NAME:             filepath.Join(t.TempDir(), "missing", "issue.db"),
+			DATABASE_CONNECT_ATTEMPTS: 3,
+		})
+		require.ErrorContains(t, err, "after 3 attempt(s)")
+	})
+}
+
+func TestGetDatabaseStatusChecks(t *testing.T) {
+	database := connectTestSqliteDatabase(t)
+	checks := GetDatabaseStatusChecks(database)
+	require.Equal(t, "database", checks[0].Name)
+	require.Equal(t, "migrations", checks[1].Name)
+
+	message, err := checks[0].Check(context.Background())
+	require.NoError(t, err)
+	require.Contains(t, message, DRIVER_SQLITE)
+
+	_, err = checks[1].Check(context.Background())
+	require.ErrorContains(t, err, "pending migrations: 0001_create_projects_sprints_issues")
+
+	applied, err := MigrateUp(database)
+	require.NoError(t, err)
+	message, err = checks[1].Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%d applied", len(applied)), message)
+
+	require.NoError(t, CloseDatabase(database))
+	_, err = checks[0].Check(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,796 @@
+package internal
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	models "issue-service/app/issue-api/routes/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormProjectStore struct {
+	database *gorm.DB
+}
+
+type gormSprintStore struct {
+	database *gorm.DB
+}
+
+type gormIssueStore struct {
+	database *gorm.DB
+}
+
+type gormBoardStore struct {
+	database *gorm.DB
+}
+
+type gormSlaStore struct {
+	database *gorm.DB
+}
+
+type gormIdempotencyStore struct {
+	database *gorm.DB
+}
+
+func NewGormStores(database *gorm.DB) models.Stores {
+	return models.Stores{
+		Projects:    &gormProjectStore{database: database},
+		Sprints:     &gormSprintStore{database: database},
+		Issues:      &gormIssueStore{database: database},
+		Boards:      &gormBoardStore{database: database},
+		Sla:         &gormSlaStore{database: database},
+		Idempotency: &gormIdempotencyStore{database: database},
+	}
+}
+
+func findOne(result *gorm.DB) error {
+	if result.Error != nil {
+		return translateDatabaseError(result)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// paginateQuery selects page of query, which must be ordered
+func paginateQuery(query *gorm.DB, page models.Page) *gorm.DB {
+	if page.Limit > 0 {
+		query = query.Limit(page.Limit)
+	}
+	if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+	return query
+}
+
+func (store *gormProjectStore) List(ctx context.Context, page models.Page) ([]models.Project, error) {
+	projects := []models.Project{}
+	result := paginateQuery(store.database.WithContext(ctx).Order("id"), page).Find(&projects)
+	return projects, translateDatabaseError(result)
+}
+
+func (store *gormProjectStore) Get(ctx context.Context, projectId int) (models.Project, error) {
+	var project models.Project
+	err := findOne(store.database.WithContext(ctx).Where("id = ?", projectId).Limit(1).Find(&project))
+	return project, err
+}
+
+func (store *gormProjectStore) Create(ctx context.Context, project *models.Project) error {
+	return translateDatabaseError(store.database.WithContext(ctx).Create(project))
+}
+
+func (store *gormSprintStore) ListByProject(ctx context.Context, projectId int, page models.Page) ([]models.Sprint, error) {
+	sprints := []models.Sprint{}
+	result := paginateQuery(store.database.WithContext(ctx).Where("project_id = ?", projectId).Order("id"), page).Find(&sprints)
+	return sprints, translateDatabaseError(result)
+}
+
+func (store *gormSprintStore) Get(ctx context.Context, projectId int, sprintId int) (models.Sprint, error) {
+	var sprint models.Sprint
+	err := findOne(store.database.WithContext(ctx).
+		Where("id = ? AND project_id = ?", sprintId, projectId).
+		Limit(1).
+		Find(&sprint))
+	return sprint, err
+}
+
+func (store *gormSprintStore) Create(ctx context.Context, sprint *models.Sprint) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkSprintDates(tx, *sprint); err != nil {
+			return err
+		}
+		return translateDatabaseError(tx.Create(sprint))
+	})
+}
+
+func (store *gormSprintStore) Update(ctx context.Context, sprint models.Sprint) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if !sprint.StartDate.IsZero() || !sprint.EndDate.IsZero() {
+			var found models.Sprint
+			if err := findOne(tx.Where("id = ? AND project_id = ?", sprint.ID, sprint.ProjectID).Limit(1).Find(&found)); err != nil {
+				return err
+			}
+			if !sprint.StartDate.IsZero() {
+				found.StartDate = sprint.StartDate
+			}
+			if !sprint.EndDate.IsZero() {
+				found.EndDate = sprint.EndDate
+			}
+			if err := checkSprintDates(tx, found); err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&models.Sprint{}).
+			Where("id = ? AND project_id = ?", sprint.ID, sprint.ProjectID).
+			Updates(models.Sprint{
+				Number:            sprint.Number,
+				StartDate:         sprint.StartDate,
+				EndDate:           sprint.EndDate,
+				MaxIssuePerSprint: sprint.MaxIssuePerSprint,
+			})
+		return findOne(result)
+	})
+}
+
+// checkSprintDates returns ErrOverlap when sprint overlaps another sprint of its project.
+// It locks the row of the project first, so that the concurrent creations and updates of its sprints check one at a time.
+func checkSprintDates(tx *gorm.DB, sprint models.Sprint) error {
+	if sprint.StartDate.IsZero() || sprint.EndDate.IsZero() {
+		return nil
+	}
+	result := tx.Model(&models.Project{}).Where("id = ?", sprint.ProjectID).UpdateColumn("id", gorm.Expr("id"))
+	if err := translateDatabaseError(result); err != nil {
+		return err
+	}
+	others := []models.Sprint{}
+	if err := translateDatabaseError(tx.Where("project_id = ? AND id <> ?", sprint.ProjectID, sprint.ID).Find(&others)); err != nil {
+		return err
+	}
+	for _, other := range others {
+		if sprint.Overlaps(other) {
+			return ErrOverlap
+		}
+	}
+	return nil
+}
+
+func (store *gormSprintStore) GetActive(ctx context.Context, projectId int) (models.Sprint, error) {
+	var sprint models.Sprint
+	err := findOne(store.database.WithContext(ctx).
+		Where("project_id = ? AND started_at IS NOT NULL AND completed_at IS NULL", projectId).
+		Limit(1).
+		Find(&sprint))
+	return sprint, err
+}
+
+func (store *gormSprintStore) Start(ctx context.Context, projectId int, sprintId int, startedAt time.Time) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the unique index idx_sprints_project_active rejects a second active sprint
+		result := tx.Model(&models.Sprint{}).
+			Where("id = ? AND project_id = ? AND started_at IS NULL AND completed_at IS NULL", sprintId, projectId).
+			Update("started_at", startedAt)
+		if err := findOne(result); err != nil {
+			return err
+		}
+
+		issues := []models.Issue{}
+		if err := translateDatabaseError(whereSprint(tx, projectId, sprintId).Order("id").Find(&issues)); err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			return nil
+		}
+		snapshot := make([]models.SprintSnapshotIssue, 0, len(issues))
+		for _, issue := range issues {
+			snapshot = append(snapshot, getSnapshotIssue(uint(sprintId), issue, true))
+		}
+		return translateDatabaseError(tx.Create(&snapshot))
+	})
+}
+
+func (store *gormSprintStore) Complete(ctx context.Context, projectId int, sprintId int, nextSprintId int, completedAt time.Time) error {
+	var nextSprint interface{}
+	if nextSprintId != 0 {
+		nextSprint = nextSprintId
+	}
+
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// the condition on the lifecycle makes the concurrent completions of the sprint fail
+		result := tx.Model(&models.Sprint{}).
+			Where("id = ? AND project_id = ? AND started_at IS NOT NULL AND completed_at IS NULL", sprintId, projectId).
+			Updates(map[string]interface{}{"completed": true, "completed_at": completedAt, "next_sprint_id": nextSprint})
+		if err := findOne(result); err != nil {
+			return err
+		}
+		if nextSprintId != 0 {
+			var next models.Sprint
+			err := findOne(tx.Where("id = ? AND project_id = ? AND completed_at IS NULL", nextSprintId, projectId).Limit(1).Find(&next))
+			if err != nil {
+				return err
+			}
+		}
+
+		issues := []models.Issue{}
+		if err := translateDatabaseError(whereSprint(tx, projectId, sprintId).Order("id").Find(&issues)); err != nil {
+			return err
+		}
+		committed := []models.SprintSnapshotIssue{}
+		if err := translateDatabaseError(tx.Where("sprint_id = ?", sprintId).Find(&committed)); err != nil {
+			return err
+		}
+		snapshot, unfinishedIssueIds := buildSprintSnapshot(uint(sprintId), committed, issues)
+
+		if len(unfinishedIssueIds) > 0 {
+			result := tx.Model(&models.Issue{}).Where("id IN ?", unfinishedIssueIds).Update("sprint_id", nextSprint)
+			if err := translateDatabaseError(result); err != nil {
+				return err
+			}
+			changes := getCarriedOverIssueChanges(issues, unfinishedIssueIds, nextSprintId, completedAt)
+			if err := translateDatabaseError(tx.Create(&changes)); err != nil {
+				return err
+			}
+		}
+		if err := translateDatabaseError(tx.Where("sprint_id = ?", sprintId).Delete(&models.SprintSnapshotIssue{})); err != nil {
+			return err
+		}
+		if len(snapshot) == 0 {
+			return nil
+		}
+		return translateDatabaseError(tx.Create(&snapshot))
+	})
+}
+
+func (store *gormSprintStore) ListSnapshot(ctx context.Context, projectId int, sprintId int) ([]models.SprintSnapshotIssue, error) {
+	snapshot := []models.SprintSnapshotIssue{}
+	result := store.database.WithContext(ctx).
+		Where("sprint_id = ? AND sprint_id IN (SELECT id FROM sprints WHERE project_id = ?)", sprintId, projectId).
+		Order("issue_id").
+		Find(&snapshot)
+	return snapshot, translateDatabaseError(result)
+}
+
+// getSnapshotIssue records the fields of issue in the snapshot of a sprint
+func getSnapshotIssue(sprintId uint, issue models.Issue, committed bool) models.SprintSnapshotIssue {
+	snapshotIssue := models.SprintSnapshotIssue{
+		SprintID:    sprintId,
+		IssueID:     issue.ID,
+		Committed:   committed,
+		Type:        issue.Type,
+		Title:       issue.Title,
+		Status:      issue.Status,
+		StoryPoints: issue.StoryPoints,
+	}
+	if committed {
+		snapshotIssue.CommittedStoryPoints = issue.StoryPoints
+	}
+	return snapshotIssue
+}
+
+// getIssueChange records the state of issue at changedAt in its history
+func getIssueChange(issue models.Issue, changedAt time.Time) models.IssueChange {
+	return models.IssueChange{
+		IssueID:     issue.ID,
+		ProjectID:   issue.ProjectID,
+		SprintID:    issue.SprintID,
+		Status:      issue.Status,
+		StoryPoints: issue.StoryPoints,
+		ChangedAt:   changedAt,
+	}
+}
+
+// getCarriedOverIssueChanges records the move of the unfinished issues of a completed sprint to nextSprintId
+func getCarriedOverIssueChanges(issues []models.Issue, unfinishedIssueIds []uint, nextSprintId int, completedAt time.Time) []models.IssueChange {
+	unfinished := map[uint]bool{}
+	for _, issueId := range unfinishedIssueIds {
+		unfinished[issueId] = true
+	}
+	changes := []models.IssueChange{}
+	for _, issue := range issues {
+		if unfinished[issue.ID] {
+			issue.SprintID = nextSprintId
+			changes = append(changes, getIssueChange(issue, completedAt))
+		}
+	}
+	return changes
+}
+
+// buildSprintSnapshot returns the snapshot of a sprint completed with issues, from the snapshot committed at its start,
+// and the ids of the unfinished issues to carry over
+func buildSprintSnapshot(sprintId uint, committed []models.SprintSnapshotIssue, issues []models.Issue) ([]models.SprintSnapshotIssue, []uint) {
+	committedIssueIds := map[uint]bool{}
+	committedStoryPoints := map[uint]*int{}
+	for _, issue := range committed {
+		committedIssueIds[issue.IssueID] = true
+		committedStoryPoints[issue.IssueID] = issue.CommittedStoryPoints
+	}
+
+	snapshot := []models.SprintSnapshotIssue{}
+	unfinishedIssueIds := []uint{}
+	for _, issue := range issues {
+		outcome := models.SPRINT_OUTCOME_COMPLETED
+		if !models.IsClosedIssueStatus(issue.Status) {
+			outcome = models.SPRINT_OUTCOME_CARRIED_OVER
+			unfinishedIssueIds = append(unfinishedIssueIds, issue.ID)
+		}
+		snapshotIssue := getSnapshotIssue(sprintId, issue, committedIssueIds[issue.ID])
+		snapshotIssue.Outcome = outcome
+		// the committed work is the one estimated at the start, the re-estimates during the sprint do not inflate it
+		snapshotIssue.CommittedStoryPoints = committedStoryPoints[issue.ID]
+		snapshot = append(snapshot, snapshotIssue)
+		delete(committedIssueIds, issue.ID)
+	}
+	// the committed issues moved out of the sprint keep the fields they had at its start
+	for _, issue := range committed {
+		if committedIssueIds[issue.IssueID] {
+			issue.Outcome = models.SPRINT_OUTCOME_REMOVED
+			snapshot = append(snapshot, issue)
+		}
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].IssueID < snapshot[j].IssueID })
+	return snapshot, unfinishedIssueIds
+}
+
+func (store *gormSprintStore) SumPoints(ctx context.Context, projectId int, sprintIds []uint) (map[uint]models.SprintPoints, error) {
+	points := map[uint]models.SprintPoints{}
+	if len(sprintIds) == 0 {
+		return points, nil
+	}
+
+	issueRows := []models.SprintPoints{}
+	result := store.database.WithContext(ctx).Model(&models.Issue{}).
+		Select("sprint_id, COALESCE(SUM(story_points), 0) AS issue_points, "+
+			"COALESCE(SUM(CASE WHEN lower(status) IN ? THEN story_points END), 0) AS closed_issue_points", models.ClosedIssueStatuses).
+		Where("project_id = ? AND sprint_id IN ?", projectId, sprintIds).
+		Group("sprint_id").
+		Scan(&issueRows)
+	if err := translateDatabaseError(result); err != nil {
+		return nil, err
+	}
+	snapshotRows := []models.SprintPoints{}
+	result = store.database.WithContext(ctx).Model(&models.SprintSnapshotIssue{}).
+		Select("sprint_id, COALESCE(SUM(CASE WHEN committed THEN committed_story_points END), 0) AS committed_points, "+
+			"COALESCE(SUM(CASE WHEN outcome = ? THEN story_points END), 0) AS completed_points, "+
+			"COUNT(CASE WHEN committed THEN 1 END) AS committed_issues, "+
+			"COUNT(CASE WHEN outcome = ? THEN 1 END) AS completed_issues", models.SPRINT_OUTCOME_COMPLETED, models.SPRINT_OUTCOME_COMPLETED).
+		Where("sprint_id IN ? AND sprint_id IN (SELECT id FROM sprints WHERE project_id = ?)", sprintIds, projectId).
+		Group("sprint_id").
+		Scan(&snapshotRows)
+	if err := translateDatabaseError(result); err != nil {
+		return nil, err
+	}
+
+	for _, row := range issueRows {
+		points[row.SprintID] = row
+	}
+	for _, row := range snapshotRows {
+		sprintPoints := points[row.SprintID]
+		sprintPoints.SprintID = row.SprintID
+		sprintPoints.CommittedPoints = row.CommittedPoints
+		sprintPoints.CompletedPoints = row.CompletedPoints
+		sprintPoints.CommittedIssues = row.CommittedIssues
+		sprintPoints.CompletedIssues = row.CompletedIssues
+		points[row.SprintID] = sprintPoints
+	}
+	return points, nil
+}
+
+func (store *gormSprintStore) CountActiveByProject(ctx context.Context) (map[int]int, error) {
+	rows := []projectCount{}
+	result := store.database.WithContext(ctx).Model(&models.Sprint{}).
+		Select("project_id, count(*) AS count").
+		Where("started_at IS NOT NULL AND completed_at IS NULL").
+		Group("project_id").
+		Scan(&rows)
+	return projectCountsToMap(rows), translateDatabaseError(result)
+}
+
+// whereSprint selects the issues of a sprint, or of the backlog of the project when sprintId is 0
+func whereSprint(query *gorm.DB, projectId int, sprintId int) *gorm.DB {
+	if sprintId == 0 {
+		return query.Where("project_id = ? AND sprint_id IS NULL", projectId)
+	}
+	return query.Where("project_id = ? AND sprint_id = ?", projectId, sprintId)
+}
+
+func (store *gormIssueStore) ListBySprint(ctx context.Context, projectId int, sprintId int, page models.Page) ([]models.Issue, error) {
+	issues := []models.Issue{}
+	result := paginateQuery(whereSprint(store.database.WithContext(ctx), projectId, sprintId).Order("rank, id"), page).Find(&issues)
+	return issues, translateDatabaseError(result)
+}
+
+func (store *gormIssueStore) Get(ctx context.Context, projectId int, sprintId int, issueId uint) (models.Issue, error) {
+	var issue models.Issue
+	err := findOne(whereSprint(store.database.WithContext(ctx), projectId, sprintId).
+		Where("id = ?", issueId).
+		Limit(1).
+		Find(&issue))
+	return issue, err
+}
+
+// getLastRank returns the greatest rank of the issues of the project, empty when it has no issue
+func getLastRank(tx *gorm.DB, projectId int) (string, error) {
+	var rank string
+	result := tx.Model(&models.Issue{}).Where("project_id = ?", projectId).Select("COALESCE(MAX(rank), '')").Scan(&rank)
+	return rank, translateDatabaseError(result)
+}
+
+func (store *gormIssueStore) Create(ctx context.Context, issue *models.Issue) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		lastRank, err := getLastRank(tx, issue.ProjectID)
+		if err != nil {
+			return err
+		}
+		issue.Rank = RankBetween(lastRank, "")
+
+		query := tx
+		if issue.SprintID == 0 {
+			// an issue of the backlog has no sprint, NULL and not 0
+			query = query.Omit("sprint_id")
+		}
+		if err := translateDatabaseError(query.Create(issue)); err != nil {
+			return err
+		}
+		change := getIssueChange(*issue, issue.CreatedAt)
+		return translateDatabaseError(tx.Create(&change))
+	})
+}
+
+// recordIssueChange records the state of the issue after a change in its history
+func recordIssueChange(tx *gorm.DB, issueId uint) error {
+	var issue models.Issue
+	if err := findOne(tx.Where("id = ?", issueId).Limit(1).Find(&issue)); err != nil {
+		return err
+	}
+	change := getIssueChange(issue, issue.UpdatedAt)
+	return translateDatabaseError(tx.Create(&change))
+}
+
+func (store *gormIssueStore) Update(ctx context.Context, issue models.Issue) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateIssue(tx, issue); err != nil {
+			return err
+		}
+		// the history only follows the status and the points
+		if issue.Status == "" && issue.StoryPoints == nil {
+			return nil
+		}
+		return recordIssueChange(tx, issue.ID)
+	})
+}
+
+func updateIssue(tx *gorm.DB, issue models.Issue) error {
+	result := whereSprint(tx.Model(&models.Issue{}), issue.ProjectID, issue.SprintID).
+		Where("id = ?", issue.ID).
+		Updates(models.Issue{
+			Type:                     issue.Type,
+			Title:                    issue.Title,
+			Description:              issue.Description,
+			Status:                   issue.Status,
+			Assignee:                 issue.Assignee,
+			Priority:                 issue.Priority,
+			Epic:                     issue.Epic,
+			StoryPoints:              issue.StoryPoints,
+			Size:                     issue.Size,
+			OriginalEstimateMinutes:  issue.OriginalEstimateMinutes,
+			RemainingEstimateMinutes: issue.RemainingEstimateMinutes,
+			LoggedMinutes:            issue.LoggedMinutes,
+		})
+	return findOne(result)
+}
+
+func (store *gormIssueStore) Move(ctx context.Context, issue models.Issue, targetProjectId int, targetSprintId int) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rank := ""
+		if targetProjectId != issue.ProjectID {
+			lastRank, err := getLastRank(tx, targetProjectId)
+			if err != nil {
+				return err
+			}
+			rank = RankBetween(lastRank, "")
+		}
+		// the map writes the sprint_id NULL of the backlog, that the zero SprintID of a struct would skip
+		updates := map[string]interface{}{"project_id": targetProjectId, "sprint_id": nil}
+		if targetSprintId != 0 {
+			updates["sprint_id"] = targetSprintId
+		}
+		if rank != "" {
+			updates["rank"] = rank
+		}
+		result := whereSprint(tx.Model(&models.Issue{}), issue.ProjectID, issue.SprintID).
+			Where("id = ?", issue.ID).
+			Updates(updates)
+		if err := findOne(result); err != nil {
+			return err
+		}
+		return recordIssueChange(tx, issue.ID)
+	})
+}
+
+func (store *gormIssueStore) GetAdjacentRanks(ctx context.Context, projectId int, issueId uint, after bool, excludedIssueId uint) (string, string, error) {
+	database := store.database.WithContext(ctx)
+	var issue models.Issue
+	if err := findOne(database.Select("id", "rank").Where("id = ? AND project_id = ?", issueId, projectId).Limit(1).Find(&issue)); err != nil {
+		return "", "", err
+	}
+	query := database.Model(&models.Issue{}).Where("project_id = ? AND id <> ?", projectId, excludedIssueId)
+	if after {
+		query = query.Where("rank > ?", issue.Rank).Order("rank")
+	} else {
+		query = query.Where("rank < ?", issue.Rank).Order("rank DESC")
+	}
+	ranks := []string{}
+	result := query.Limit(1).Pluck("rank", &ranks)
+	if len(ranks) == 0 {
+		return issue.Rank, "", translateDatabaseError(result)
+	}
+	return issue.Rank, ranks[0], translateDatabaseError(result)
+}
+
+func (store *gormIssueStore) SetRank(ctx context.Context, projectId int, issueId uint, rank string) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Issue{}).
+			Where("id = ? AND project_id = ?", issueId, projectId).
+			Update("rank", rank)
+		if err := findOne(result); err != nil {
+			return err
+		}
+		return rebalanceLongRanks(tx, projectId, rank)
+	})
+}
+
+// rebalanceLongRanks ranks again the issues of the project in their order with the shortest ranks,
+// when rank is longer than rankRebalanceLength
+func rebalanceLongRanks(tx *gorm.DB, projectId int, rank string) error {
+	if len(rank) <= rankRebalanceLength {
+		return nil
+	}
+	issueIds := []uint{}
+	result := tx.Model(&models.Issue{}).Where("project_id = ?", projectId).Order("rank, id").Pluck("id", &issueIds)
+	if err := translateDatabaseError(result); err != nil {
+		return err
+	}
+	for index, evenRank := range getEvenRanks(len(issueIds)) {
+		// the issues are not changed by the rebalancing, their updated_at is kept
+		result := tx.Model(&models.Issue{}).Where("id = ?", issueIds[index]).UpdateColumn("rank", evenRank)
+		if err := translateDatabaseError(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (store *gormIssueStore) MoveOnBoard(ctx context.Context, projectId int, issueId uint, column models.BoardColumn, status string, rank string) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// updating the board serializes the moves of the issues of the project, so that they see the same counts
+		result := tx.Model(&models.Board{}).Where("project_id = ?", projectId).Update("updated_at", time.Now())
+		if err := findOne(result); err != nil {
+			return err
+		}
+		var board models.Board
+		if err := findOne(tx.Where("project_id = ?", projectId).Limit(1).Find(&board)); err != nil {
+			return err
+		}
+		var issue models.Issue
+		result = whereBoardScope(tx.Where("id = ? AND project_id = ?", issueId, projectId), board).Limit(1).Find(&issue)
+		if err := findOne(result); err != nil {
+			return err
+		}
+
+		if status == "" {
+			status = column.Statuses[0]
+			if column.HasStatus(issue.Status) {
+				status = issue.Status
+			}
+		}
+		// the issues already in the column move within it, even when it is over its limit
+		if column.WipLimit != nil && !column.HasStatus(issue.Status) {
+			statuses := make([]string, 0, len(column.Statuses))
+			for _, columnStatus := range column.Statuses {
+				statuses = append(statuses, strings.ToLower(columnStatus))
+			}
+			var count int64
+			result := whereBoardScope(tx.Model(&models.Issue{}).Where("project_id = ? AND lower(status) IN ?", projectId, statuses), board).Count(&count)
+			if err := translateDatabaseError(result); err != nil {
+				return err
+			}
+			if count >= int64(*column.WipLimit) {
+				return ErrWipLimit
+			}
+		}
+
+		updates := map[string]interface{}{"status": status}
+		if rank != "" {
+			updates["rank"] = rank
+		}
+		result = tx.Model(&models.Issue{}).Where("id = ?", issueId).Updates(updates)
+		if err := findOne(result); err != nil {
+			return err
+		}
+		if err := rebalanceLongRanks(tx, projectId, rank); err != nil {
+			return err
+		}
+		if status == issue.Status {
+			return nil
+		}
+		return recordIssueChange(tx, issueId)
+	})
+}
+
+// whereBoardScope selects the issues in the scope of the board, as models.Board.InScope
+func whereBoardScope(query *gorm.DB, board models.Board) *gorm.DB {
+	if board.Scope == models.BOARD_SCOPE_PROJECT {
+		return query.Where("(sprint_id IS NULL OR sprint_id IN (SELECT id FROM sprints WHERE project_id = ? AND completed_at IS NULL AND deleted_at IS NULL))", board.ProjectID)
+	}
+	return query.Where("sprint_id IN (SELECT id FROM sprints WHERE project_id = ? AND started_at IS NOT NULL AND completed_at IS NULL AND deleted_at IS NULL)", board.ProjectID)
+}
+
+func (store *gormIssueStore) ListSprintChanges(ctx context.Context, projectId int, sprintId int) ([]models.IssueChange, error) {
+	changes := []models.IssueChange{}
+	result := store.database.WithContext(ctx).
+		Where("issue_id IN (SELECT issue_id FROM issue_changes WHERE project_id = ? AND sprint_id = ?)", projectId, sprintId).
+		Order("changed_at, id").
+		Find(&changes)
+	return changes, translateDatabaseError(result)
+}
+
+// whereIssueFilter selects the issues of the project matching filter
+func whereIssueFilter(query *gorm.DB, projectId int, filter models.IssueFilter) *gorm.DB {
+	query = query.Where("project_id = ?", projectId)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Assignee != "" {
+		query = query.Where("assignee = ?", filter.Assignee)
+	}
+	if filter.Open {
+		query = query.Where("lower(status) NOT IN ?", models.ClosedIssueStatuses)
+	}
+	return query
+}
+
+func (store *gormIssueStore) ListByProject(ctx context.Context, projectId int, filter models.IssueFilter) ([]models.Issue, error) {
+	issues := []models.Issue{}
+	result := whereIssueFilter(store.database.WithContext(ctx), projectId, filter).Order("rank, id").Find(&issues)
+	return issues, translateDatabaseError(result)
+}
+
+func (store *gormIssueStore) ListProjectChanges(ctx context.Context, projectId int, filter models.IssueFilter) ([]models.IssueChange, error) {
+	changes := []models.IssueChange{}
+	database := store.database.WithContext(ctx)
+	projectIssues := whereIssueFilter(database.Model(&models.Issue{}).Select("id"), projectId, filter)
+	result := database.
+		Where("issue_id IN (?)", projectIssues).
+		Order("changed_at, id").
+		Find(&changes)
+	return changes, translateDatabaseError(result)
+}
+
+func (store *gormIssueStore) CountOpenByProject(ctx context.Context) (map[int]int, error) {
+	rows := []projectCount{}
+	result := store.database.WithContext(ctx).Model(&models.Issue{}).
+		Select("project_id, count(*) AS count").
+		Where("lower(status) NOT IN ?", models.ClosedIssueStatuses).
+		Group("project_id").
+		Scan(&rows)
+	return projectCountsToMap(rows), translateDatabaseError(result)
+}
+
+func (store *gormIssueStore) SumOpenWork(ctx context.Context, projectId int) (models.OpenWork, error) {
+	var work models.OpenWork
+	result := store.database.WithContext(ctx).Model(&models.Issue{}).
+		Select("count(*) AS issues, COALESCE(SUM(story_points), 0) AS points, "+
+			"COUNT(CASE WHEN story_points IS NULL THEN 1 END) AS unestimated_issues").
+		Where("project_id = ? AND lower(status) NOT IN ?", projectId, models.ClosedIssueStatuses).
+		Scan(&work)
+	return work, translateDatabaseError(result)
+}
+
+type projectCount struct {
+	ProjectID int
+	Count     int
+}
+
+func projectCountsToMap(rows []projectCount) map[int]int {
+	counts := map[int]int{}
+	for _, row := range rows {
+		counts[row.ProjectID] = row.Count
+	}
+	return counts
+}
+
+func (store *gormBoardStore) Get(ctx context.Context, projectId int) (models.Board, error) {
+	var board models.Board
+	err := findOne(store.database.WithContext(ctx).Where("project_id = ?", projectId).Limit(1).Find(&board))
+	return board, err
+}
+
+func (store *gormBoardStore) Save(ctx context.Context, board *models.Board) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.Board
+		result := tx.Where("project_id = ?", board.ProjectID).Limit(1).Find(&existing)
+		if err := translateDatabaseError(result); err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			// the unique index idx_boards_project rejects a concurrent creation
+			return translateDatabaseError(tx.Create(board))
+		}
+		board.ID, board.CreatedAt = existing.ID, existing.CreatedAt
+		return translateDatabaseError(tx.Select("swimlane", "scope", "columns", "updated_at").Updates(board))
+	})
+}
+
+func (store *gormSlaStore) GetPolicy(ctx context.Context, projectId int) (models.SlaPolicy, error) {
+	var policy models.SlaPolicy
+	err := findOne(store.database.WithContext(ctx).Where("project_id = ?", projectId).Limit(1).Find(&policy))
+	return policy, err
+}
+
+func (store *gormSlaStore) SavePolicy(ctx context.Context, policy *models.SlaPolicy) error {
+	return store.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.SlaPolicy
+		result := tx.Where("project_id = ?", policy.ProjectID).Limit(1).Find(&existing)
+		if err := translateDatabaseError(result); err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			// the unique index idx_sla_policies_project rejects a concurrent creation
+			return translateDatabaseError(tx.Create(policy))
+		}
+		policy.ID, policy.CreatedAt = existing.ID, existing.CreatedAt
+		return translateDatabaseError(tx.Select("paused_statuses", "targets", "updated_at").Updates(policy))
+	})
+}
+
+func (store *gormSlaStore) ListPolicies(ctx context.Context) ([]models.SlaPolicy, error) {
+	policies := []models.SlaPolicy{}
+	result := store.database.WithContext(ctx).Order("project_id").Find(&policies)
+	return policies, translateDatabaseError(result)
+}
+
+func (store *gormSlaStore) ListBreaches(ctx context.Context, projectId int) ([]models.SlaBreach, error) {
+	breaches := []models.SlaBreach{}
+	result := store.database.WithContext(ctx).Where("project_id = ?", projectId).Order("id").Find(&breaches)
+	return breaches, translateDatabaseError(result)
+}
+
+func (store *gormSlaStore) FlagBreach(ctx context.Context, breach *models.SlaBreach) (bool, error) {
+	// the unique index idx_sla_breaches_issue_kind keeps the first flag of concurrent checkers
+	result := store.database.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(breach)
+	if err := translateDatabaseError(result); err != nil {
+		return false, err
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (store *gormIdempotencyStore) Create(ctx context.Context, record *models.IdempotencyRecord, expiredBefore time.Time) error {
+	result := store.database.WithContext(ctx).Where("created_at < ?", expiredBefore).Delete(&models.IdempotencyRecord{})
+	if err := translateDatabaseError(result); err != nil {
+		return err
+	}
+	// the unique index idx_idempotency_records_key rejects the key of a request already recorded
+	return translateDatabaseError(store.database.WithContext(ctx).Create(record))
+}
+
+func (store *gormIdempotencyStore) Get(ctx context.Context, key string) (models.IdempotencyRecord, error) {
+	var record models.IdempotencyRecord
+	err := findOne(store.database.WithContext(ctx).Where("key = ?", key).Limit(1).Find(&record))
+	return record, err
+}
+
+func (store *gormIdempotencyStore) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	result := store.database.WithContext(ctx).Model(&models.IdempotencyRecord{}).Where("key = ?", record.Key).
+		Updates(map[string]interface{}{"status_code": record.StatusCode, "content_type": record.ContentType, "body": record.Body})
+	return findOne(result)
+}
+
+func (store *gormIdempotencyStore) Delete(ctx context.Context, key string) error {
+	result := store.database.WithContext(ctx).Where("key = ?", key).Delete(&models.IdempotencyRecord{})
+	return translateDatabaseError(result)
+}
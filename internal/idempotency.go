@@ -0,0 +1,150 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"time"
+
+	models "issue-service/app/issue-api/routes/models"
+)
+
+const (
+	IDEMPOTENCY_KEY_HEADER = "Idempotency-Key"
+	// IDEMPOTENCY_REPLAYED_HEADER is set on the responses replayed to the retries
+	IDEMPOTENCY_REPLAYED_HEADER = "Idempotent-Replayed"
+	// IDEMPOTENCY_KEY_TTL is how long the response of a request is replayed to its retries
+	IDEMPOTENCY_KEY_TTL = 24 * time.Hour
+)
+
+var validIdempotencyKey = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// Idempotency processes once the POST requests sent with the same Idempotency-Key header,
+// so that the clients can retry them after an ambiguous failure
+type Idempotency struct {
+	store models.IdempotencyStore
+}
+
+func NewIdempotency(store models.IdempotencyStore) *Idempotency {
+	return &Idempotency{store: store}
+}
+
+// Handler replays the response of the first request to the requests sent with the same Idempotency-Key header,
+// it answers 409 while the first request is processed and 422 when the key was sent with another request.
+// The responses of the server errors and of the panics are not kept, so that the request can be processed again.
+// The requests without the header and the other methods are not changed.
+func (idempotency *Idempotency) Handler(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IDEMPOTENCY_KEY_HEADER)
+		if r.Method != http.MethodPost || key == "" {
+			inner.ServeHTTP(w, r)
+			return
+		}
+		if !validIdempotencyKey.MatchString(key) {
+			LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "The Idempotency-Key header must have from 1 to 255 visible ASCII characters",
+				ErrorCode:    http.StatusBadRequest,
+			}, w)
+			return
+		}
+		body, err := readRequestBody(r)
+		if errors.Is(err, errRequestBodyTooLarge) {
+			LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: err.Error(),
+				ErrorCode:    http.StatusRequestEntityTooLarge,
+			}, w)
+			return
+		}
+		if err != nil {
+			LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error reading request body",
+				ErrorCode:    http.StatusBadRequest,
+			}, w)
+			return
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		record := models.IdempotencyRecord{Key: key, RequestHash: getRequestHash(r, body)}
+		err = idempotency.store.Create(r.Context(), &record, time.Now().Add(-IDEMPOTENCY_KEY_TTL))
+		if errors.Is(err, ErrDuplicateKey) {
+			idempotency.replay(w, r, record)
+			return
+		}
+		if err != nil {
+			LogAndReturnErrorResponse(&models.ErrorResponse{ErrorMessage: err.Error(), ErrorCode: http.StatusInternalServerError}, w)
+			return
+		}
+
+		response := &responseBuffer{ResponseWriter: w}
+		defer func() {
+			// a panic is answered 500 by the recovery middleware, the key is forgotten as for the server errors
+			if recovered := recover(); recovered != nil {
+				if err := idempotency.store.Delete(context.Background(), key); err != nil {
+					RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error forgetting the Idempotency-Key")
+				}
+				panic(recovered)
+			}
+		}()
+		inner.ServeHTTP(response, r)
+		// the record is completed even when the client went away, its retries would be answered 409 otherwise
+		record.StatusCode = response.getStatus()
+		if record.StatusCode >= http.StatusInternalServerError {
+			err = idempotency.store.Delete(context.Background(), key)
+		} else {
+			record.ContentType, record.Body = response.Header().Get("Content-Type"), response.body.Bytes()
+			err = idempotency.store.Complete(context.Background(), record)
+		}
+		if err != nil {
+			RequestLogger(r.Context()).WithField("error", err.Error()).Error("Error storing the response of the Idempotency-Key")
+		}
+		response.flush()
+	})
+}
+
+// replay answers a request with the response stored for its key
+func (idempotency *Idempotency) replay(w http.ResponseWriter, r *http.Request, request models.IdempotencyRecord) {
+	record, err := idempotency.store.Get(r.Context(), request.Key)
+	if errors.Is(err, ErrNotFound) {
+		// the first request failed and its key was just forgotten, the 409 below lets the client send it again
+		record.RequestHash = request.RequestHash
+	} else if err != nil {
+		LogAndReturnErrorResponse(&models.ErrorResponse{ErrorMessage: err.Error(), ErrorCode: http.StatusInternalServerError}, w)
+		return
+	}
+	if record.RequestHash != request.RequestHash {
+		LogAndReturnErrorResponse(&models.ErrorResponse{
+			ErrorMessage: "The Idempotency-Key was sent with another request",
+			ErrorCode:    http.StatusUnprocessableEntity,
+		}, w)
+		return
+	}
+	if record.StatusCode == 0 {
+		w.Header().Set("Retry-After", "1")
+		LogAndReturnErrorResponse(&models.ErrorResponse{
+			ErrorMessage: "The request with the same Idempotency-Key is being processed",
+			ErrorCode:    http.StatusConflict,
+		}, w)
+		return
+	}
+
+	w.Header().Set(IDEMPOTENCY_REPLAYED_HEADER, "true")
+	if record.ContentType != "" {
+		w.Header().Set("Content-Type", record.ContentType)
+	}
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+// getRequestHash hashes the method, the path and the body of the request
+func getRequestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	models "issue-service/app/issue-api/routes/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency(testCase *testing.T) {
+	config, err := GetConfig("../.env")
+	require.NoError(testCase, err)
+
+	post := func(handler http.Handler, key string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/v1/projects", strings.NewReader(body))
+		if key != "" {
+			request.Header.Set(IDEMPOTENCY_KEY_HEADER, key)
+		}
+		responseRecorder := httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, request)
+		return responseRecorder
+	}
+
+	for name, stores := range map[string]models.Stores{
+		"gorm":   NewGormStores(NewTestDatabase(testCase, config)),
+		"memory": NewMemoryStores(),
+	} {
+		testCase.Run(name, func(t *testing.T) {
+			var calls int32
+			statusCode := http.StatusOK
+			handler := NewIdempotency(stores.Idempotency).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+				w.WriteHeader(statusCode)
+				fmt.Fprintf(w, `{"id":%d}`, call)
+			}))
+
+			first := post(handler, "create-1", `{"name":"first"}`)
+			require.Equal(t, http.StatusOK, first.Code)
+			retry := post(handler, "create-1", `{"name":"first"}`)
+			require.Equal(t, http.StatusOK, retry.Code)
+			require.Equal(t, `{"id":1}`, retry.Body.String())
+			require.Equal(t, "application/json; charset=UTF-8", retry.Header().Get("Content-Type"))
+			require.Equal(t, "true", retry.Header().Get(IDEMPOTENCY_REPLAYED_HEADER))
+			require.Equal(t, int32(1), atomic.LoadInt32(&calls), "a retry should not be processed again")
+
+			require.Equal(t, http.StatusUnprocessableEntity, post(handler, "create-1", `{"name":"other"}`).Code)
+			require.Equal(t, http.StatusBadRequest, post(handler, "with space", `{}`).Code)
+			require.Equal(t, `{"id":2}`, post(handler, "", `{"name":"first"}`).Body.String(), "a request without key is processed")
+
+			statusCode = http.StatusInternalServerError
+			require.Equal(t, http.StatusInternalServerError, post(handler, "create-2", `{}`).Code)
+			statusCode = http.StatusOK
+			require.Equal(t, `{"id":4}`, post(handler, "create-2", `{}`).Body.String(), "the server errors are not replayed")
+
+			panicking := NewIdempotency(stores.Idempotency).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic("handler failure")
+			}))
+			require.Panics(t, func() { post(panicking, "create-3", `{}`) })
+			require.Equal(t, `{"id":5}`, post(handler, "create-3", `{}`).Body.String(), "the key of a panicking request is forgotten")
+
+			requestHash := getRequestHash(httptest.NewRequest(http.MethodPost, "/v1/projects", nil), []byte(`{}`))
+			record := models.IdempotencyRecord{Key: "in-progress", RequestHash: requestHash}
+			require.NoError(t, stores.Idempotency.Create(context.Background(), &record, time.Now().Add(-IDEMPOTENCY_KEY_TTL)))
+			inProgress := post(handler, "in-progress", `{}`)
+			require.Equal(t, http.StatusConflict, inProgress.Code)
+			require.Equal(t, "1", inProgress.Header().Get("Retry-After"))
+		})
+	}
+}
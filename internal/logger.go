@@ -1,23 +1,90 @@
 package internal
 
 import (
+	"context"
+	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"math/rand"
 	"net/http"
+	"regexp"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
 )
 
-func Logger(inner http.Handler, name string) http.Handler {
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+// validRequestId keeps the request ids coming from the clients out of the logs when they could forge log lines
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type requestLoggerKey struct{}
+
+// AccessLogger logs every request it serves at info level.
+// The responses with a status below 400 are logged with a probability of samplePercent %.
+type AccessLogger struct {
+	logger        *log.Logger
+	samplePercent int
+}
+
+func NewAccessLogger(logger *log.Logger, samplePercent int) *AccessLogger {
+	return &AccessLogger{logger: logger, samplePercent: samplePercent}
+}
+
+// Handler assigns a request id, or keeps the one in the X-Request-ID header, and sends it back.
+// The handlers get a logrus entry carrying the request id from RequestLogger.
+func (accessLogger *AccessLogger) Handler(inner http.Handler, routeName string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		inner.ServeHTTP(w, r)
+		requestId := r.Header.Get(REQUEST_ID_HEADER)
+		if !validRequestId.MatchString(requestId) {
+			requestId = newRequestId()
+		}
+		w.Header().Set(REQUEST_ID_HEADER, requestId)
+
+		entry := accessLogger.logger.WithContext(r.Context()).WithField("request_id", requestId)
+		responseWriter := negroni.NewResponseWriter(w)
 
-		log.WithFields(log.Fields{
-			"method":    r.Method,
-			"path":      r.RequestURI,
-			"handler":   name,
-			"timestamp": time.Since(start),
-		}).Debug()
+		inner.ServeHTTP(responseWriter, r.WithContext(context.WithValue(r.Context(), requestLoggerKey{}, entry)))
+
+		status := responseWriter.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if status < 400 && !accessLogger.isSampled() {
+			return
+		}
+		entry.WithFields(log.Fields{
+			"method":      r.Method,
+			"path":        r.RequestURI,
+			"route":       routeName,
+			"status":      status,
+			"size":        responseWriter.Size(),
+			"remote_addr": r.RemoteAddr,
+			"user_agent":  r.UserAgent(),
+			"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+		}).Info("Request served")
 	})
 }
+
+func (accessLogger *AccessLogger) isSampled() bool {
+	return accessLogger.samplePercent >= 100 || rand.Intn(100) < accessLogger.samplePercent
+}
+
+// RequestLogger returns the logrus entry of the request served with ctx,
+// or an entry of the standard logger outside of a request
+func RequestLogger(ctx context.Context) *log.Entry {
+	if entry, ok := ctx.Value(requestLoggerKey{}).(*log.Entry); ok {
+		return entry
+	}
+	return log.WithContext(ctx)
+}
+
+func newRequestId() string {
+	id := make([]byte, 16)
+	if _, err := cryptoRand.Read(id); err != nil {
+		return GetRandomStringName(32)
+	}
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func newBufferLogger() (*log.Logger, *bytes.Buffer) {
+	var out bytes.Buffer
+	logger := log.New()
+	logger.SetFormatter(&log.JSONFormatter{})
+	logger.SetOutput(&out)
+	return logger, &out
+}
+
+func getLogEntries(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	entries := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func serveWithAccessLog(accessLogger *AccessLogger, status int, requestId string) *httptest.ResponseRecorder {
+	handler := accessLogger.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		RequestLogger(r.Context()).Warn("in the handler")
+		w.WriteHeader(status)
+		w.Write([]byte("body"))
+	}), "GetProjects")
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/projects", nil)
+	request.Header.Set(REQUEST_ID_HEADER, requestId)
+	request.Header.Set("User-Agent", "test-agent")
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+	return responseRecorder
+}
+
+func TestAccessLogger(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("the request id of the client is kept", func(t *testing.T) {
+		t.Parallel()
+		logger, out := newBufferLogger()
+		responseRecorder := serveWithAccessLog(NewAccessLogger(logger, 100), http.StatusOK, "client-id-1")
+
+		require.Equal(t, "client-id-1", responseRecorder.Header().Get(REQUEST_ID_HEADER))
+		entries := getLogEntries(t, out)
+		require.Equal(t, 2, len(entries))
+		require.Equal(t, "in the handler", entries[0]["msg"])
+		require.Equal(t, "client-id-1", entries[0]["request_id"])
+
+		require.Equal(t, "Request served", entries[1]["msg"])
+		require.Equal(t, "info", entries[1]["level"])
+		require.Equal(t, "client-id-1", entries[1]["request_id"])
+		require.Equal(t, "GET", entries[1]["method"])
+		require.Equal(t, "/v1/projects", entries[1]["path"])
+		require.Equal(t, "GetProjects", entries[1]["route"])
+		require.Equal(t, float64(200), entries[1]["status"])
+		require.Equal(t, float64(4), entries[1]["size"])
+		require.Equal(t, "192.0.2.1:1234", entries[1]["remote_addr"])
+		require.Equal(t, "test-agent", entries[1]["user_agent"])
+		require.Contains(t, entries[1], "duration_ms")
+	})
+
+	testCase.Run("a missing or invalid request id is replaced", func(t *testing.T) {
+		t.Parallel()
+		logger, out := newBufferLogger()
+		accessLogger := NewAccessLogger(logger, 100)
+
+		for _, requestId := range []string{"", "forged\n{\"level\":\"error\"}", strings.Repeat("a", 129)} {
+			responseRecorder := serveWithAccessLog(accessLogger, http.StatusOK, requestId)
+			generatedId := responseRecorder.Header().Get(REQUEST_ID_HEADER)
+			require.Regexp(t, "^[0-9a-f]{32}$", generatedId)
+		}
+		for _, entry := range getLogEntries(t, out) {
+			require.Regexp(t, "^[0-9a-f]{32}$", entry["request_id"])
+		}
+	})
+
+	testCase.Run("the errors are logged whatever the sampling", func(t *testing.T) {
+		t.Parallel()
+		logger, out := newBufferLogger()
+		accessLogger := NewAccessLogger(logger, 0)
+
+		serveWithAccessLog(accessLogger, http.StatusOK, "ok")
+		serveWithAccessLog(accessLogger, http.StatusNotFound, "not-found")
+		serveWithAccessLog(accessLogger, http.StatusInternalServerError, "failed")
+
+		served := []interface{}{}
+		for _, entry := range getLogEntries(t, out) {
+			if entry["msg"] == "Request served" {
+				served = append(served, entry["request_id"])
+			}
+		}
+		require.Equal(t, []interface{}{"not-found", "failed"}, served)
+	})
+}
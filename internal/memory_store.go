@@ -0,0 +1,940 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	models "issue-service/app/issue-api/routes/models"
+)
+
+// memoryDatabase keeps every table behind a single lock, so that the
+// uniqueness and foreign key checks see a consistent snapshot
+type memoryDatabase struct {
+	mutex    sync.RWMutex
+	projects map[uint]models.Project
+	sprints  map[uint]models.Sprint
+	issues   map[uint]models.Issue
+	// snapshots holds the snapshot of the issues by sprint id
+	snapshots map[uint][]models.SprintSnapshotIssue
+	// changes is the history of the issues, in the order of the changes
+	changes []models.IssueChange
+	// boards holds the board of every project by project id
+	boards map[int]models.Board
+	// slaPolicies holds the SLA policy of every project by project id
+	slaPolicies map[int]models.SlaPolicy
+	slaBreaches []models.SlaBreach
+	// idempotencyRecords holds the records by key
+	idempotencyRecords map[string]models.IdempotencyRecord
+	lastProjectId      uint
+	lastSprintId       uint
+	lastIssueId        uint
+	lastChangeId       uint
+	lastBoardId        uint
+	lastPolicyId       uint
+	lastBreachId       uint
+	lastRecordId       uint
+}
+
+type memoryProjectStore struct {
+	database *memoryDatabase
+}
+
+type memorySprintStore struct {
+	database *memoryDatabase
+}
+
+type memoryIssueStore struct {
+	database *memoryDatabase
+}
+
+type memoryBoardStore struct {
+	database *memoryDatabase
+}
+
+type memorySlaStore struct {
+	database *memoryDatabase
+}
+
+type memoryIdempotencyStore struct {
+	database *memoryDatabase
+}
+
+// NewMemoryStores returns stores that keep everything in memory.
+// They are safe for concurrent use and meant for tests.
+func NewMemoryStores() models.Stores {
+	database := &memoryDatabase{
+		projects:           map[uint]models.Project{},
+		sprints:            map[uint]models.Sprint{},
+		issues:             map[uint]models.Issue{},
+		snapshots:          map[uint][]models.SprintSnapshotIssue{},
+		boards:             map[int]models.Board{},
+		slaPolicies:        map[int]models.SlaPolicy{},
+		idempotencyRecords: map[string]models.IdempotencyRecord{},
+	}
+
+	return models.Stores{
+		Projects:    &memoryProjectStore{database: database},
+		Sprints:     &memorySprintStore{database: database},
+		Issues:      &memoryIssueStore{database: database},
+		Boards:      &memoryBoardStore{database: database},
+		Sla:         &memorySlaStore{database: database},
+		Idempotency: &memoryIdempotencyStore{database: database},
+	}
+}
+
+func sortedKeys[T any](table map[uint]T) []uint {
+	keys := make([]uint, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+// paginateItems selects page of items
+func paginateItems[T any](items []T, page models.Page) []T {
+	if page.Offset >= len(items) {
+		return []T{}
+	}
+	items = items[page.Offset:]
+	if page.Limit > 0 && page.Limit < len(items) {
+		items = items[:page.Limit]
+	}
+	return items
+}
+
+func (store *memoryProjectStore) List(ctx context.Context, page models.Page) ([]models.Project, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	projects := []models.Project{}
+	for _, id := range sortedKeys(store.database.projects) {
+		projects = append(projects, store.database.projects[id])
+	}
+	return paginateItems(projects, page), nil
+}
+
+func (store *memoryProjectStore) Get(ctx context.Context, projectId int) (models.Project, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	project, ok := store.database.projects[uint(projectId)]
+	if !ok {
+		return models.Project{}, ErrNotFound
+	}
+	return project, nil
+}
+
+func (store *memoryProjectStore) Create(ctx context.Context, project *models.Project) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	for _, existing := range store.database.projects {
+		if existing.Name == project.Name {
+			return fmt.Errorf("%w: projects.name", ErrDuplicateKey)
+		}
+	}
+
+	store.database.lastProjectId++
+	now := time.Now()
+	project.ID = store.database.lastProjectId
+	project.CreatedAt = now
+	project.UpdatedAt = now
+	store.database.projects[project.ID] = *project
+	return nil
+}
+
+func (store *memorySprintStore) ListByProject(ctx context.Context, projectId int, page models.Page) ([]models.Sprint, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	sprints := []models.Sprint{}
+	for _, id := range sortedKeys(store.database.sprints) {
+		if sprint := store.database.sprints[id]; sprint.ProjectID == projectId {
+			sprints = append(sprints, sprint)
+		}
+	}
+	return paginateItems(sprints, page), nil
+}
+
+func (store *memorySprintStore) Get(ctx context.Context, projectId int, sprintId int) (models.Sprint, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	sprint, ok := store.database.sprints[uint(sprintId)]
+	if !ok || sprint.ProjectID != projectId {
+		return models.Sprint{}, ErrNotFound
+	}
+	return sprint, nil
+}
+
+func (store *memorySprintStore) Create(ctx context.Context, sprint *models.Sprint) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	if _, ok := store.database.projects[uint(sprint.ProjectID)]; !ok {
+		return fmt.Errorf("%w: fk_sprints_project", ErrForeignKey)
+	}
+	if store.database.hasSprintNumber(sprint.ProjectID, sprint.Number, 0) {
+		return fmt.Errorf("%w: idx_sprints_project_number", ErrDuplicateKey)
+	}
+	if err := store.database.checkSprintDates(*sprint); err != nil {
+		return err
+	}
+
+	store.database.lastSprintId++
+	now := time.Now()
+	sprint.ID = store.database.lastSprintId
+	sprint.CreatedAt = now
+	sprint.UpdatedAt = now
+	store.database.sprints[sprint.ID] = *sprint
+	return nil
+}
+
+func (store *memorySprintStore) Update(ctx context.Context, sprint models.Sprint) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.sprints[sprint.ID]
+	if !ok || found.ProjectID != sprint.ProjectID {
+		return ErrNotFound
+	}
+
+	if sprint.Number != "" {
+		if store.database.hasSprintNumber(found.ProjectID, sprint.Number, found.ID) {
+			return fmt.Errorf("%w: idx_sprints_project_number", ErrDuplicateKey)
+		}
+		found.Number = sprint.Number
+	}
+	if !sprint.StartDate.IsZero() || !sprint.EndDate.IsZero() {
+		if !sprint.StartDate.IsZero() {
+			found.StartDate = sprint.StartDate
+		}
+		if !sprint.EndDate.IsZero() {
+			found.EndDate = sprint.EndDate
+		}
+		if err := store.database.checkSprintDates(found); err != nil {
+			return err
+		}
+	}
+	if sprint.MaxIssuePerSprint != 0 {
+		found.MaxIssuePerSprint = sprint.MaxIssuePerSprint
+	}
+	found.UpdatedAt = time.Now()
+	store.database.sprints[found.ID] = found
+	return nil
+}
+
+func (store *memorySprintStore) GetActive(ctx context.Context, projectId int) (models.Sprint, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	for _, sprint := range store.database.sprints {
+		if sprint.ProjectID == projectId && sprint.IsActive() {
+			return sprint, nil
+		}
+	}
+	return models.Sprint{}, ErrNotFound
+}
+
+func (store *memorySprintStore) Start(ctx context.Context, projectId int, sprintId int, startedAt time.Time) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.sprints[uint(sprintId)]
+	if !ok || found.ProjectID != projectId || found.StartedAt != nil || found.CompletedAt != nil {
+		return ErrNotFound
+	}
+	for _, sprint := range store.database.sprints {
+		if sprint.ProjectID == projectId && sprint.IsActive() {
+			return fmt.Errorf("%w: idx_sprints_project_active", ErrDuplicateKey)
+		}
+	}
+
+	snapshot := []models.SprintSnapshotIssue{}
+	for _, issue := range store.database.getSprintIssues(projectId, sprintId) {
+		snapshot = append(snapshot, getSnapshotIssue(found.ID, issue, true))
+	}
+	store.database.snapshots[found.ID] = snapshot
+	found.StartedAt = &startedAt
+	found.UpdatedAt = time.Now()
+	store.database.sprints[found.ID] = found
+	return nil
+}
+
+func (store *memorySprintStore) Complete(ctx context.Context, projectId int, sprintId int, nextSprintId int, completedAt time.Time) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.sprints[uint(sprintId)]
+	if !ok || found.ProjectID != projectId || !found.IsActive() {
+		return ErrNotFound
+	}
+	if nextSprintId != 0 {
+		next, ok := store.database.sprints[uint(nextSprintId)]
+		if !ok || next.ProjectID != projectId || next.CompletedAt != nil {
+			return ErrNotFound
+		}
+		found.NextSprintID = &nextSprintId
+	}
+
+	issues := store.database.getSprintIssues(projectId, sprintId)
+	snapshot, unfinishedIssueIds := buildSprintSnapshot(found.ID, store.database.snapshots[found.ID], issues)
+	now := time.Now()
+	for _, issueId := range unfinishedIssueIds {
+		issue := store.database.issues[issueId]
+		issue.SprintID = nextSprintId
+		issue.UpdatedAt = now
+		store.database.issues[issueId] = issue
+	}
+	for _, change := range getCarriedOverIssueChanges(issues, unfinishedIssueIds, nextSprintId, completedAt) {
+		store.database.addIssueChange(change)
+	}
+	store.database.snapshots[found.ID] = snapshot
+	found.Completed = true
+	found.CompletedAt = &completedAt
+	found.UpdatedAt = now
+	store.database.sprints[found.ID] = found
+	return nil
+}
+
+func (store *memorySprintStore) ListSnapshot(ctx context.Context, projectId int, sprintId int) ([]models.SprintSnapshotIssue, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	snapshot := []models.SprintSnapshotIssue{}
+	if sprint, ok := store.database.sprints[uint(sprintId)]; ok && sprint.ProjectID == projectId {
+		snapshot = append(snapshot, store.database.snapshots[sprint.ID]...)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].IssueID < snapshot[j].IssueID })
+	return snapshot, nil
+}
+
+func (store *memorySprintStore) SumPoints(ctx context.Context, projectId int, sprintIds []uint) (map[uint]models.SprintPoints, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	points := map[uint]models.SprintPoints{}
+	for _, sprintId := range sprintIds {
+		sprint, ok := store.database.sprints[sprintId]
+		if !ok || sprint.ProjectID != projectId {
+			continue
+		}
+		sprintPoints := models.SprintPoints{SprintID: sprintId}
+		for _, issue := range store.database.getSprintIssues(projectId, int(sprintId)) {
+			if issue.StoryPoints == nil {
+				continue
+			}
+			sprintPoints.IssuePoints += *issue.StoryPoints
+			if models.IsClosedIssueStatus(issue.Status) {
+				sprintPoints.ClosedIssuePoints += *issue.StoryPoints
+			}
+		}
+		for _, issue := range store.database.snapshots[sprintId] {
+			storyPoints := 0
+			if issue.StoryPoints != nil {
+				storyPoints = *issue.StoryPoints
+			}
+			if issue.Committed {
+				if issue.CommittedStoryPoints != nil {
+					sprintPoints.CommittedPoints += *issue.CommittedStoryPoints
+				}
+				sprintPoints.CommittedIssues++
+			}
+			if issue.Outcome == models.SPRINT_OUTCOME_COMPLETED {
+				sprintPoints.CompletedPoints += storyPoints
+				sprintPoints.CompletedIssues++
+			}
+		}
+		points[sprintId] = sprintPoints
+	}
+	return points, nil
+}
+
+func (store *memorySprintStore) CountActiveByProject(ctx context.Context) (map[int]int, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	counts := map[int]int{}
+	for _, sprint := range store.database.sprints {
+		if sprint.IsActive() {
+			counts[sprint.ProjectID]++
+		}
+	}
+	return counts, nil
+}
+
+func (database *memoryDatabase) checkSprintDates(sprint models.Sprint) error {
+	for id, other := range database.sprints {
+		if id != sprint.ID && other.ProjectID == sprint.ProjectID && sprint.Overlaps(other) {
+			return fmt.Errorf("%w: sprint %d", ErrOverlap, id)
+		}
+	}
+	return nil
+}
+
+func (database *memoryDatabase) hasSprintNumber(projectId int, number string, excludedSprintId uint) bool {
+	for id, existing := range database.sprints {
+		if id != excludedSprintId && existing.ProjectID == projectId && existing.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+func (store *memoryIssueStore) ListBySprint(ctx context.Context, projectId int, sprintId int, page models.Page) ([]models.Issue, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	return paginateItems(store.database.getSprintIssues(projectId, sprintId), page), nil
+}
+
+// getSprintIssues returns the issues of a sprint, or of the backlog when sprintId is 0, ordered by rank and id
+func (database *memoryDatabase) getSprintIssues(projectId int, sprintId int) []models.Issue {
+	issues := []models.Issue{}
+	for _, id := range sortedKeys(database.issues) {
+		if issue := database.issues[id]; issue.ProjectID == projectId && issue.SprintID == sprintId {
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Rank < issues[j].Rank })
+	return issues
+}
+
+func (store *memoryIssueStore) Get(ctx context.Context, projectId int, sprintId int, issueId uint) (models.Issue, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	issue, ok := store.database.issues[issueId]
+	if !ok || issue.ProjectID != projectId || issue.SprintID != sprintId {
+		return models.Issue{}, ErrNotFound
+	}
+	return issue, nil
+}
+
+func (store *memoryIssueStore) Create(ctx context.Context, issue *models.Issue) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	if err := store.database.checkIssueReferences(issue.ProjectID, issue.SprintID); err != nil {
+		return err
+	}
+
+	store.database.lastIssueId++
+	now := time.Now()
+	issue.ID = store.database.lastIssueId
+	issue.Rank = RankBetween(store.database.getLastRank(issue.ProjectID), "")
+	issue.CreatedAt = now
+	issue.UpdatedAt = now
+	stored := *issue
+	stored.StoryPoints = copyInt(issue.StoryPoints)
+	stored.OriginalEstimateMinutes = copyInt(issue.OriginalEstimateMinutes)
+	stored.RemainingEstimateMinutes = copyInt(issue.RemainingEstimateMinutes)
+	stored.LoggedMinutes = copyInt(issue.LoggedMinutes)
+	store.database.issues[issue.ID] = stored
+	store.database.addIssueChange(getIssueChange(stored, now))
+	return nil
+}
+
+// getLastRank returns the greatest rank of the issues of the project, empty when it has no issue
+func (database *memoryDatabase) getLastRank(projectId int) string {
+	lastRank := ""
+	for _, issue := range database.issues {
+		if issue.ProjectID == projectId && issue.Rank > lastRank {
+			lastRank = issue.Rank
+		}
+	}
+	return lastRank
+}
+
+func (database *memoryDatabase) addIssueChange(change models.IssueChange) {
+	database.lastChangeId++
+	change.ID = database.lastChangeId
+	if change.StoryPoints != nil {
+		change.StoryPoints = copyInt(change.StoryPoints)
+	}
+	database.changes = append(database.changes, change)
+}
+
+func (store *memoryIssueStore) Update(ctx context.Context, issue models.Issue) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.issues[issue.ID]
+	if !ok || found.ProjectID != issue.ProjectID || found.SprintID != issue.SprintID {
+		return ErrNotFound
+	}
+
+	if issue.Type != "" {
+		found.Type = issue.Type
+	}
+	if issue.Title != "" {
+		found.Title = issue.Title
+	}
+	if issue.Description != "" {
+		found.Description = issue.Description
+	}
+	if issue.Status != "" {
+		found.Status = issue.Status
+	}
+	if issue.Assignee != "" {
+		found.Assignee = issue.Assignee
+	}
+	if issue.Priority != "" {
+		found.Priority = issue.Priority
+	}
+	if issue.Epic != "" {
+		found.Epic = issue.Epic
+	}
+	if issue.StoryPoints != nil {
+		found.StoryPoints = copyInt(issue.StoryPoints)
+	}
+	if issue.Size != "" {
+		found.Size = issue.Size
+	}
+	if issue.OriginalEstimateMinutes != nil {
+		found.OriginalEstimateMinutes = copyInt(issue.OriginalEstimateMinutes)
+	}
+	if issue.RemainingEstimateMinutes != nil {
+		found.RemainingEstimateMinutes = copyInt(issue.RemainingEstimateMinutes)
+	}
+	if issue.LoggedMinutes != nil {
+		found.LoggedMinutes = copyInt(issue.LoggedMinutes)
+	}
+	found.UpdatedAt = time.Now()
+	store.database.issues[found.ID] = found
+	// the history only follows the status and the points
+	if issue.Status != "" || issue.StoryPoints != nil {
+		store.database.addIssueChange(getIssueChange(found, found.UpdatedAt))
+	}
+	return nil
+}
+
+func (store *memoryIssueStore) Move(ctx context.Context, issue models.Issue, targetProjectId int, targetSprintId int) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.issues[issue.ID]
+	if !ok || found.ProjectID != issue.ProjectID || found.SprintID != issue.SprintID {
+		return ErrNotFound
+	}
+	if err := store.database.checkIssueReferences(targetProjectId, targetSprintId); err != nil {
+		return err
+	}
+
+	if targetProjectId != found.ProjectID {
+		found.Rank = RankBetween(store.database.getLastRank(targetProjectId), "")
+	}
+	found.ProjectID = targetProjectId
+	found.SprintID = targetSprintId
+	found.UpdatedAt = time.Now()
+	store.database.issues[found.ID] = found
+	store.database.addIssueChange(getIssueChange(found, found.UpdatedAt))
+	return nil
+}
+
+func (store *memoryIssueStore) GetAdjacentRanks(ctx context.Context, projectId int, issueId uint, after bool, excludedIssueId uint) (string, string, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	found, ok := store.database.issues[issueId]
+	if !ok || found.ProjectID != projectId {
+		return "", "", ErrNotFound
+	}
+	adjacent := ""
+	for _, issue := range store.database.issues {
+		if issue.ProjectID != projectId || issue.ID == excludedIssueId {
+			continue
+		}
+		if after && issue.Rank > found.Rank && (adjacent == "" || issue.Rank < adjacent) {
+			adjacent = issue.Rank
+		}
+		if !after && issue.Rank < found.Rank && issue.Rank > adjacent {
+			adjacent = issue.Rank
+		}
+	}
+	return found.Rank, adjacent, nil
+}
+
+func (store *memoryIssueStore) SetRank(ctx context.Context, projectId int, issueId uint, rank string) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.issues[issueId]
+	if !ok || found.ProjectID != projectId {
+		return ErrNotFound
+	}
+	found.Rank = rank
+	found.UpdatedAt = time.Now()
+	store.database.issues[found.ID] = found
+	store.database.rebalanceLongRanks(projectId, rank)
+	return nil
+}
+
+// rebalanceLongRanks ranks again the issues of the project in their order with the shortest ranks,
+// when rank is longer than rankRebalanceLength
+func (database *memoryDatabase) rebalanceLongRanks(projectId int, rank string) {
+	if len(rank) <= rankRebalanceLength {
+		return
+	}
+	issues := database.getProjectIssues(projectId, models.IssueFilter{})
+	for index, evenRank := range getEvenRanks(len(issues)) {
+		issue := database.issues[issues[index].ID]
+		issue.Rank = evenRank
+		database.issues[issue.ID] = issue
+	}
+}
+
+func (store *memoryIssueStore) MoveOnBoard(ctx context.Context, projectId int, issueId uint, column models.BoardColumn, status string, rank string) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	board, ok := store.database.boards[projectId]
+	if !ok {
+		return ErrNotFound
+	}
+	found, ok := store.database.issues[issueId]
+	if !ok || found.ProjectID != projectId || !store.database.inBoardScope(board, found) {
+		return ErrNotFound
+	}
+	if status == "" {
+		status = column.Statuses[0]
+		if column.HasStatus(found.Status) {
+			status = found.Status
+		}
+	}
+	// the issues already in the column move within it, even when it is over its limit
+	if column.WipLimit != nil && !column.HasStatus(found.Status) {
+		count := 0
+		for _, issue := range store.database.issues {
+			if issue.ProjectID == projectId && column.HasStatus(issue.Status) && store.database.inBoardScope(board, issue) {
+				count++
+			}
+		}
+		if count >= *column.WipLimit {
+			return ErrWipLimit
+		}
+	}
+
+	previousStatus := found.Status
+	found.Status = status
+	if rank != "" {
+		found.Rank = rank
+	}
+	found.UpdatedAt = time.Now()
+	store.database.issues[found.ID] = found
+	store.database.rebalanceLongRanks(projectId, rank)
+	if status != previousStatus {
+		store.database.addIssueChange(getIssueChange(found, found.UpdatedAt))
+	}
+	return nil
+}
+
+// inBoardScope tells whether the issue is on the board, the caller holds the mutex
+func (database *memoryDatabase) inBoardScope(board models.Board, issue models.Issue) bool {
+	if issue.SprintID == 0 {
+		return board.InScope(nil)
+	}
+	sprint, ok := database.sprints[uint(issue.SprintID)]
+	return ok && board.InScope(&sprint)
+}
+
+func (store *memoryIssueStore) ListSprintChanges(ctx context.Context, projectId int, sprintId int) ([]models.IssueChange, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	sprintIssueIds := map[uint]bool{}
+	for _, change := range store.database.changes {
+		if change.ProjectID == projectId && change.SprintID == sprintId {
+			sprintIssueIds[change.IssueID] = true
+		}
+	}
+	changes := []models.IssueChange{}
+	for _, change := range store.database.changes {
+		if sprintIssueIds[change.IssueID] {
+			changes = append(changes, change)
+		}
+	}
+	// the completions record their changes at the completion time given by the caller
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+	return changes, nil
+}
+
+// getProjectIssues returns the issues of the project matching filter, ordered by rank
+func (database *memoryDatabase) getProjectIssues(projectId int, filter models.IssueFilter) []models.Issue {
+	issues := []models.Issue{}
+	for _, id := range sortedKeys(database.issues) {
+		issue := database.issues[id]
+		if issue.ProjectID == projectId && (filter.Type == "" || issue.Type == filter.Type) &&
+			(filter.Assignee == "" || issue.Assignee == filter.Assignee) && (!filter.Open || !models.IsClosedIssueStatus(issue.Status)) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Rank < issues[j].Rank })
+	return issues
+}
+
+func (store *memoryIssueStore) ListByProject(ctx context.Context, projectId int, filter models.IssueFilter) ([]models.Issue, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	return store.database.getProjectIssues(projectId, filter), nil
+}
+
+func (store *memoryIssueStore) ListProjectChanges(ctx context.Context, projectId int, filter models.IssueFilter) ([]models.IssueChange, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	projectIssueIds := map[uint]bool{}
+	for _, issue := range store.database.getProjectIssues(projectId, filter) {
+		projectIssueIds[issue.ID] = true
+	}
+	changes := []models.IssueChange{}
+	for _, change := range store.database.changes {
+		if projectIssueIds[change.IssueID] {
+			changes = append(changes, change)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+	return changes, nil
+}
+
+func (store *memoryIssueStore) CountOpenByProject(ctx context.Context) (map[int]int, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	counts := map[int]int{}
+	for _, issue := range store.database.issues {
+		if !models.IsClosedIssueStatus(issue.Status) {
+			counts[issue.ProjectID]++
+		}
+	}
+	return counts, nil
+}
+
+func (store *memoryIssueStore) SumOpenWork(ctx context.Context, projectId int) (models.OpenWork, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	var work models.OpenWork
+	for _, issue := range store.database.issues {
+		if issue.ProjectID != projectId || models.IsClosedIssueStatus(issue.Status) {
+			continue
+		}
+		work.Issues++
+		if issue.StoryPoints == nil {
+			work.UnestimatedIssues++
+		} else {
+			work.Points += *issue.StoryPoints
+		}
+	}
+	return work, nil
+}
+
+func (database *memoryDatabase) checkIssueReferences(projectId int, sprintId int) error {
+	if _, ok := database.projects[uint(projectId)]; !ok {
+		return fmt.Errorf("%w: fk_issues_project", ErrForeignKey)
+	}
+	// an issue of the backlog has no sprint
+	if _, ok := database.sprints[uint(sprintId)]; !ok && sprintId != 0 {
+		return fmt.Errorf("%w: fk_issues_sprint", ErrForeignKey)
+	}
+	return nil
+}
+
+// copyBoard copies the columns of board, so that the stored board is not shared with the callers
+func copyBoard(board models.Board) models.Board {
+	columns := make([]models.BoardColumn, 0, len(board.Columns))
+	for _, column := range board.Columns {
+		column.Statuses = append([]string{}, column.Statuses...)
+		if column.WipLimit != nil {
+			column.WipLimit = copyInt(column.WipLimit)
+		}
+		columns = append(columns, column)
+	}
+	board.Columns = columns
+	return board
+}
+
+func (store *memoryBoardStore) Get(ctx context.Context, projectId int) (models.Board, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	board, ok := store.database.boards[projectId]
+	if !ok {
+		return models.Board{}, ErrNotFound
+	}
+	return copyBoard(board), nil
+}
+
+func (store *memoryBoardStore) Save(ctx context.Context, board *models.Board) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	if _, ok := store.database.projects[uint(board.ProjectID)]; !ok {
+		return fmt.Errorf("%w: fk_boards_project", ErrForeignKey)
+	}
+	now := time.Now()
+	if existing, ok := store.database.boards[board.ProjectID]; ok {
+		board.ID, board.CreatedAt = existing.ID, existing.CreatedAt
+	} else {
+		store.database.lastBoardId++
+		board.ID, board.CreatedAt = store.database.lastBoardId, now
+	}
+	board.UpdatedAt = now
+	store.database.boards[board.ProjectID] = copyBoard(*board)
+	return nil
+}
+
+// copyInt keeps the stored issues from sharing the pointers of the callers, nil stays nil
+func copyInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+// copySlaPolicy copies the paused statuses and the targets of policy, so that the stored policy is not shared with the callers
+func copySlaPolicy(policy models.SlaPolicy) models.SlaPolicy {
+	policy.PausedStatuses = append([]string{}, policy.PausedStatuses...)
+	targets := make([]models.SlaTarget, 0, len(policy.Targets))
+	for _, target := range policy.Targets {
+		if target.ResponseMinutes != nil {
+			target.ResponseMinutes = copyInt(target.ResponseMinutes)
+		}
+		if target.ResolutionMinutes != nil {
+			target.ResolutionMinutes = copyInt(target.ResolutionMinutes)
+		}
+		targets = append(targets, target)
+	}
+	policy.Targets = targets
+	return policy
+}
+
+func (store *memorySlaStore) GetPolicy(ctx context.Context, projectId int) (models.SlaPolicy, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	policy, ok := store.database.slaPolicies[projectId]
+	if !ok {
+		return models.SlaPolicy{}, ErrNotFound
+	}
+	return copySlaPolicy(policy), nil
+}
+
+func (store *memorySlaStore) SavePolicy(ctx context.Context, policy *models.SlaPolicy) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	if _, ok := store.database.projects[uint(policy.ProjectID)]; !ok {
+		return fmt.Errorf("%w: fk_sla_policies_project", ErrForeignKey)
+	}
+	now := time.Now()
+	if existing, ok := store.database.slaPolicies[policy.ProjectID]; ok {
+		policy.ID, policy.CreatedAt = existing.ID, existing.CreatedAt
+	} else {
+		store.database.lastPolicyId++
+		policy.ID, policy.CreatedAt = store.database.lastPolicyId, now
+	}
+	policy.UpdatedAt = now
+	store.database.slaPolicies[policy.ProjectID] = copySlaPolicy(*policy)
+	return nil
+}
+
+func (store *memorySlaStore) ListPolicies(ctx context.Context) ([]models.SlaPolicy, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	policies := []models.SlaPolicy{}
+	for _, policy := range store.database.slaPolicies {
+		policies = append(policies, copySlaPolicy(policy))
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ProjectID < policies[j].ProjectID })
+	return policies, nil
+}
+
+func (store *memorySlaStore) ListBreaches(ctx context.Context, projectId int) ([]models.SlaBreach, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	breaches := []models.SlaBreach{}
+	for _, breach := range store.database.slaBreaches {
+		if breach.ProjectID == projectId {
+			breaches = append(breaches, breach)
+		}
+	}
+	return breaches, nil
+}
+
+func (store *memorySlaStore) FlagBreach(ctx context.Context, breach *models.SlaBreach) (bool, error) {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	if _, ok := store.database.issues[breach.IssueID]; !ok {
+		return false, fmt.Errorf("%w: fk_sla_breaches_issue", ErrForeignKey)
+	}
+	for _, flagged := range store.database.slaBreaches {
+		if flagged.IssueID == breach.IssueID && flagged.Kind == breach.Kind {
+			return false, nil
+		}
+	}
+	store.database.lastBreachId++
+	breach.ID, breach.CreatedAt = store.database.lastBreachId, time.Now()
+	store.database.slaBreaches = append(store.database.slaBreaches, *breach)
+	return true, nil
+}
+
+func (store *memoryIdempotencyStore) Create(ctx context.Context, record *models.IdempotencyRecord, expiredBefore time.Time) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	for key, existing := range store.database.idempotencyRecords {
+		if existing.CreatedAt.Before(expiredBefore) {
+			delete(store.database.idempotencyRecords, key)
+		}
+	}
+	if _, ok := store.database.idempotencyRecords[record.Key]; ok {
+		return fmt.Errorf("%w: idx_idempotency_records_key", ErrDuplicateKey)
+	}
+	store.database.lastRecordId++
+	record.ID, record.CreatedAt = store.database.lastRecordId, time.Now()
+	store.database.idempotencyRecords[record.Key] = *record
+	return nil
+}
+
+func (store *memoryIdempotencyStore) Get(ctx context.Context, key string) (models.IdempotencyRecord, error) {
+	store.database.mutex.RLock()
+	defer store.database.mutex.RUnlock()
+
+	record, ok := store.database.idempotencyRecords[key]
+	if !ok {
+		return models.IdempotencyRecord{}, ErrNotFound
+	}
+	return record, nil
+}
+
+func (store *memoryIdempotencyStore) Complete(ctx context.Context, record models.IdempotencyRecord) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	found, ok := store.database.idempotencyRecords[record.Key]
+	if !ok {
+		return ErrNotFound
+	}
+	found.StatusCode, found.ContentType = record.StatusCode, record.ContentType
+	found.Body = append([]byte{}, record.Body...)
+	store.database.idempotencyRecords[record.Key] = found
+	return nil
+}
+
+func (store *memoryIdempotencyStore) Delete(ctx context.Context, key string) error {
+	store.database.mutex.Lock()
+	defer store.database.mutex.Unlock()
+
+	delete(store.database.idempotencyRecords, key)
+	return nil
+}
@@ -0,0 +1,81 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	models "issue-service/app/issue-api/routes/models"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStores(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("concurrent creates get unique ids", func(t *testing.T) {
+		t.Parallel()
+		stores := NewMemoryStores()
+		projectId, sprintId := CreateProjectAndSprint(stores)
+
+		var waitGroup sync.WaitGroup
+		for index := 0; index < 50; index++ {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				CreateTestIssue(stores, int(projectId), int(sprintId))
+			}()
+		}
+		waitGroup.Wait()
+
+		issues, err := stores.Issues.ListBySprint(context.Background(), int(projectId), int(sprintId), models.Page{})
+		require.Equal(t, nil, err)
+		require.Equal(t, 50, len(issues))
+		for index := 1; index < len(issues); index++ {
+			require.Less(t, issues[index-1].ID, issues[index].ID)
+		}
+	})
+
+	testCase.Run("create returns duplicate and foreign key errors", func(t *testing.T) {
+		t.Parallel()
+		stores := NewMemoryStores()
+		projectId, _ := CreateProjectAndSprint(stores)
+
+		project, _ := stores.Projects.Get(context.Background(), int(projectId))
+		duplicateProject := models.Project{Name: project.Name}
+		err := stores.Projects.Create(context.Background(), &duplicateProject)
+		require.True(t, IsDuplicateKeyError(err), fmt.Sprint(err))
+
+		orphanSprint := models.Sprint{Number: "1", ProjectID: 99999}
+		err = stores.Sprints.Create(context.Background(), &orphanSprint)
+		require.True(t, IsForeignKeyError(err), fmt.Sprint(err))
+	})
+
+	testCase.Run("create does not keep the pointers of the caller", func(t *testing.T) {
+		t.Parallel()
+		stores := NewMemoryStores()
+		projectId, sprintId := CreateProjectAndSprint(stores)
+		storyPoints, loggedMinutes := 3, 60
+		issue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Task", Title: "Title", StoryPoints: &storyPoints, LoggedMinutes: &loggedMinutes}
+		require.Equal(t, nil, stores.Issues.Create(context.Background(), &issue))
+
+		storyPoints, loggedMinutes = 8, 120
+		stored, err := stores.Issues.Get(context.Background(), int(projectId), int(sprintId), issue.ID)
+		require.Equal(t, nil, err)
+		require.Equal(t, 3, *stored.StoryPoints)
+		require.Equal(t, 60, *stored.LoggedMinutes)
+	})
+
+	testCase.Run("lookups are scoped to the parent", func(t *testing.T) {
+		t.Parallel()
+		stores := NewMemoryStores()
+		projectId, sprintId := CreateProjectAndSprint(stores)
+		otherProjectId := CreateTestProject(stores)
+
+		_, err := stores.Sprints.Get(context.Background(), int(otherProjectId), int(sprintId))
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = stores.Sprints.Get(context.Background(), int(projectId), int(sprintId))
+		require.Equal(t, nil, err)
+	})
+}
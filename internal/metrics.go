@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	models "issue-service/app/issue-api/routes/models"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
+)
+
+const metricsNamespace = "issue_service"
+
+// storeCollectorTimeout bounds the queries run on every scrape
+const storeCollectorTimeout = 5 * time.Second
+
+// Metrics owns a registry, so that every router built by the tests gets its own metrics
+type Metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// NewMetrics registers the HTTP, Go runtime and process metrics, plus the given collectors
+func NewMetrics(extraCollectors ...prometheus.Collector) *Metrics {
+	metrics := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route name and status code.",
+		}, []string{"route", "code"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of the HTTP requests by route name and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "code"}),
+	}
+
+	metrics.registry.MustRegister(
+		metrics.requests,
+		metrics.requestDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	metrics.registry.MustRegister(extraCollectors...)
+	return metrics
+}
+
+// Instrument counts and times the requests served by inner under the route name
+func (metrics *Metrics) Instrument(inner http.Handler, routeName string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		responseWriter := negroni.NewResponseWriter(w)
+
+		inner.ServeHTTP(responseWriter, r)
+
+		code := strconv.Itoa(responseWriter.Status())
+		metrics.requests.WithLabelValues(routeName, code).Inc()
+		metrics.requestDuration.WithLabelValues(routeName, code).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (metrics *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(metrics.registry, promhttp.HandlerOpts{})
+}
+
+type storeCollector struct {
+	stores        models.Stores
+	openIssues    *prometheus.Desc
+	activeSprints *prometheus.Desc
+}
+
+// NewStoreCollector exposes the domain gauges, queried from the stores on every scrape
+func NewStoreCollector(stores models.Stores) prometheus.Collector {
+	return &storeCollector{
+		stores: stores,
+		openIssues: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "open_issues"),
+			"Number of issues whose status is not closed, by project.",
+			[]string{"project_id"}, nil,
+		),
+		activeSprints: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "active_sprints"),
+			"Number of sprints started and not completed, by project.",
+			[]string{"project_id"}, nil,
+		),
+	}
+}
+
+func (collector *storeCollector) Describe(descriptions chan<- *prometheus.Desc) {
+	descriptions <- collector.openIssues
+	descriptions <- collector.activeSprints
+}
+
+func (collector *storeCollector) Collect(metrics chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeCollectorTimeout)
+	defer cancel()
+
+	openIssues, err := collector.stores.Issues.CountOpenByProject(ctx)
+	collectProjectCounts(metrics, collector.openIssues, openIssues, err)
+
+	activeSprints, err := collector.stores.Sprints.CountActiveByProject(ctx)
+	collectProjectCounts(metrics, collector.activeSprints, activeSprints, err)
+}
+
+func collectProjectCounts(metrics chan<- prometheus.Metric, description *prometheus.Desc, counts map[int]int, err error) {
+	if err != nil {
+		log.WithField("error", err.Error()).Error("Error collecting metrics")
+		metrics <- prometheus.NewInvalidMetric(description, err)
+		return
+	}
+	for projectId, count := range counts {
+		metrics <- prometheus.MustNewConstMetric(description, prometheus.GaugeValue, float64(count), strconv.Itoa(projectId))
+	}
+}
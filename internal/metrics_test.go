@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	models "issue-service/app/issue-api/routes/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectCounts(testCase *testing.T) {
+	testCase.Parallel()
+
+	newStores := map[string]func(t *testing.T) models.Stores{
+		"memory": func(t *testing.T) models.Stores {
+			return NewMemoryStores()
+		},
+		"gorm": func(t *testing.T) models.Stores {
+			database := connectTestSqliteDatabase(t)
+			_, err := MigrateUp(database)
+			require.NoError(t, err)
+			return NewGormStores(database)
+		},
+	}
+
+	for name, newStore := range newStores {
+		newStore := newStore
+		testCase.Run(name, func(t *testing.T) {
+			t.Parallel()
+			stores := newStore(t)
+			projectId, sprintId := CreateProjectAndSprint(stores)
+			futureSprint := models.Sprint{
+				ID:        CreateTestSprint(stores, "future", int(projectId)),
+				ProjectID: int(projectId),
+				StartDate: time.Now().AddDate(0, 1, 0),
+			}
+			require.NoError(t, stores.Sprints.Update(context.Background(), futureSprint))
+			completedSprintId := int(CreateTestSprint(stores, "completed", int(projectId)))
+			require.NoError(t, stores.Sprints.Start(context.Background(), int(projectId), completedSprintId, time.Now()))
+			require.NoError(t, stores.Sprints.Complete(context.Background(), int(projectId), completedSprintId, 0, time.Now()))
+			require.NoError(t, stores.Sprints.Start(context.Background(), int(projectId), int(sprintId), time.Now()))
+
+			CreateTestIssue(stores, int(projectId), int(sprintId))
+			closedIssue := models.Issue{ProjectID: int(projectId), SprintID: int(sprintId), Type: "Task", Title: "Closed", Status: "Done"}
+			require.NoError(t, stores.Issues.Create(context.Background(), &closedIssue))
+			otherProjectId, otherSprintId := CreateProjectAndSprint(stores)
+			CreateTestIssue(stores, int(otherProjectId), int(otherSprintId))
+			CreateTestIssue(stores, int(otherProjectId), int(otherSprintId))
+
+			openIssues, err := stores.Issues.CountOpenByProject(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, map[int]int{int(projectId): 1, int(otherProjectId): 2}, openIssues)
+
+			// the sprint of the other project is planned and not started
+			activeSprints, err := stores.Sprints.CountActiveByProject(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, map[int]int{int(projectId): 1}, activeSprints)
+		})
+	}
+}
+
+func TestMetrics(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("requests are counted by route and status code", func(t *testing.T) {
+		t.Parallel()
+		metrics := NewMetrics()
+		handler := metrics.Instrument(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}), "AddProject")
+
+		for index := 0; index < 3; index++ {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/projects", nil))
+		}
+
+		require.Equal(t, float64(3), testutil.ToFloat64(metrics.requests.WithLabelValues("AddProject", "409")))
+		require.Equal(t, 1, testutil.CollectAndCount(metrics.requestDuration))
+	})
+
+	testCase.Run("the store gauges are labelled by project", func(t *testing.T) {
+		t.Parallel()
+		stores := NewMemoryStores()
+		projectId, sprintId := CreateProjectAndSprint(stores)
+		CreateTestIssue(stores, int(projectId), int(sprintId))
+
+		expected := fmt.Sprintf(`
+# HELP issue_service_open_issues Number of issues whose status is not closed, by project.
+# TYPE issue_service_open_issues gauge
+issue_service_open_issues{project_id="%d"} 1
+`, projectId)
+		require.NoError(t, testutil.CollectAndCompare(NewStoreCollector(stores), strings.NewReader(expected), "issue_service_open_issues"))
+	})
+}
@@ -0,0 +1,316 @@
+package internal
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Migrations live in migrations/<driver>/<version>_<name>.<up|down>.sql,
+// every dialect must provide the same versions.
+// The first migration also has an adopt file, see adoptBaselineSchema.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+const schemaMigrationsTable = "schema_migrations"
+
+// migrationLockId is the postgres advisory lock key shared by every instance of the service
+const migrationLockId = 4_815_162_342
+
+// baselineTable is created by the first migration, and by the AutoMigrate of the versions before the migrations
+const baselineTable = "projects"
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+	// Adopt brings a schema created before the migrations to the state of the migration, it is optional
+	Adopt string
+}
+
+type MigrationStatus struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+}
+
+func (status MigrationStatus) Applied() bool {
+	return status.AppliedAt != nil
+}
+
+type schemaMigration struct {
+	Version   uint `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return schemaMigrationsTable
+}
+
+// LoadMigrations returns the migrations of the given driver sorted by version
+func LoadMigrations(driver string) ([]Migration, error) {
+	directory := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrationFiles, directory)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database driver \"%s\"", driver)
+	}
+
+	migrationsByVersion := map[uint]*Migration{}
+	for _, entry := range entries {
+		version, name, direction, err := parseMigrationFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		content, err := fs.ReadFile(migrationFiles, path.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrationsByVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			migrationsByVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: \"%s\" and \"%s\"", version, migration.Name, name)
+		}
+		switch direction {
+		case "up":
+			migration.Up = string(content)
+		case "down":
+			migration.Down = string(content)
+		case "adopt":
+			migration.Adopt = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+func parseMigrationFileName(fileName string) (uint, string, string, error) {
+	parts := strings.Split(fileName, ".")
+	if len(parts) != 3 || parts[2] != "sql" || (parts[1] != "up" && parts[1] != "down" && parts[1] != "adopt") {
+		return 0, "", "", fmt.Errorf("invalid migration file name \"%s\"", fileName)
+	}
+
+	versionAndName := strings.SplitN(parts[0], "_", 2)
+	version, err := strconv.ParseUint(versionAndName[0], 10, 32)
+	if err != nil || len(versionAndName) != 2 || version == 0 {
+		return 0, "", "", fmt.Errorf("invalid migration file name \"%s\"", fileName)
+	}
+	return uint(version), versionAndName[1], parts[1], nil
+}
+
+// withMigrationLock runs fn on a single connection holding the migration lock.
+// On sqlite the connection string opens every transaction with BEGIN IMMEDIATE,
+// so concurrent migrators are already serialised on the database file.
+func withMigrationLock(database *gorm.DB, fn func(connection *gorm.DB) error) error {
+	return database.Connection(func(connection *gorm.DB) error {
+		if connection.Dialector.Name() == DRIVER_POSTGRES {
+			if err := connection.Exec("SELECT pg_advisory_lock(?)", migrationLockId).Error; err != nil {
+				return fmt.Errorf("acquiring the migration lock: %w", err)
+			}
+			defer connection.Exec("SELECT pg_advisory_unlock(?)", migrationLockId)
+		}
+
+		if err := createSchemaMigrationsTable(connection); err != nil {
+			return err
+		}
+		return fn(connection)
+	})
+}
+
+func createSchemaMigrationsTable(database *gorm.DB) error {
+	return database.Exec(fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %s (version bigint PRIMARY KEY, name text NOT NULL, applied_at timestamp NOT NULL)",
+		schemaMigrationsTable,
+	)).Error
+}
+
+func getAppliedMigrations(database *gorm.DB) ([]schemaMigration, error) {
+	applied := []schemaMigration{}
+	err := database.Order("version").Find(&applied).Error
+	return applied, err
+}
+
+func isMigrationApplied(database *gorm.DB, version uint) (bool, error) {
+	var count int64
+	err := database.Model(&schemaMigration{}).Where("version = ?", version).Count(&count).Error
+	return count > 0, err
+}
+
+// MigrateUp applies every pending migration, each one in its own transaction
+func MigrateUp(database *gorm.DB) ([]Migration, error) {
+	migrations, err := LoadMigrations(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []Migration{}
+	err = withMigrationLock(database, func(connection *gorm.DB) error {
+		if len(migrations) > 0 {
+			adopted, err := adoptBaselineSchema(connection, migrations[0])
+			if err != nil {
+				return fmt.Errorf("adopting the existing schema as migration %d_%s: %w", migrations[0].Version, migrations[0].Name, err)
+			}
+			if adopted {
+				log.WithField("version", migrations[0].Version).Info(fmt.Sprintf("Adopted the existing schema as migration %s", migrations[0].Name))
+				applied = append(applied, migrations[0])
+			}
+		}
+
+		for _, migration := range migrations {
+			done := false
+			err := connection.Transaction(func(tx *gorm.DB) error {
+				// checked inside the transaction, another instance may have just applied it
+				if isApplied, err := isMigrationApplied(tx, migration.Version); err != nil || isApplied {
+					return err
+				}
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				done = true
+				return tx.Create(&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now().UTC(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			if done {
+				log.WithField("version", migration.Version).Info(fmt.Sprintf("Applied migration %s", migration.Name))
+				applied = append(applied, migration)
+			}
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// adoptBaselineSchema records the first migration as applied when no migration is applied but its tables exist:
+// the versions before the migrations created them with AutoMigrate. Its adopt file first reshapes them as the migration would have.
+func adoptBaselineSchema(connection *gorm.DB, first Migration) (bool, error) {
+	if first.Adopt == "" {
+		return false, nil
+	}
+	adopted := false
+	err := connection.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&schemaMigration{}).Count(&count).Error; err != nil || count > 0 {
+			return err
+		}
+		if !tx.Migrator().HasTable(baselineTable) {
+			return nil
+		}
+		if err := tx.Exec(first.Adopt).Error; err != nil {
+			return err
+		}
+		adopted = true
+		return tx.Create(&schemaMigration{
+			Version:   first.Version,
+			Name:      first.Name,
+			AppliedAt: time.Now().UTC(),
+		}).Error
+	})
+	return adopted && err == nil, err
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first
+func MigrateDown(database *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := LoadMigrations(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	migrationsByVersion := map[uint]Migration{}
+	for _, migration := range migrations {
+		migrationsByVersion[migration.Version] = migration
+	}
+
+	reverted := []Migration{}
+	err = withMigrationLock(database, func(connection *gorm.DB) error {
+		appliedMigrations, err := getAppliedMigrations(connection)
+		if err != nil {
+			return err
+		}
+
+		for i := len(appliedMigrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration, ok := migrationsByVersion[appliedMigrations[i].Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s is applied but unknown to this version of the service",
+					appliedMigrations[i].Version, appliedMigrations[i].Name)
+			}
+
+			err := connection.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			log.WithField("version", migration.Version).Info(fmt.Sprintf("Reverted migration %s", migration.Name))
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// GetMigrationStatus lists the known migrations and any applied migration
+// this version of the service does not know about, sorted by version
+func GetMigrationStatus(database *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(database.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	statusByVersion := map[uint]MigrationStatus{}
+	for _, migration := range migrations {
+		statusByVersion[migration.Version] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+	}
+
+	// reading the status does not wait for the lock, a database never migrated has every migration pending
+	if database.Migrator().HasTable(schemaMigrationsTable) {
+		appliedMigrations, err := getAppliedMigrations(database)
+		if err != nil {
+			return nil, err
+		}
+		for _, applied := range appliedMigrations {
+			appliedAt := applied.AppliedAt
+			statusByVersion[applied.Version] = MigrationStatus{
+				Version:   applied.Version,
+				Name:      applied.Name,
+				AppliedAt: &appliedAt,
+			}
+		}
+	}
+
+	statuses := []MigrationStatus{}
+	for _, status := range statusByVersion {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
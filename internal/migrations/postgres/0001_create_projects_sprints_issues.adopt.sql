@@ -0,0 +1,13 @@
+-- reshapes the tables created by the AutoMigrate of the versions before the migrations as the up file creates them
+
+-- the unique index of the sprints was named idx_member
+DROP INDEX IF EXISTS idx_member;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_project_number ON sprints (project_id, number);
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sprints_deleted_at ON sprints (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_issues_deleted_at ON issues (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_issues_project_sprint ON issues (project_id, sprint_id);
+
+-- fk_issues_sprint referenced the projects
+ALTER TABLE issues DROP CONSTRAINT IF EXISTS fk_issues_sprint;
+ALTER TABLE issues ADD CONSTRAINT fk_issues_sprint FOREIGN KEY (sprint_id) REFERENCES sprints (id);
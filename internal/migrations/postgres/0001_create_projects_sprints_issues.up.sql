@@ -0,0 +1,41 @@
+CREATE TABLE projects (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    client text,
+    name text CONSTRAINT projects_name_key UNIQUE,
+    type text
+);
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at);
+
+CREATE TABLE sprints (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    number text,
+    project_id bigint CONSTRAINT fk_sprints_project REFERENCES projects (id),
+    start_date timestamptz,
+    end_date timestamptz,
+    completed boolean,
+    max_issue_per_sprint bigint
+);
+CREATE INDEX idx_sprints_deleted_at ON sprints (deleted_at);
+CREATE UNIQUE INDEX idx_sprints_project_number ON sprints (project_id, number);
+
+CREATE TABLE issues (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    project_id bigint CONSTRAINT fk_issues_project REFERENCES projects (id),
+    sprint_id bigint CONSTRAINT fk_issues_sprint REFERENCES sprints (id),
+    type text,
+    title text,
+    description text,
+    status text,
+    assignee text
+);
+CREATE INDEX idx_issues_deleted_at ON issues (deleted_at);
+CREATE INDEX idx_issues_project_sprint ON issues (project_id, sprint_id);
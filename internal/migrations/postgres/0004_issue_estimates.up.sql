@@ -0,0 +1,7 @@
+ALTER TABLE projects ADD COLUMN estimation_scale text NOT NULL DEFAULT 'fibonacci';
+ALTER TABLE issues ADD COLUMN story_points bigint;
+ALTER TABLE issues ADD COLUMN size text;
+ALTER TABLE issues ADD COLUMN original_estimate_minutes bigint;
+ALTER TABLE issues ADD COLUMN remaining_estimate_minutes bigint;
+ALTER TABLE issues ADD COLUMN logged_minutes bigint;
+ALTER TABLE sprint_snapshot_issues ADD COLUMN story_points bigint;
@@ -0,0 +1,16 @@
+-- every row is the state of an issue after a change of its status, its points, its project or its sprint
+CREATE TABLE issue_changes (
+    id bigserial PRIMARY KEY,
+    issue_id bigint CONSTRAINT fk_issue_changes_issue REFERENCES issues (id),
+    project_id bigint,
+    sprint_id bigint NOT NULL DEFAULT 0,
+    status text,
+    story_points bigint,
+    changed_at timestamptz
+);
+CREATE INDEX idx_issue_changes_issue ON issue_changes (issue_id);
+CREATE INDEX idx_issue_changes_project_sprint ON issue_changes (project_id, sprint_id);
+
+-- the existing issues start their history with their current state
+INSERT INTO issue_changes (issue_id, project_id, sprint_id, status, story_points, changed_at)
+SELECT id, project_id, COALESCE(sprint_id, 0), status, story_points, created_at FROM issues WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP TABLE boards;
+DROP INDEX idx_issues_project_rank;
+ALTER TABLE issues DROP COLUMN rank;
@@ -0,0 +1,23 @@
+ALTER TABLE issues ADD COLUMN priority text NOT NULL DEFAULT '';
+
+CREATE TABLE sla_policies (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    project_id bigint CONSTRAINT fk_sla_policies_project REFERENCES projects (id),
+    paused_statuses text,
+    targets text
+);
+CREATE UNIQUE INDEX idx_sla_policies_project ON sla_policies (project_id);
+
+-- every row flags, once, an issue whose response or resolution clock exceeded its target
+CREATE TABLE sla_breaches (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    project_id bigint,
+    issue_id bigint CONSTRAINT fk_sla_breaches_issue REFERENCES issues (id),
+    kind text NOT NULL,
+    breached_at timestamptz
+);
+CREATE UNIQUE INDEX idx_sla_breaches_issue_kind ON sla_breaches (issue_id, kind);
+CREATE INDEX idx_sla_breaches_project ON sla_breaches (project_id);
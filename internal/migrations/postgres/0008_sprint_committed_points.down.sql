@@ -0,0 +1 @@
+ALTER TABLE sprint_snapshot_issues DROP COLUMN committed_story_points;
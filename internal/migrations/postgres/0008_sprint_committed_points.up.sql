@@ -0,0 +1,4 @@
+-- the committed points are kept apart from the points at the completion of the sprint,
+-- the snapshots of the completed sprints only know their points at the completion
+ALTER TABLE sprint_snapshot_issues ADD COLUMN committed_story_points bigint;
+UPDATE sprint_snapshot_issues SET committed_story_points = story_points WHERE committed;
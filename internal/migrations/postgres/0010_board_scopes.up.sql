@@ -0,0 +1 @@
+ALTER TABLE boards ADD COLUMN scope text NOT NULL DEFAULT 'activeSprint';
@@ -0,0 +1,12 @@
+-- every row keeps the response of a POST request sent with an Idempotency-Key header
+CREATE TABLE idempotency_records (
+    id bigserial PRIMARY KEY,
+    key text NOT NULL,
+    request_hash text NOT NULL,
+    status_code bigint NOT NULL DEFAULT 0,
+    content_type text,
+    body bytea,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_idempotency_records_key ON idempotency_records (key);
+CREATE INDEX idx_idempotency_records_created_at ON idempotency_records (created_at);
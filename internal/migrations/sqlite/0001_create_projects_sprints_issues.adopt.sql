@@ -0,0 +1,28 @@
+-- reshapes the tables created by the AutoMigrate of the versions before the migrations as the up file creates them
+
+-- the unique index of the sprints was named idx_member
+DROP INDEX IF EXISTS idx_member;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_project_number ON sprints (project_id, number);
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sprints_deleted_at ON sprints (deleted_at);
+
+-- fk_issues_sprint referenced the projects, sqlite cannot change a constraint so the issues are copied to a new table
+CREATE TABLE issues_adopted (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    project_id integer CONSTRAINT fk_issues_project REFERENCES projects (id),
+    sprint_id integer CONSTRAINT fk_issues_sprint REFERENCES sprints (id),
+    type text,
+    title text,
+    description text,
+    status text,
+    assignee text
+);
+INSERT INTO issues_adopted (id, created_at, updated_at, deleted_at, project_id, sprint_id, type, title, description, status, assignee)
+SELECT id, created_at, updated_at, deleted_at, project_id, sprint_id, type, title, description, status, assignee FROM issues;
+DROP TABLE issues;
+ALTER TABLE issues_adopted RENAME TO issues;
+CREATE INDEX idx_issues_deleted_at ON issues (deleted_at);
+CREATE INDEX idx_issues_project_sprint ON issues (project_id, sprint_id);
@@ -0,0 +1,3 @@
+DROP TABLE issues;
+DROP TABLE sprints;
+DROP TABLE projects;
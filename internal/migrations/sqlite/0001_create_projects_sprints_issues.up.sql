@@ -0,0 +1,41 @@
+CREATE TABLE projects (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    client text,
+    name text CONSTRAINT projects_name_key UNIQUE,
+    type text
+);
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at);
+
+CREATE TABLE sprints (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    number text,
+    project_id integer CONSTRAINT fk_sprints_project REFERENCES projects (id),
+    start_date datetime,
+    end_date datetime,
+    completed numeric,
+    max_issue_per_sprint integer
+);
+CREATE INDEX idx_sprints_deleted_at ON sprints (deleted_at);
+CREATE UNIQUE INDEX idx_sprints_project_number ON sprints (project_id, number);
+
+CREATE TABLE issues (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    project_id integer CONSTRAINT fk_issues_project REFERENCES projects (id),
+    sprint_id integer CONSTRAINT fk_issues_sprint REFERENCES sprints (id),
+    type text,
+    title text,
+    description text,
+    status text,
+    assignee text
+);
+CREATE INDEX idx_issues_deleted_at ON issues (deleted_at);
+CREATE INDEX idx_issues_project_sprint ON issues (project_id, sprint_id);
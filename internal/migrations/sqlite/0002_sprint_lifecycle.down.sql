@@ -0,0 +1,5 @@
+DROP TABLE sprint_snapshot_issues;
+DROP INDEX idx_sprints_project_active;
+ALTER TABLE sprints DROP COLUMN next_sprint_id;
+ALTER TABLE sprints DROP COLUMN completed_at;
+ALTER TABLE sprints DROP COLUMN started_at;
@@ -0,0 +1,17 @@
+ALTER TABLE sprints ADD COLUMN started_at datetime;
+ALTER TABLE sprints ADD COLUMN completed_at datetime;
+ALTER TABLE sprints ADD COLUMN next_sprint_id integer;
+UPDATE sprints SET completed_at = updated_at WHERE completed;
+-- a project has at most one active sprint, started and not completed
+CREATE UNIQUE INDEX idx_sprints_project_active ON sprints (project_id) WHERE started_at IS NOT NULL AND completed_at IS NULL;
+
+CREATE TABLE sprint_snapshot_issues (
+    sprint_id integer CONSTRAINT fk_sprint_snapshot_issues_sprint REFERENCES sprints (id),
+    issue_id integer,
+    committed numeric,
+    outcome text,
+    type text,
+    title text,
+    status text,
+    PRIMARY KEY (sprint_id, issue_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE projects DROP COLUMN sprint_max_days;
+ALTER TABLE projects DROP COLUMN sprint_min_days;
+ALTER TABLE projects DROP COLUMN timezone;
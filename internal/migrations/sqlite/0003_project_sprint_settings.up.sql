@@ -0,0 +1,3 @@
+ALTER TABLE projects ADD COLUMN timezone text NOT NULL DEFAULT 'UTC';
+ALTER TABLE projects ADD COLUMN sprint_min_days integer NOT NULL DEFAULT 0;
+ALTER TABLE projects ADD COLUMN sprint_max_days integer NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+ALTER TABLE sprint_snapshot_issues DROP COLUMN story_points;
+ALTER TABLE issues DROP COLUMN logged_minutes;
+ALTER TABLE issues DROP COLUMN remaining_estimate_minutes;
+ALTER TABLE issues DROP COLUMN original_estimate_minutes;
+ALTER TABLE issues DROP COLUMN size;
+ALTER TABLE issues DROP COLUMN story_points;
+ALTER TABLE projects DROP COLUMN estimation_scale;
@@ -0,0 +1,7 @@
+ALTER TABLE projects ADD COLUMN estimation_scale text NOT NULL DEFAULT 'fibonacci';
+ALTER TABLE issues ADD COLUMN story_points integer;
+ALTER TABLE issues ADD COLUMN size text;
+ALTER TABLE issues ADD COLUMN original_estimate_minutes integer;
+ALTER TABLE issues ADD COLUMN remaining_estimate_minutes integer;
+ALTER TABLE issues ADD COLUMN logged_minutes integer;
+ALTER TABLE sprint_snapshot_issues ADD COLUMN story_points integer;
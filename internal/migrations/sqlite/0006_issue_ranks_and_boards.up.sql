@@ -0,0 +1,14 @@
+-- the existing issues are ranked by id, the ranks never end with the lowest digit 0
+ALTER TABLE issues ADD COLUMN rank text NOT NULL DEFAULT '';
+UPDATE issues SET rank = substr('000000000000' || id, -12, 12) || 'i';
+CREATE INDEX idx_issues_project_rank ON issues (project_id, rank);
+
+CREATE TABLE boards (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    project_id integer CONSTRAINT fk_boards_project REFERENCES projects (id),
+    swimlane text NOT NULL DEFAULT '',
+    columns text
+);
+CREATE UNIQUE INDEX idx_boards_project ON boards (project_id);
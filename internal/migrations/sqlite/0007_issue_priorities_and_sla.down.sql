@@ -0,0 +1,3 @@
+DROP TABLE sla_breaches;
+DROP TABLE sla_policies;
+ALTER TABLE issues DROP COLUMN priority;
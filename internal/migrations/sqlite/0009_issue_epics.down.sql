@@ -0,0 +1 @@
+ALTER TABLE issues DROP COLUMN epic;
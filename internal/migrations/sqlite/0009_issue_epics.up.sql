@@ -0,0 +1 @@
+ALTER TABLE issues ADD COLUMN epic text NOT NULL DEFAULT '';
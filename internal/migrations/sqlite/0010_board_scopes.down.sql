@@ -0,0 +1 @@
+ALTER TABLE boards DROP COLUMN scope;
@@ -0,0 +1,207 @@
+package internal
+
+import (
+	"context"
+	"issue-service/app/issue-api/cfg"
+	models "issue-service/app/issue-api/routes/models"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func connectTestSqliteDatabase(t *testing.T) *gorm.DB {
+	database, err := ConnectDatabase(cfg.EnvConfiguration{
+		DATABASE_DRIVER: DRIVER_SQLITE,
+		DATABASE_NAME:   filepath.Join(t.TempDir(), "issue.db"),
+	})
+	require.NoError(t, err)
+	return database
+}
+
+// the models of the versions before the migrations, their AutoMigrate created the baseline schema
+type baselineProject struct {
+	gorm.Model
+	Client string
+	Name   string `gorm:"unique"`
+	Type   string
+}
+
+func (baselineProject) TableName() string {
+	return "projects"
+}
+
+type baselineSprint struct {
+	gorm.Model
+	Number            string `gorm:"uniqueIndex:idx_member"`
+	ProjectID         int    `gorm:"uniqueIndex:idx_member"`
+	Project           baselineProject
+	StartDate         time.Time
+	EndDate           time.Time
+	Completed         bool
+	MaxIssuePerSprint int
+}
+
+func (baselineSprint) TableName() string {
+	return "sprints"
+}
+
+type baselineIssue struct {
+	gorm.Model
+	ProjectID   int `gorm:"uniqueIndex:idx_member"`
+	Project     baselineProject
+	SprintID    int `gorm:"uniqueIndex:idx_member"`
+	Sprint      baselineProject
+	Type        string
+	Title       string
+	Description string
+	Status      string
+	Assignee    string
+}
+
+func (baselineIssue) TableName() string {
+	return "issues"
+}
+
+func TestLoadMigrations(testCase *testing.T) {
+	testCase.Run("every dialect has the same versions", func(t *testing.T) {
+		postgresMigrations, err := LoadMigrations(DRIVER_POSTGRES)
+		require.NoError(t, err)
+		sqliteMigrations, err := LoadMigrations(DRIVER_SQLITE)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, postgresMigrations)
+		require.Equal(t, len(postgresMigrations), len(sqliteMigrations))
+		for i := range postgresMigrations {
+			require.Equal(t, postgresMigrations[i].Version, sqliteMigrations[i].Version)
+			require.Equal(t, postgresMigrations[i].Name, sqliteMigrations[i].Name)
+		}
+	})
+
+	testCase.Run("unknown driver", func(t *testing.T) {
+		_, err := LoadMigrations("mysql")
+		require.Error(t, err)
+	})
+
+	testCase.Run("file names", func(t *testing.T) {
+		version, name, direction, err := parseMigrationFileName("0002_add_priority.down.sql")
+		require.NoError(t, err)
+		require.Equal(t, uint(2), version)
+		require.Equal(t, "add_priority", name)
+		require.Equal(t, "down", direction)
+
+		for _, fileName := range []string{"add_priority.up.sql", "0002.up.sql", "0002_add_priority.sql", "0002_add_priority.sideways.sql"} {
+			_, _, _, err := parseMigrationFileName(fileName)
+			require.Error(t, err, fileName)
+		}
+	})
+}
+
+func TestMigrations(testCase *testing.T) {
+	testCase.Run("up, status and down", func(t *testing.T) {
+		database := connectTestSqliteDatabase(t)
+		migrations, err := LoadMigrations(DRIVER_SQLITE)
+		require.NoError(t, err)
+
+		statuses, err := GetMigrationStatus(database)
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), len(statuses))
+		for _, status := range statuses {
+			require.False(t, status.Applied())
+		}
+
+		applied, err := MigrateUp(database)
+		require.NoError(t, err)
+		require.Equal(t, migrations, applied)
+		require.True(t, database.Migrator().HasTable("issues"))
+
+		statuses, err = GetMigrationStatus(database)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			require.True(t, status.Applied())
+		}
+
+		applied, err = MigrateUp(database)
+		require.NoError(t, err)
+		require.Empty(t, applied)
+
+		reverted, err := MigrateDown(database, len(migrations))
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), len(reverted))
+		require.Equal(t, migrations[0], reverted[len(reverted)-1])
+		require.False(t, database.Migrator().HasTable("projects"))
+
+		reverted, err = MigrateDown(database, 1)
+		require.NoError(t, err)
+		require.Empty(t, reverted)
+	})
+
+	testCase.Run("applied migrations unknown to the service", func(t *testing.T) {
+		database := connectTestSqliteDatabase(t)
+		_, err := MigrateUp(database)
+		require.NoError(t, err)
+		require.NoError(t, database.Create(&schemaMigration{Version: 9999, Name: "from_the_future"}).Error)
+
+		statuses, err := GetMigrationStatus(database)
+		require.NoError(t, err)
+		last := statuses[len(statuses)-1]
+		require.Equal(t, uint(9999), last.Version)
+		require.True(t, last.Applied())
+
+		_, err = MigrateDown(database, 1)
+		require.Error(t, err)
+	})
+
+	testCase.Run("the schema of the versions before the migrations is adopted", func(t *testing.T) {
+		database := connectTestSqliteDatabase(t)
+		// like those versions, the errors of AutoMigrate are ignored: the second idx_member is not created
+		database.AutoMigrate(&baselineProject{})
+		database.AutoMigrate(&baselineSprint{})
+		database.AutoMigrate(&baselineIssue{})
+		project := baselineProject{Name: "adopted", Type: "scrum"}
+		require.NoError(t, database.Create(&project).Error)
+		sprint := baselineSprint{Number: "1", ProjectID: int(project.ID)}
+		require.NoError(t, database.Create(&sprint).Error)
+		require.NoError(t, database.Create(&baselineIssue{ProjectID: int(project.ID), SprintID: int(sprint.ID), Type: "Task", Title: "Adopted"}).Error)
+
+		migrations, err := LoadMigrations(DRIVER_SQLITE)
+		require.NoError(t, err)
+		applied, err := MigrateUp(database)
+		require.NoError(t, err)
+		require.Equal(t, migrations, applied)
+
+		stores := NewGormStores(database)
+		issues, err := stores.Issues.ListBySprint(context.Background(), int(project.ID), int(sprint.ID), models.Page{})
+		require.NoError(t, err)
+		require.Equal(t, "Adopted", issues[0].Title)
+		otherSprintId := CreateTestSprint(stores, "2", int(project.ID))
+		CreateTestIssue(stores, int(project.ID), int(otherSprintId))
+		CreateTestIssue(stores, int(project.ID), int(otherSprintId))
+		issues, err = stores.Issues.ListBySprint(context.Background(), int(project.ID), int(otherSprintId), models.Page{})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(issues), "the issues reference the sprints, several in a sprint")
+
+		reverted, err := MigrateDown(database, len(migrations))
+		require.NoError(t, err)
+		require.Equal(t, len(migrations), len(reverted))
+		require.False(t, database.Migrator().HasTable("projects"))
+	})
+
+	testCase.Run("stores work on the migrated schema", func(t *testing.T) {
+		database := connectTestSqliteDatabase(t)
+		_, err := MigrateUp(database)
+		require.NoError(t, err)
+		stores := NewGormStores(database)
+
+		projectId, sprintId := CreateProjectAndSprint(stores)
+		first := CreateTestIssue(stores, int(projectId), int(sprintId))
+		second := CreateTestIssue(stores, int(projectId), int(sprintId))
+		require.NotEqual(t, first, second)
+
+		duplicateSprint := models.Sprint{Number: "12345", ProjectID: int(projectId)}
+		err = stores.Sprints.Create(context.Background(), &duplicateSprint)
+		require.True(t, IsDuplicateKeyError(err))
+	})
+}
@@ -0,0 +1,399 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	models "issue-service/app/issue-api/routes/models"
+
+	"gorm.io/gorm"
+)
+
+const OPENAPI_VERSION = "3.0.3"
+
+type OpenAPIDocument struct {
+	OpenAPI    string                     `json:"openapi"`
+	Info       OpenAPIInfo                `json:"info"`
+	Paths      map[string]OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents          `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIPathItem maps the lower case methods to their operation
+type OpenAPIPathItem map[string]*OpenAPIOperation
+
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Parameters  []OpenAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required"`
+	Schema      *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas"`
+}
+
+type OpenAPISchema struct {
+	Ref        string                    `json:"$ref,omitempty"`
+	Type       string                    `json:"type,omitempty"`
+	Format     string                    `json:"format,omitempty"`
+	Nullable   bool                      `json:"nullable,omitempty"`
+	Enum       []string                  `json:"enum,omitempty"`
+	MinLength  *int                      `json:"minLength,omitempty"`
+	MaxLength  *int                      `json:"maxLength,omitempty"`
+	Minimum    *float64                  `json:"minimum,omitempty"`
+	Maximum    *float64                  `json:"maximum,omitempty"`
+	MinItems   *int                      `json:"minItems,omitempty"`
+	MaxItems   *int                      `json:"maxItems,omitempty"`
+	Items      *OpenAPISchema            `json:"items,omitempty"`
+	Properties map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Required   []string                  `json:"required,omitempty"`
+	// AdditionalProperties is false for the structs, which reject the unknown fields, and the schema of the values for the maps
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+var pathParameterPattern = regexp.MustCompile(`{([^}]+)}`)
+
+// knownSchemas describes the types encoded by their own MarshalJSON
+var knownSchemas = map[reflect.Type]OpenAPISchema{
+	reflect.TypeOf(time.Time{}):      {Type: "string", Format: "date-time"},
+	reflect.TypeOf(gorm.DeletedAt{}): {Type: "string", Format: "date-time", Nullable: true},
+}
+
+// NewOpenAPIDocument describes routes, the schemas are generated from the types of their bodies
+func NewOpenAPIDocument(routes models.Routes) OpenAPIDocument {
+	generator := schemaGenerator{schemas: map[string]*OpenAPISchema{}}
+	document := OpenAPIDocument{
+		OpenAPI:    OPENAPI_VERSION,
+		Info:       OpenAPIInfo{Title: "Yet Another Issue Tracker", Version: Version},
+		Paths:      map[string]OpenAPIPathItem{},
+		Components: OpenAPIComponents{Schemas: generator.schemas},
+	}
+
+	for _, route := range routes {
+		operation := &OpenAPIOperation{
+			OperationID: route.Name,
+			Summary:     route.Summary,
+			Responses:   map[string]*OpenAPIResponse{},
+		}
+		for _, match := range pathParameterPattern.FindAllStringSubmatch(route.Pattern, -1) {
+			operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   getPathParameterSchema(match[1]),
+			})
+		}
+		for _, parameter := range route.QueryParameters {
+			operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+				Name:        parameter.Name,
+				In:          "query",
+				Description: parameter.Description,
+				Schema:      &OpenAPISchema{Type: parameter.Type},
+			})
+		}
+		if route.Method == http.MethodPost {
+			operation.Parameters = append(operation.Parameters, OpenAPIParameter{
+				Name:        IDEMPOTENCY_KEY_HEADER,
+				In:          "header",
+				Description: "Processes the request once, its retries with the same key get the same response",
+				Schema:      &OpenAPISchema{Type: "string"},
+			})
+		}
+		if route.RequestBody != nil {
+			operation.RequestBody = &OpenAPIRequestBody{
+				Required: !route.RequestBodyOptional,
+				Content: map[string]OpenAPIMediaType{
+					"application/json": {Schema: generator.getSchema(reflect.TypeOf(route.RequestBody))},
+				},
+			}
+		}
+		if route.RequestBody != nil || route.Method == http.MethodPost {
+			operation.Responses[strconv.Itoa(http.StatusRequestEntityTooLarge)] = generator.getResponse(models.Response{
+				Description: errRequestBodyTooLarge.Error(),
+				Body:        models.ErrorResponse{},
+			})
+		}
+		for statusCode, response := range route.Responses {
+			operation.Responses[strconv.Itoa(statusCode)] = generator.getResponse(response)
+		}
+
+		pathItem, ok := document.Paths[route.Pattern]
+		if !ok {
+			pathItem = OpenAPIPathItem{}
+			document.Paths[route.Pattern] = pathItem
+		}
+		pathItem[strings.ToLower(route.Method)] = operation
+	}
+	return document
+}
+
+// MarshalOpenAPIDocument returns the indented document, the keys are sorted so that it can be diffed
+func MarshalOpenAPIDocument(document OpenAPIDocument) ([]byte, error) {
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// getPathParameterSchema types the ids as integers, the other parameters as strings
+func getPathParameterSchema(name string) *OpenAPISchema {
+	if strings.HasSuffix(name, "Id") {
+		return &OpenAPISchema{Type: "integer"}
+	}
+	return &OpenAPISchema{Type: "string"}
+}
+
+type schemaGenerator struct {
+	schemas map[string]*OpenAPISchema
+}
+
+func (generator *schemaGenerator) getResponse(response models.Response) *OpenAPIResponse {
+	openAPIResponse := &OpenAPIResponse{Description: response.Description}
+	if response.Body == nil {
+		return openAPIResponse
+	}
+
+	contentType := response.ContentType
+	schema := generator.getSchema(reflect.TypeOf(response.Body))
+	if contentType == "" {
+		contentType = "application/json"
+		if reflect.TypeOf(response.Body).Kind() == reflect.String {
+			contentType = "text/plain"
+		}
+	}
+	openAPIResponse.Content = map[string]OpenAPIMediaType{contentType: {Schema: schema}}
+	for _, otherContentType := range response.OtherContentTypes {
+		openAPIResponse.Content[otherContentType] = OpenAPIMediaType{Schema: &OpenAPISchema{Type: "string"}}
+	}
+	return openAPIResponse
+}
+
+// getSchema returns the schema of the JSON encoding of valueType,
+// the named structs are added to the components and referenced
+func (generator *schemaGenerator) getSchema(valueType reflect.Type) *OpenAPISchema {
+	if known, ok := knownSchemas[valueType]; ok {
+		return &known
+	}
+
+	switch valueType.Kind() {
+	case reflect.Ptr:
+		schema := generator.getSchema(valueType.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &OpenAPISchema{Type: "array", Items: generator.getSchema(valueType.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: generator.getSchema(valueType.Elem())}
+	case reflect.Struct:
+		if valueType.Name() == "" {
+			return generator.getObjectSchema(valueType)
+		}
+		name := valueType.Name()
+		if _, ok := generator.schemas[name]; !ok {
+			// registered before its fields, so that a recursive type references itself
+			generator.schemas[name] = &OpenAPISchema{}
+			*generator.schemas[name] = *generator.getObjectSchema(valueType)
+		}
+		return &OpenAPISchema{Ref: fmt.Sprintf("#/components/schemas/%s", name)}
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+func (generator *schemaGenerator) getObjectSchema(structType reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}, AdditionalProperties: false}
+	for _, field := range getJSONFields(structType) {
+		fieldSchema := generator.getSchema(field.field.Type)
+		required := addValidateConstraints(fieldSchema, field.field.Tag.Get("validate"))
+		schema.Properties[field.name] = fieldSchema
+		if required {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// addValidateConstraints translates the validate tag of a field into the constraints of its schema,
+// the rules without an OpenAPI equivalent are left to the validator of the handlers
+func addValidateConstraints(schema *OpenAPISchema, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+	rules := strings.Split(tag, ",")
+	for index, rule := range rules {
+		name, parameter := rule, ""
+		if index := strings.Index(rule, "="); index >= 0 {
+			name, parameter = rule[:index], rule[index+1:]
+		}
+		if name == "dive" {
+			// the rules after dive apply to the items of the array
+			if schema.Type == "array" && schema.Items != nil && schema.Items.Ref == "" {
+				addValidateConstraints(schema.Items, strings.Join(rules[index+1:], ","))
+			}
+			break
+		}
+		switch name {
+		case "required":
+			required = true
+		case "min", "gte":
+			setBound(schema, parameter, true)
+		case "max", "lte":
+			setBound(schema, parameter, false)
+		case "len":
+			setBound(schema, parameter, true)
+			setBound(schema, parameter, false)
+		case "oneof":
+			if schema.Type == "string" {
+				schema.Enum = strings.Fields(parameter)
+			}
+		}
+	}
+	// the validator rejects the zero value of a required field, an empty string included
+	if required && schema.Type == "string" && schema.MinLength == nil {
+		setBound(schema, "1", true)
+	}
+	return required
+}
+
+// setBound sets the minimum, or the maximum, of the length of a string, of the value of a number or of the size of an array
+func setBound(schema *OpenAPISchema, parameter string, lower bool) {
+	value, err := strconv.ParseFloat(parameter, 64)
+	if err != nil {
+		return
+	}
+	size := int(value)
+	switch {
+	case schema.Type == "string" && lower:
+		schema.MinLength = &size
+	case schema.Type == "string":
+		schema.MaxLength = &size
+	case (schema.Type == "integer" || schema.Type == "number") && lower:
+		schema.Minimum = &value
+	case schema.Type == "integer" || schema.Type == "number":
+		schema.Maximum = &value
+	case schema.Type == "array" && lower:
+		schema.MinItems = &size
+	case schema.Type == "array":
+		schema.MaxItems = &size
+	}
+}
+
+type jsonField struct {
+	name   string
+	field  reflect.StructField
+	depth  int
+	tagged bool
+}
+
+// getJSONFields lists the fields encoded by encoding/json: the fields of the embedded structs are promoted,
+// a shallower field hides the deeper ones with the same name
+func getJSONFields(structType reflect.Type) []jsonField {
+	fields := map[string]jsonField{}
+	conflicts := map[string]bool{}
+	collectJSONFields(structType, 0, fields, conflicts)
+
+	names := []string{}
+	for name := range fields {
+		if !conflicts[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	result := []jsonField{}
+	for _, name := range names {
+		result = append(result, fields[name])
+	}
+	return result
+}
+
+func collectJSONFields(structType reflect.Type, depth int, fields map[string]jsonField, conflicts map[string]bool) {
+	for index := 0; index < structType.NumField(); index++ {
+		field := structType.Field(index)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if field.Anonymous && name == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				collectJSONFields(embeddedType, depth+1, fields, conflicts)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		candidate := jsonField{name: name, field: field, depth: depth, tagged: name != ""}
+		if name == "" {
+			candidate.name = field.Name
+		}
+		existing, ok := fields[candidate.name]
+		switch {
+		case !ok || candidate.depth < existing.depth:
+			fields[candidate.name] = candidate
+			delete(conflicts, candidate.name)
+		case candidate.depth == existing.depth:
+			if candidate.tagged && !existing.tagged {
+				fields[candidate.name] = candidate
+			} else if candidate.tagged == existing.tagged {
+				conflicts[candidate.name] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"encoding/json"
+	models "issue-service/app/issue-api/routes/models"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type openAPITestBase struct {
+	ID        uint
+	CreatedAt time.Time
+}
+
+type openAPITestBody struct {
+	openAPITestBase
+	ID       string            `json:"id" validate:"required"`
+	Name     string            `json:"name,omitempty" validate:"required,max=10"`
+	Parent   *openAPITestBody  `json:"parent,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Tags     []string          `json:"tags" validate:"max=3,dive,max=10"`
+	Ignored  string            `json:"-"`
+	Untagged bool
+	internal int
+}
+
+func TestNewOpenAPIDocument(t *testing.T) {
+	document := NewOpenAPIDocument(models.Routes{
+		models.Route{
+			Name:        "AddBody",
+			Method:      http.MethodPost,
+			Pattern:     "/v1/parents/{parentId}/bodies/{name}",
+			Summary:     "Create a body",
+			RequestBody: openAPITestBody{},
+			Responses: models.WithErrors(map[int]models.Response{
+				http.StatusNoContent: {Description: "Created"},
+			}, http.StatusNotFound),
+		},
+	})
+
+	operation := document.Paths["/v1/parents/{parentId}/bodies/{name}"]["post"]
+	require.Equal(t, "AddBody", operation.OperationID)
+	require.Equal(t, []OpenAPIParameter{
+		{Name: "parentId", In: "path", Required: true, Schema: &OpenAPISchema{Type: "integer"}},
+		{Name: "name", In: "path", Required: true, Schema: &OpenAPISchema{Type: "string"}},
+		{Name: IDEMPOTENCY_KEY_HEADER, In: "header", Description: "Processes the request once, its retries with the same key get the same response", Schema: &OpenAPISchema{Type: "string"}},
+	}, operation.Parameters)
+	require.Equal(t, "#/components/schemas/openAPITestBody", operation.RequestBody.Content["application/json"].Schema.Ref)
+	require.Nil(t, operation.Responses["204"].Content)
+	require.Equal(t, "#/components/schemas/ErrorResponse", operation.Responses["404"].Content["application/json"].Schema.Ref)
+
+	schema := document.Components.Schemas["openAPITestBody"]
+	properties := []string{}
+	for name := range schema.Properties {
+		properties = append(properties, name)
+	}
+	encoded, err := json.Marshal(openAPITestBody{Name: "name", Parent: &openAPITestBody{}})
+	require.NoError(t, err)
+	var encodedFields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &encodedFields))
+	encodedNames := []string{}
+	for name := range encodedFields {
+		encodedNames = append(encodedNames, name)
+	}
+	require.ElementsMatch(t, encodedNames, properties, "The properties should be the fields encoded by encoding/json")
+
+	require.Equal(t, []string{"id", "name"}, schema.Required)
+	require.Equal(t, &OpenAPISchema{Type: "string", Format: "date-time"}, schema.Properties["CreatedAt"])
+	one, ten := 1, 10
+	require.Equal(t, &OpenAPISchema{Type: "string", MinLength: &one}, schema.Properties["id"])
+	require.Equal(t, &OpenAPISchema{Type: "string", MinLength: &one, MaxLength: &ten}, schema.Properties["name"])
+	require.Equal(t, false, schema.AdditionalProperties)
+	require.Equal(t, "#/components/schemas/openAPITestBody", schema.Properties["parent"].Ref)
+	require.Equal(t, &OpenAPISchema{Type: "object", AdditionalProperties: &OpenAPISchema{Type: "string"}}, schema.Properties["labels"])
+	three := 3
+	require.Equal(t, &OpenAPISchema{Type: "array", MaxItems: &three, Items: &OpenAPISchema{Type: "string", MaxLength: &ten}}, schema.Properties["tags"])
+}
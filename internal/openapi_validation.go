@@ -0,0 +1,405 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	models "issue-service/app/issue-api/routes/models"
+
+	"github.com/gorilla/mux"
+)
+
+// MAX_REQUEST_BODY_BYTES bounds the body of the requests, the larger ones are answered 413
+const MAX_REQUEST_BODY_BYTES = 1 << 20
+
+var errRequestBodyTooLarge = fmt.Errorf("The request body exceeds %d bytes", MAX_REQUEST_BODY_BYTES)
+
+// ResponseProblemsReporter receives the differences between a response and its specification
+type ResponseProblemsReporter func(ctx context.Context, routeName string, problems []string)
+
+// LogResponseProblems reports the problems of a response as a warning of the request logger
+func LogResponseProblems(ctx context.Context, routeName string, problems []string) {
+	RequestLogger(ctx).WithField("route", routeName).
+		WithField("problems", problems).
+		Warn("Response not matching the OpenAPI specification")
+}
+
+// RequestValidator rejects the requests not matching the OpenAPI document before they reach the handlers
+type RequestValidator struct {
+	document               OpenAPIDocument
+	reportResponseProblems ResponseProblemsReporter
+}
+
+// NewRequestValidator validates the requests against document, and the responses too when reportResponseProblems is not nil
+func NewRequestValidator(document OpenAPIDocument, reportResponseProblems ResponseProblemsReporter) *RequestValidator {
+	return &RequestValidator{document: document, reportResponseProblems: reportResponseProblems}
+}
+
+// Handler answers 400 listing every problem of the parameters and of the body of the request,
+// and 413 when the body exceeds MAX_REQUEST_BODY_BYTES. The routes missing from the document are only limited
+func (requestValidator *RequestValidator) Handler(inner http.Handler, route models.Route) http.Handler {
+	operation := requestValidator.document.Paths[route.Pattern][strings.ToLower(route.Method)]
+	if operation == nil {
+		return limitRequestBody(inner)
+	}
+
+	return limitRequestBody(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		problems, err := requestValidator.validateRequest(r, operation)
+		if errors.Is(err, errRequestBodyTooLarge) {
+			LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: err.Error(),
+				ErrorCode:    http.StatusRequestEntityTooLarge,
+			}, w)
+			return
+		}
+		if err != nil {
+			LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: "Error reading request body",
+				ErrorCode:    http.StatusBadRequest,
+			}, w)
+			return
+		}
+		if len(problems) > 0 {
+			LogAndReturnErrorResponse(&models.ErrorResponse{
+				ErrorMessage: strings.Join(problems, "\n"),
+				ErrorCode:    http.StatusBadRequest,
+			}, w)
+			return
+		}
+
+		if requestValidator.reportResponseProblems == nil {
+			inner.ServeHTTP(w, r)
+			return
+		}
+		response := &responseBuffer{ResponseWriter: w}
+		inner.ServeHTTP(response, r)
+		if problems := requestValidator.validateResponse(response, operation); len(problems) > 0 {
+			requestValidator.reportResponseProblems(r.Context(), route.Name, problems)
+		}
+		response.flush()
+	}))
+}
+
+// limitRequestBody wraps the body of the requests in http.MaxBytesReader, so that no handler reads more than MAX_REQUEST_BODY_BYTES
+func limitRequestBody(inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, MAX_REQUEST_BODY_BYTES)
+		inner.ServeHTTP(w, r)
+	})
+}
+
+// readRequestBody reads the body limited by limitRequestBody, it returns errRequestBodyTooLarge past the limit
+func readRequestBody(r *http.Request) ([]byte, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	// http.MaxBytesReader fails once it read the limit, its error has no exported type before go 1.19
+	if err != nil && len(body) == MAX_REQUEST_BODY_BYTES {
+		return nil, errRequestBodyTooLarge
+	}
+	return body, err
+}
+
+// validateRequest returns the problems of the request, the body is read and replaced for the handler
+func (requestValidator *RequestValidator) validateRequest(r *http.Request, operation *OpenAPIOperation) ([]string, error) {
+	validator := schemaValidator{schemas: requestValidator.document.Components.Schemas}
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+	for _, parameter := range operation.Parameters {
+		switch {
+		case parameter.In == "path":
+			validator.validateParameter(fmt.Sprintf("path.%s", parameter.Name), vars[parameter.Name], parameter.Schema)
+		case parameter.In == "query" && query.Has(parameter.Name):
+			validator.validateParameter(fmt.Sprintf("query.%s", parameter.Name), query.Get(parameter.Name), parameter.Schema)
+		}
+	}
+
+	if operation.RequestBody != nil {
+		body, err := readRequestBody(r)
+		if err != nil {
+			return nil, err
+		}
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		mediaType, ok := operation.RequestBody.Content["application/json"]
+		switch {
+		case len(bytes.TrimSpace(body)) == 0:
+			if operation.RequestBody.Required {
+				validator.addProblem("body", "is required")
+			}
+		case ok:
+			validator.validateJSON("body", body, mediaType.Schema)
+		}
+	}
+	return validator.problems, nil
+}
+
+func (requestValidator *RequestValidator) validateResponse(response *responseBuffer, operation *OpenAPIOperation) []string {
+	validator := schemaValidator{schemas: requestValidator.document.Components.Schemas}
+	status := response.getStatus()
+	specification, ok := operation.Responses[strconv.Itoa(status)]
+	if !ok {
+		validator.addProblem("status", "%d is not documented", status)
+		return validator.problems
+	}
+
+	if len(specification.Content) == 0 {
+		if response.body.Len() > 0 {
+			validator.addProblem("body", "must be empty")
+		}
+		return validator.problems
+	}
+	// the error responses are sent as text/plain by http.Error, so the documented media type is trusted,
+	// unless the response is in another documented media type
+	responseMediaType, _, _ := mime.ParseMediaType(response.Header().Get("Content-Type"))
+	if _, ok := specification.Content[responseMediaType]; ok && responseMediaType != "application/json" {
+		return validator.problems
+	}
+	if mediaType, ok := specification.Content["application/json"]; ok {
+		validator.validateJSON("body", response.body.Bytes(), mediaType.Schema)
+	}
+	return validator.problems
+}
+
+// responseBuffer holds the response until it is validated
+type responseBuffer struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (response *responseBuffer) WriteHeader(status int) {
+	if response.status == 0 {
+		response.status = status
+	}
+}
+
+func (response *responseBuffer) Write(content []byte) (int, error) {
+	response.WriteHeader(http.StatusOK)
+	return response.body.Write(content)
+}
+
+func (response *responseBuffer) getStatus() int {
+	if response.status == 0 {
+		return http.StatusOK
+	}
+	return response.status
+}
+
+func (response *responseBuffer) flush() {
+	response.ResponseWriter.WriteHeader(response.getStatus())
+	response.ResponseWriter.Write(response.body.Bytes())
+}
+
+// schemaValidator collects the problems of the values, each one prefixed by the path of the value
+type schemaValidator struct {
+	schemas  map[string]*OpenAPISchema
+	problems []string
+}
+
+func (validator *schemaValidator) addProblem(path string, format string, args ...interface{}) {
+	validator.problems = append(validator.problems, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (validator *schemaValidator) validateParameter(path string, value string, schema *OpenAPISchema) {
+	if schema.Type == "integer" {
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			validator.addProblem(path, "must be an integer")
+		}
+	}
+}
+
+func (validator *schemaValidator) validateJSON(path string, content []byte, schema *OpenAPISchema) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		validator.addProblem(path, "is not valid JSON")
+		return
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		validator.addProblem(path, "is not valid JSON")
+		return
+	}
+	validator.validate(path, value, schema)
+}
+
+// validate checks value, decoded with UseNumber, against schema
+func (validator *schemaValidator) validate(path string, value interface{}, schema *OpenAPISchema) {
+	if schema.Ref != "" {
+		schema = validator.schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if schema == nil {
+			return
+		}
+	}
+	if schema.Type == "" {
+		return
+	}
+	if value == nil {
+		if !schema.Nullable {
+			validator.addProblem(path, "must not be null")
+		}
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			validator.addTypeProblem(path, schema.Type, value)
+			return
+		}
+		validator.validateObject(path, object, schema)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			validator.addTypeProblem(path, schema.Type, value)
+			return
+		}
+		if schema.MinItems != nil && len(array) < *schema.MinItems {
+			validator.addProblem(path, "must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			validator.addProblem(path, "must have at most %d items", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for index, item := range array {
+				validator.validate(fmt.Sprintf("%s[%d]", path, index), item, schema.Items)
+			}
+		}
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			validator.addTypeProblem(path, schema.Type, value)
+			return
+		}
+		validator.validateString(path, text, schema)
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			validator.addTypeProblem(path, schema.Type, value)
+			return
+		}
+		validator.validateNumber(path, number, schema)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			validator.addTypeProblem(path, schema.Type, value)
+		}
+	}
+}
+
+func (validator *schemaValidator) validateObject(path string, object map[string]interface{}, schema *OpenAPISchema) {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			validator.addProblem(path+"."+name, "is required")
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := schema.Properties[name]; ok {
+			validator.validate(path+"."+name, object[name], property)
+			continue
+		}
+		switch additionalProperties := schema.AdditionalProperties.(type) {
+		case bool:
+			if !additionalProperties {
+				validator.addProblem(path+"."+name, "is not a known field")
+			}
+		case *OpenAPISchema:
+			validator.validate(path+"."+name, object[name], additionalProperties)
+		}
+	}
+}
+
+func (validator *schemaValidator) validateString(path string, text string, schema *OpenAPISchema) {
+	length := utf8.RuneCountInString(text)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		if *schema.MinLength == 1 {
+			validator.addProblem(path, "must not be empty")
+		} else {
+			validator.addProblem(path, "must be at least %d characters long", *schema.MinLength)
+		}
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		validator.addProblem(path, "must be at most %d characters long", *schema.MaxLength)
+	}
+	if schema.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, text); err != nil {
+			validator.addProblem(path, "must be a RFC 3339 date-time")
+		}
+	}
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if text == allowed {
+				return
+			}
+		}
+		validator.addProblem(path, "must be one of %s", strings.Join(schema.Enum, ", "))
+	}
+}
+
+func (validator *schemaValidator) validateNumber(path string, number json.Number, schema *OpenAPISchema) {
+	if schema.Type == "integer" {
+		if _, err := strconv.ParseInt(number.String(), 10, 64); err != nil {
+			if _, err := strconv.ParseUint(number.String(), 10, 64); err != nil {
+				validator.addProblem(path, "must be an integer")
+				return
+			}
+		}
+	}
+	value, err := number.Float64()
+	if err != nil {
+		validator.addProblem(path, "must be a number")
+		return
+	}
+	if schema.Minimum != nil && value < *schema.Minimum {
+		validator.addProblem(path, "must be at least %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		validator.addProblem(path, "must be at most %v", *schema.Maximum)
+	}
+}
+
+func (validator *schemaValidator) addTypeProblem(path string, expected string, value interface{}) {
+	validator.addProblem(path, "must be %s %s, got %s", getArticle(expected), expected, getJSONType(value))
+}
+
+func getArticle(jsonType string) string {
+	if strings.ContainsAny(jsonType[:1], "aeiou") {
+		return "an"
+	}
+	return "a"
+}
+
+func getJSONType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return "null"
+	}
+}
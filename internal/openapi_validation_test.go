@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	models "issue-service/app/issue-api/routes/models"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+)
+
+type validationTestBody struct {
+	Title    string   `json:"title" validate:"required,max=5"`
+	Kind     string   `json:"kind,omitempty" validate:"oneof=bug task"`
+	Points   int      `json:"points,omitempty" validate:"min=0,max=100"`
+	Labels   []string `json:"labels,omitempty" validate:"max=2"`
+	Optional *string  `json:"optional"`
+}
+
+// newValidationTestRouter serves AddBody, whose handler answers response, validated by a validator reporting to problems
+func newValidationTestRouter(response func(w http.ResponseWriter), problems *[]string) (http.Handler, *string) {
+	route := models.Route{
+		Name:        "AddBody",
+		Method:      http.MethodPost,
+		Pattern:     "/v1/parents/{parentId}/bodies",
+		RequestBody: validationTestBody{},
+		Responses: models.WithErrors(map[int]models.Response{
+			http.StatusOK: {Description: "Created", Body: models.CreateResponse{}},
+		}, http.StatusBadRequest),
+	}
+	requestValidator := NewRequestValidator(NewOpenAPIDocument(models.Routes{route}), func(ctx context.Context, routeName string, reported []string) {
+		*problems = append(*problems, reported...)
+	})
+
+	receivedBody := new(string)
+	router := mux.NewRouter()
+	router.Methods(route.Method).Path(route.Pattern).Handler(requestValidator.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*receivedBody = string(body)
+		response(w)
+	}), route))
+	return router, receivedBody
+}
+
+func answerCreated(w http.ResponseWriter) {
+	w.Write([]byte(`{"id":"1"}`))
+}
+
+func postValidationTestBody(router http.Handler, path string, body string) (int, models.ErrorResponse) {
+	responseRecorder := httptest.NewRecorder()
+	router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+
+	var errorResponse models.ErrorResponse
+	if responseRecorder.Code != http.StatusOK {
+		json.NewDecoder(responseRecorder.Body).Decode(&errorResponse)
+	}
+	return responseRecorder.Code, errorResponse
+}
+
+func TestRequestValidator(testCase *testing.T) {
+	testCase.Parallel()
+
+	testCase.Run("a valid request reaches the handler with its body", func(t *testing.T) {
+		problems := []string{}
+		router, receivedBody := newValidationTestRouter(answerCreated, &problems)
+		body := `{"title":"fix","kind":"bug","points":3,"labels":["a"],"optional":null}`
+
+		statusCode, _ := postValidationTestBody(router, "/v1/parents/1/bodies", body)
+		require.Equal(t, http.StatusOK, statusCode)
+		require.Equal(t, body, *receivedBody)
+		require.Empty(t, problems)
+	})
+
+	testCase.Run("every problem of the request is listed with its path", func(t *testing.T) {
+		problems := []string{}
+		router, receivedBody := newValidationTestRouter(answerCreated, &problems)
+
+		statusCode, errorResponse := postValidationTestBody(router, "/v1/parents/first/bodies",
+			`{"kind":"epic","points":1.5,"labels":["a",2,"c"],"optional":null,"unknown":true}`)
+		require.Equal(t, http.StatusBadRequest, statusCode)
+		require.Equal(t, []string{
+			"path.parentId: must be an integer",
+			"body.title: is required",
+			"body.kind: must be one of bug, task",
+			"body.labels: must have at most 2 items",
+			"body.labels[1]: must be a string, got number",
+			"body.points: must be an integer",
+			"body.unknown: is not a known field",
+		}, strings.Split(errorResponse.ErrorMessage, "\n"))
+		require.Equal(t, "", *receivedBody, "The handler should not be called")
+	})
+
+	testCase.Run("the constraints of the validate tags are checked", func(t *testing.T) {
+		problems := []string{}
+		router, _ := newValidationTestRouter(answerCreated, &problems)
+
+		_, errorResponse := postValidationTestBody(router, "/v1/parents/1/bodies", `{"title":"","points":-1}`)
+		require.Equal(t, "body.points: must be at least 0\nbody.title: must not be empty", errorResponse.ErrorMessage)
+
+		_, errorResponse = postValidationTestBody(router, "/v1/parents/1/bodies", `{"title":"too long","points":101,"optional":"ok"}`)
+		require.Equal(t, "body.points: must be at most 100\nbody.title: must be at most 5 characters long", errorResponse.ErrorMessage)
+	})
+
+	testCase.Run("a missing, invalid or null body is rejected", func(t *testing.T) {
+		problems := []string{}
+		router, _ := newValidationTestRouter(answerCreated, &problems)
+
+		for body, expectedMessage := range map[string]string{
+			"":                               "body: is required",
+			`{"title":`:                      "body: is not valid JSON",
+			`{"title":"a"} {}`:               "body: is not valid JSON",
+			"null":                           "body: must not be null",
+			`["title"]`:                      "body: must be an object, got array",
+			`{"title":"a","optional":false}`: "body.optional: must be a string, got boolean",
+		} {
+			statusCode, errorResponse := postValidationTestBody(router, "/v1/parents/1/bodies", body)
+			require.Equal(t, http.StatusBadRequest, statusCode, body)
+			require.Equal(t, expectedMessage, errorResponse.ErrorMessage, body)
+		}
+	})
+
+	testCase.Run("a body larger than the limit is answered 413", func(t *testing.T) {
+		problems := []string{}
+		router, receivedBody := newValidationTestRouter(answerCreated, &problems)
+		body := `{"title":"fix"}`
+		body += strings.Repeat(" ", MAX_REQUEST_BODY_BYTES-len(body))
+
+		statusCode, _ := postValidationTestBody(router, "/v1/parents/1/bodies", body)
+		require.Equal(t, http.StatusOK, statusCode, "a body of the limit is read")
+		*receivedBody = ""
+		statusCode, errorResponse := postValidationTestBody(router, "/v1/parents/1/bodies", body+" ")
+		require.Equal(t, http.StatusRequestEntityTooLarge, statusCode)
+		require.Equal(t, "The request body exceeds 1048576 bytes", errorResponse.ErrorMessage)
+		require.Equal(t, "", *receivedBody, "The handler should not be called")
+		require.Empty(t, problems)
+	})
+
+	testCase.Run("the responses not matching the specification are reported", func(t *testing.T) {
+		problems := []string{}
+		router, _ := newValidationTestRouter(func(w http.ResponseWriter) {
+			w.Write([]byte(`{"id":1,"extra":"field"}`))
+		}, &problems)
+
+		responseRecorder := httptest.NewRecorder()
+		router.ServeHTTP(responseRecorder, httptest.NewRequest(http.MethodPost, "/v1/parents/1/bodies", strings.NewReader(`{"title":"a"}`)))
+		require.Equal(t, http.StatusOK, responseRecorder.Code)
+		require.Equal(t, `{"id":1,"extra":"field"}`, responseRecorder.Body.String(), "The response should be sent unchanged")
+		require.Equal(t, []string{"body.extra: is not a known field", "body.id: must be a string, got number"}, problems)
+
+		problems = problems[:0]
+		router, _ = newValidationTestRouter(func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusTeapot)
+		}, &problems)
+		statusCode, _ := postValidationTestBody(router, "/v1/parents/1/bodies", `{"title":"a"}`)
+		require.Equal(t, http.StatusTeapot, statusCode)
+		require.Equal(t, []string{"status: 418 is not documented"}, problems)
+	})
+}
@@ -0,0 +1,95 @@
+package internal
+
+import "strings"
+
+// rankDigits are the digits of the issue ranks, in their lexicographic order
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// rankHeadLength is the length of the head of the ranks incremented to rank an issue last,
+// the migration ranking the existing issues pads their ids to it
+const rankHeadLength = 12
+
+// rankRebalanceLength is the length past which the ranks of a project are rebalanced:
+// ranking issues again and again at the same place makes the ranks longer
+const rankRebalanceLength = 64
+
+// RankBetween returns a rank sorting after before and before after, the empty ranks standing for the ends of the ranking.
+// The ranks never end with the lowest digit, so that a rank always fits between two of them.
+func RankBetween(before string, after string) string {
+	if after == "" {
+		if rank, ok := incrementRankHead(before); ok {
+			return rank
+		}
+	}
+	return getMiddleRank(before, after)
+}
+
+// getMiddleRank returns the rank halfway between before and after, digit by digit
+func getMiddleRank(before string, after string) string {
+	getDigit := func(rank string, index int) int {
+		if index < len(rank) {
+			return strings.IndexByte(rankDigits, rank[index])
+		}
+		return 0
+	}
+
+	prefix := 0
+	for prefix < len(after) && getDigit(before, prefix) == getDigit(after, prefix) {
+		prefix++
+	}
+	if prefix > 0 {
+		remainder := ""
+		if prefix < len(before) {
+			remainder = before[prefix:]
+		}
+		return after[:prefix] + getMiddleRank(remainder, after[prefix:])
+	}
+
+	lowDigit, highDigit := getDigit(before, 0), len(rankDigits)
+	if after != "" {
+		highDigit = getDigit(after, 0)
+	}
+	if highDigit-lowDigit > 1 {
+		return string(rankDigits[(lowDigit+highDigit)/2])
+	}
+	if len(after) > 1 {
+		return after[:1]
+	}
+	remainder := ""
+	if len(before) > 1 {
+		remainder = before[1:]
+	}
+	return string(rankDigits[lowDigit]) + getMiddleRank(remainder, "")
+}
+
+// incrementRankHead returns the rank following the head of before, so that ranking issues last keeps the ranks short.
+// It returns false when the head has the greatest value.
+func incrementRankHead(before string) (string, bool) {
+	head := []byte(before)
+	if len(head) > rankHeadLength {
+		head = head[:rankHeadLength]
+	}
+	for len(head) < rankHeadLength {
+		head = append(head, rankDigits[0])
+	}
+	for index := len(head) - 1; index >= 0; index-- {
+		digit := strings.IndexByte(rankDigits, head[index])
+		if digit < len(rankDigits)-1 {
+			head[index] = rankDigits[digit+1]
+			return string(head) + string(rankDigits[len(rankDigits)/2]), true
+		}
+		head[index] = rankDigits[0]
+	}
+	return "", false
+}
+
+// getEvenRanks returns count ranks in order, as short as the ranks of the issues ranked last
+func getEvenRanks(count int) []string {
+	ranks := make([]string, 0, count)
+	rank := ""
+	for len(ranks) < count {
+		rank = RankBetween(rank, "")
+		ranks = append(ranks, rank)
+	}
+	return ranks
+}
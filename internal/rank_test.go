@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankBetween(testCase *testing.T) {
+	testCase.Run("the rank between two ranks", func(t *testing.T) {
+		require.Equal(t, "000000000001i", RankBetween("", ""))
+		require.Equal(t, "000000000013i", RankBetween("000000000012i", ""))
+		require.Equal(t, "i00000000001i", RankBetween("i", ""))
+		require.Equal(t, "zzzzzzzzzzzzr", RankBetween("zzzzzzzzzzzzi", ""))
+		require.Equal(t, "9", RankBetween("", "i"))
+		require.Equal(t, "1i", RankBetween("1", "2"))
+		require.Equal(t, "0000i", RankBetween("", "0001"))
+		require.Equal(t, "000000001", RankBetween("0000000009i", "0000000010i"))
+		require.Equal(t, "azi", RankBetween("az", "b"))
+	})
+
+	testCase.Run("the ranks stay ordered after many insertions", func(t *testing.T) {
+		random := rand.New(rand.NewSource(1))
+		ranks := []string{RankBetween("", "")}
+		for index := 0; index < 1000; index++ {
+			position := random.Intn(len(ranks) + 1)
+			before, after := "", ""
+			if position > 0 {
+				before = ranks[position-1]
+			}
+			if position < len(ranks) {
+				after = ranks[position]
+			}
+			rank := RankBetween(before, after)
+			require.True(t, before < rank && (after == "" || rank < after), "%q is not between %q and %q", rank, before, after)
+			require.NotEqual(t, byte('0'), rank[len(rank)-1])
+			ranks = append(ranks[:position], append([]string{rank}, ranks[position:]...)...)
+		}
+		require.True(t, sort.StringsAreSorted(ranks))
+	})
+
+	testCase.Run("the ranks of the issues ranked last keep their length", func(t *testing.T) {
+		rank := ""
+		for index := 0; index < 10000; index++ {
+			next := RankBetween(rank, "")
+			require.Less(t, rank, next)
+			rank = next
+		}
+		require.Equal(t, rankHeadLength+1, len(rank))
+	})
+}
@@ -1,38 +1,110 @@
 package internal
 
 import (
+	"context"
+	cryptoRand "crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"issue-service/app/issue-api/cfg"
 	models "issue-service/app/issue-api/routes/models"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func CreateTestProject(database *gorm.DB) uint {
+// TEST_DATABASE_DRIVER overrides the database driver of the configuration used by NewTestDatabase
+const TEST_DATABASE_DRIVER = "TEST_DATABASE_DRIVER"
+
+// NewTestDatabase returns a migrated database that only the calling test can see.
+// On postgres every test gets its own schema, on sqlite its own file,
+// both are removed when the test ends.
+func NewTestDatabase(t *testing.T, config cfg.EnvConfiguration) *gorm.DB {
+	t.Helper()
+	if driver := os.Getenv(TEST_DATABASE_DRIVER); driver != "" {
+		config.DATABASE_DRIVER = driver
+	}
+
+	var database *gorm.DB
+	switch getDatabaseDriver(config) {
+	case DRIVER_SQLITE:
+		config.DATABASE_NAME = filepath.Join(t.TempDir(), "test.db")
+		database = openTestDatabase(t, sqlite.Open(getSqliteConnectionString(config)))
+	case DRIVER_POSTGRES:
+		schema := getTestSchemaName(t)
+		administration := openTestDatabase(t, postgres.Open(getConnectionString(config)))
+		require.NoError(t, administration.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)).Error)
+		t.Cleanup(func() {
+			administration.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		})
+		database = openTestDatabase(t, postgres.Open(fmt.Sprintf("%s search_path=%s", getConnectionString(config), schema)))
+	default:
+		t.Fatalf("unsupported database driver \"%s\"", config.DATABASE_DRIVER)
+	}
+
+	_, err := MigrateUp(database)
+	require.NoError(t, err)
+	return database
+}
+
+// getTestSchemaName does not use math/rand, which is not seeded and
+// would give the same names to the test binaries of different packages
+func getTestSchemaName(t *testing.T) string {
+	suffix := make([]byte, 8)
+	_, err := cryptoRand.Read(suffix)
+	require.NoError(t, err)
+	return "test_" + hex.EncodeToString(suffix)
+}
+
+func openTestDatabase(t *testing.T, dialector gorm.Dialector) *gorm.DB {
+	database, err := gorm.Open(dialector, &gorm.Config{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if sqlDatabase, err := database.DB(); err == nil {
+			sqlDatabase.Close()
+		}
+	})
+	return database
+}
+
+func CreateTestProject(stores models.Stores) uint {
 	inputProject := models.Project{
 		Name:   GetRandomStringName(10),
 		Type:   "project-type",
 		Client: "project-client",
 	}
-	database.Create(&inputProject)
+	stores.Projects.Create(context.Background(), &inputProject)
 
 	return inputProject.ID
 }
-func CreateTestSprint(database *gorm.DB, sprintNumber string, projectId int) uint {
+
+// CreateTestSprint creates a sprint of a week, starting now or when the last sprint of the project ends
+func CreateTestSprint(stores models.Stores, sprintNumber string, projectId int) uint {
+	startDate := time.Now()
+	sprints, _ := stores.Sprints.ListByProject(context.Background(), projectId, models.Page{})
+	for _, sprint := range sprints {
+		if sprint.EndDate.After(startDate) {
+			startDate = sprint.EndDate
+		}
+	}
 	inputSprint := models.Sprint{
 		Number:    sprintNumber,
 		ProjectID: projectId,
-		StartDate: time.Now(),
-		EndDate:   time.Now().AddDate(0, 0, 7),
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 0, 7),
 		Completed: false,
 	}
-	database.Create(&inputSprint)
+	stores.Sprints.Create(context.Background(), &inputSprint)
 
 	return inputSprint.ID
 }
-func CreateTestIssue(database *gorm.DB, projectId int, sprintId int) uint {
+func CreateTestIssue(stores models.Stores, projectId int, sprintId int) uint {
 	inputIssue := models.Issue{
 		ProjectID:   projectId,
 		SprintID:    sprintId,
@@ -42,13 +114,13 @@ func CreateTestIssue(database *gorm.DB, projectId int, sprintId int) uint {
 		Status:      "To Do",
 		Assignee:    "Assignee",
 	}
-	database.Create(&inputIssue)
+	stores.Issues.Create(context.Background(), &inputIssue)
 
 	return inputIssue.ID
 }
-func CreateProjectAndSprint(database *gorm.DB) (projectId uint, sprintId uint) {
-	projectId = CreateTestProject(database)
-	sprintId = CreateTestSprint(database, "12345", int(projectId))
+func CreateProjectAndSprint(stores models.Stores) (projectId uint, sprintId uint) {
+	projectId = CreateTestProject(stores)
+	sprintId = CreateTestSprint(stores, "12345", int(projectId))
 
 	return
 }
@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"issue-service/app/issue-api/cfg"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/negroni"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	TRACING_EXPORTER_NONE   = "none"
+	TRACING_EXPORTER_STDOUT = "stdout"
+	TRACING_EXPORTER_OTLP   = "otlp"
+)
+
+var tracingExporters = []string{TRACING_EXPORTER_NONE, TRACING_EXPORTER_STDOUT, TRACING_EXPORTER_OTLP}
+
+const (
+	serviceName = "issue-service"
+	tracerName  = "issue-service"
+)
+
+// NewTracerProvider returns a tracer provider sending the spans to TRACING_EXPORTER.
+// With the none exporter the spans are still created, so that the logs and the responses carry a trace id.
+func NewTracerProvider(ctx context.Context, config cfg.EnvConfiguration) (*sdktrace.TracerProvider, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(Version),
+		)),
+	}
+
+	switch config.TRACING_EXPORTER {
+	case TRACING_EXPORTER_NONE:
+	case TRACING_EXPORTER_STDOUT:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("creating the stdout exporter: %w", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case TRACING_EXPORTER_OTLP:
+		exporterOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.TRACING_OTLP_ENDPOINT)}
+		if config.TRACING_OTLP_INSECURE {
+			exporterOptions = append(exporterOptions, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, exporterOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("creating the otlp exporter: %w", err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter \"%s\"", config.TRACING_EXPORTER)
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// SetGlobalTracing makes Trace and TracingPlugin use provider, and propagate the W3C trace context
+func SetGlobalTracing(provider trace.TracerProvider) {
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Trace starts a server span named after the route, child of the traceparent header of the request.
+// The traceparent of the span is sent back in the response headers.
+func Trace(inner http.Handler, routeName string, pattern string) http.Handler {
+	tracer := otel.Tracer(tracerName)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		propagator := otel.GetTextMapPropagator()
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, routeName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(serviceName, pattern, r)...),
+		)
+		defer span.End()
+
+		propagator.Inject(ctx, propagation.HeaderCarrier(w.Header()))
+		responseWriter := negroni.NewResponseWriter(w)
+
+		inner.ServeHTTP(responseWriter, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(responseWriter.Status())...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(responseWriter.Status(), trace.SpanKindServer))
+	})
+}
+
+// TracingPlugin is a GORM plugin starting a client span for every query,
+// child of the span in the context of the statement
+type TracingPlugin struct{}
+
+const tracingSpanKey = "tracing:span"
+
+func (plugin *TracingPlugin) Name() string {
+	return "tracing"
+}
+
+func (plugin *TracingPlugin) Initialize(database *gorm.DB) error {
+	tracer := otel.Tracer(tracerName)
+	callbacks := database.Callback()
+	errs := []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", startQuerySpan(tracer, "gorm.Create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", endQuerySpan),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", startQuerySpan(tracer, "gorm.Query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", endQuerySpan),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", startQuerySpan(tracer, "gorm.Update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", endQuerySpan),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", startQuerySpan(tracer, "gorm.Delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", endQuerySpan),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", startQuerySpan(tracer, "gorm.Row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", endQuerySpan),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startQuerySpan(tracer, "gorm.Raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endQuerySpan),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuerySpan(tracer trace.Tracer, spanName string) func(*gorm.DB) {
+	return func(database *gorm.DB) {
+		ctx := database.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		_, span := tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindClient))
+		database.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+func endQuerySpan(database *gorm.DB) {
+	value, ok := database.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		getDatabaseSystem(database.Dialector.Name()),
+		semconv.DBStatementKey.String(database.Statement.SQL.String()),
+		semconv.DBSQLTableKey.String(database.Statement.Table),
+		attribute.Int64("db.rows_affected", database.Statement.RowsAffected),
+	)
+	if database.Error != nil && !errors.Is(database.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(database.Error)
+		span.SetStatus(codes.Error, database.Error.Error())
+	}
+}
+
+func getDatabaseSystem(dialectorName string) attribute.KeyValue {
+	if dialectorName == DRIVER_SQLITE {
+		return semconv.DBSystemSqlite
+	}
+	return semconv.DBSystemPostgreSQL
+}
+
+// TraceHook adds the trace and span ids to the entries logged with a context holding a span
+type TraceHook struct{}
+
+func (hook TraceHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (hook TraceHook) Fire(entry *log.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	spanContext := trace.SpanContextFromContext(entry.Context)
+	if spanContext.IsValid() {
+		entry.Data["trace_id"] = spanContext.TraceID().String()
+		entry.Data["span_id"] = spanContext.SpanID().String()
+	}
+	return nil
+}
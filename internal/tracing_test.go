@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"issue-service/app/issue-api/cfg"
+	models "issue-service/app/issue-api/routes/models"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	spanRecorder     = tracetest.NewSpanRecorder()
+	installRecorder  sync.Once
+	testTraceParent  = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	testTraceId      = "0af7651916cd43dd8448eb211c80319c"
+	testParentSpanId = "b7ad6b7169203331"
+)
+
+// recordSpans installs a global tracer provider recording every span of the package tests
+func recordSpans() {
+	installRecorder.Do(func() {
+		SetGlobalTracing(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+}
+
+// getEndedSpans returns the ended spans of the trace, the tests running in parallel
+func getEndedSpans(traceId trace.TraceID) []sdktrace.ReadOnlySpan {
+	spans := []sdktrace.ReadOnlySpan{}
+	for _, span := range spanRecorder.Ended() {
+		if span.SpanContext().TraceID() == traceId {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func getAttribute(span sdktrace.ReadOnlySpan, key string) interface{} {
+	for _, attribute := range span.Attributes() {
+		if string(attribute.Key) == key {
+			return attribute.Value.AsInterface()
+		}
+	}
+	return nil
+}
+
+func TestNewTracerProvider(testCase *testing.T) {
+	for _, exporter := range []string{TRACING_EXPORTER_NONE, TRACING_EXPORTER_STDOUT, TRACING_EXPORTER_OTLP} {
+		exporter := exporter
+		testCase.Run(exporter, func(t *testing.T) {
+			provider, err := NewTracerProvider(context.Background(), cfg.EnvConfiguration{
+				TRACING_EXPORTER:      exporter,
+				TRACING_OTLP_ENDPOINT: "localhost:4318",
+				TRACING_OTLP_INSECURE: true,
+			})
+			require.NoError(t, err)
+			require.NoError(t, provider.Shutdown(context.Background()))
+		})
+	}
+
+	testCase.Run("unknown exporter", func(t *testing.T) {
+		_, err := NewTracerProvider(context.Background(), cfg.EnvConfiguration{TRACING_EXPORTER: "zipkin"})
+		require.EqualError(t, err, "unknown tracing exporter \"zipkin\"")
+	})
+}
+
+func TestTrace(testCase *testing.T) {
+	recordSpans()
+	traceId, _ := trace.TraceIDFromHex(testTraceId)
+
+	testCase.Run("the server span continues the trace of the traceparent header", func(t *testing.T) {
+		handler := Trace(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, testTraceId, trace.SpanContextFromContext(r.Context()).TraceID().String())
+			w.WriteHeader(http.StatusInternalServerError)
+		}), "GetIssue", "/v1/projects/{projectId}/issues/{issueId}")
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/projects/1/issues/2", nil)
+		request.Header.Set("traceparent", testTraceParent)
+		responseRecorder := httptest.NewRecorder()
+		handler.ServeHTTP(responseRecorder, request)
+
+		spans := getEndedSpans(traceId)
+		require.Equal(t, 1, len(spans))
+		require.Equal(t, "GetIssue", spans[0].Name())
+		require.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+		require.Equal(t, testParentSpanId, spans[0].Parent().SpanID().String())
+		require.Equal(t, "/v1/projects/{projectId}/issues/{issueId}", getAttribute(spans[0], "http.route"))
+		require.Equal(t, int64(500), getAttribute(spans[0], "http.status_code"))
+		require.Equal(t, codes.Error, spans[0].Status().Code)
+		require.Contains(t, responseRecorder.Header().Get("traceparent"), testTraceId)
+	})
+
+	testCase.Run("every query is a child span", func(t *testing.T) {
+		database := connectTestSqliteDatabase(t)
+		_, err := MigrateUp(database)
+		require.NoError(t, err)
+
+		parentContext := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceId,
+			SpanID:     trace.SpanID{1},
+			TraceFlags: trace.FlagsSampled,
+		}))
+		projects := []models.Project{}
+		require.NoError(t, database.WithContext(parentContext).Find(&projects).Error)
+
+		var querySpan sdktrace.ReadOnlySpan
+		for _, span := range getEndedSpans(traceId) {
+			if span.Name() == "gorm.Query" && span.Parent().SpanID() == (trace.SpanID{1}) {
+				querySpan = span
+			}
+		}
+		require.NotNil(t, querySpan)
+		require.Equal(t, trace.SpanKindClient, querySpan.SpanKind())
+		require.Equal(t, "sqlite", getAttribute(querySpan, "db.system"))
+		require.Equal(t, "projects", getAttribute(querySpan, "db.sql.table"))
+		require.Contains(t, getAttribute(querySpan, "db.statement"), "SELECT * FROM `projects`")
+	})
+}
+
+func TestTraceHook(t *testing.T) {
+	recordSpans()
+	var out bytes.Buffer
+	logger := log.New()
+	logger.SetFormatter(&log.JSONFormatter{})
+	logger.SetOutput(&out)
+	logger.AddHook(TraceHook{})
+
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "test")
+	logger.WithContext(ctx).Info("in a span")
+	span.End()
+	logger.Info("without a span")
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Equal(t, 2, len(lines))
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &entry))
+	require.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+	require.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+	entry = map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(lines[1], &entry))
+	require.NotContains(t, entry, "trace_id")
+}